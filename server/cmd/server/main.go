@@ -1,7 +1,13 @@
+// Command server runs the TaskFuss API. Build it with the sqlite_fts5 tag, without it search
+// scans every row instead of using the full-text index:
+//
+//	go build -tags sqlite_fts5 ./cmd/server
 package main
 
 import (
 	"os"
+	"time"
+	_ "time/tzdata" // Time zones of users do not depend on the zoneinfo of the host
 
 	// "github.com/boreymarf/task-fuss/server/internal/config"
 	"github.com/boreymarf/task-fuss/server/internal/db"
@@ -73,7 +79,7 @@ func main() {
 	r.Use(cors.New(cors.Config{
 		AllowAllOrigins: true,
 		// AllowOrigins:     []string{"http://localhost:5173", "http://192.168.1.82:5173"},
-		AllowMethods:     []string{"GET", "POST"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE"},
 		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
 		ExposeHeaders:    []string{"Content-Length"},
 		AllowCredentials: true,
@@ -105,24 +111,47 @@ func main() {
 		logger.Log.Fatal().Err(err).Msg("Unable to initialize requirement entry repository")
 	}
 
+	tagRepository, err := db.InitTagRepository(database)
+	if err != nil {
+		logger.Log.Fatal().Err(err).Msg("Unable to initialize tag repository")
+	}
+
+	// The search index is built on the tables above, so it is initialized after them
+	searchRepository, err := db.InitSearchRepository(database)
+	if err != nil {
+		logger.Log.Fatal().Err(err).Msg("Unable to initialize search repository")
+	}
+
+	unitOfWork, err := db.InitUnitOfWork(database)
+	if err != nil {
+		logger.Log.Fatal().Err(err).Msg("Unable to initialize unit of work")
+	}
+
 	// Services
 	taskService, err := service.InitTaskService(
+		unitOfWork,
+		userRepository,
 		taskRepository,
 		taskEntryRepository,
 		requirementRepository,
 		requirementEntryRepository,
+		tagRepository,
+		searchRepository,
 	)
 	if err != nil {
 		logger.Log.Fatal().Err(err).Msg("Unable to initialize task service repository")
 	}
 
+	// Tasks deleted more than service.TrashRetention ago are removed for good
+	go taskService.PurgeTrashEvery(24 * time.Hour)
+
 	// Handlers
 	authHandler, err := handlers.InitAuthHandler(userRepository)
 	if err != nil {
 		logger.Log.Fatal().Err(err).Msg("Unable to initialize auth handler")
 	}
 
-	profileHandler, err := handlers.InitProfileHandler(userRepository)
+	profileHandler, err := handlers.InitProfileHandler(userRepository, taskService)
 	if err != nil {
 		logger.Log.Fatal().Err(err).Msg("Unable to initialize profile handler")
 	}
@@ -139,7 +168,17 @@ func main() {
 		logger.Log.Fatal().Err(err).Msg("Unable to initialize task handler")
 	}
 
-	routes.SetupAPIRoutes(r, userRepository, authHandler, profileHandler, taskHandler)
+	entriesHandler, err := handlers.InitEntriesHandler(taskService)
+	if err != nil {
+		logger.Log.Fatal().Err(err).Msg("Unable to initialize entries handler")
+	}
+
+	tagHandler, err := handlers.InitTagHandler(taskService)
+	if err != nil {
+		logger.Log.Fatal().Err(err).Msg("Unable to initialize tag handler")
+	}
+
+	routes.SetupAPIRoutes(r, userRepository, authHandler, profileHandler, taskHandler, entriesHandler, tagHandler)
 
 	// Initializing
 	port := os.Getenv("PORT")
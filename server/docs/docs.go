@@ -15,6 +15,64 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/agenda": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the active tasks due on the date with their requirement tree annotated with the values\nlogged so far and whether every node is met, pending or failed. GET /today is the agenda of today.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "agenda"
+                ],
+                "summary": "Get the tasks due on a day",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date in YYYY-MM-DD format (default: today in the time zone of the user)",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tasks due on the date ordered by ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetAgendaResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid date",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    }
+                }
+            }
+        },
         "/auth/login": {
             "post": {
                 "description": "Authenticate user credentials and return a JWT token",
@@ -113,41 +171,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/ping": {
-            "get": {
-                "description": "Returns \"pong\" if the server is running",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "service"
-                ],
-                "summary": "Server health check",
-                "responses": {
-                    "200": {
-                        "description": "Server is running",
-                        "schema": {
-                            "$ref": "#/definitions/dto.PongResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/profile": {
-            "get": {
+        "/checkin": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves the authenticated user's profile information",
+                "description": "Sets values of any number of requirements for one day in a single transaction. A value replaces the latest one logged that day for a requirement without aggregation and is added to the day for sums, counts and extremes, other requirements keep theirs. Returns the re-evaluated status of each affected task",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "profile"
+                    "entries"
                 ],
-                "summary": "Get user profile",
+                "summary": "Log many values for a day at once",
                 "parameters": [
                     {
                         "type": "string",
@@ -155,29 +196,50 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "description": "Date and values by requirement ID",
+                        "name": "CheckInRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CheckInRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Profile retrieved successfully",
+                        "description": "Status of the affected tasks",
                         "schema": {
-                            "$ref": "#/definitions/dto.ProfileResponse"
+                            "$ref": "#/definitions/dto.CheckInResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or values (code: VALIDATION_FAILED)",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized (code: UNAUTHORIZED)",
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "A requirement belongs to another user",
                         "schema": {
                             "$ref": "#/definitions/api.Error"
                         }
                     },
                     "404": {
-                        "description": "Profile not found (code: PROFILE_NOT_FOUND)",
+                        "description": "A requirement was not found",
                         "schema": {
                             "$ref": "#/definitions/api.Error"
                         }
                     },
                     "500": {
-                        "description": "Internal server error (code: INTERNAL_ERROR)",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/api.Error"
                         }
@@ -185,21 +247,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/tasks": {
+        "/entries/{entry_id}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves tasks based on filter criteria (active/archived/completed) and detail level",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
+                    "entries"
                 ],
-                "summary": "Get all tasks with filtering options",
+                "summary": "Get an entry by ID",
                 "parameters": [
                     {
                         "type": "string",
@@ -209,44 +270,22 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "enum": [
-                            "minimal",
-                            "standard",
-                            "full"
-                        ],
-                        "type": "string",
-                        "description": "Detail level",
-                        "name": "detailLevel",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Include active tasks (default: true)",
-                        "name": "showActive",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Include archived tasks (default: false)",
-                        "name": "showArchived",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Include completed tasks (default: true)",
-                        "name": "showCompleted",
-                        "in": "query"
+                        "type": "integer",
+                        "description": "Entry ID",
+                        "name": "entry_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of tasks",
+                        "description": "Entry",
                         "schema": {
-                            "$ref": "#/definitions/dto.GetAllTasksResponse"
+                            "$ref": "#/definitions/dto.EntryResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid query parameters",
+                        "description": "Invalid entry ID",
                         "schema": {
                             "$ref": "#/definitions/api.Error"
                         }
@@ -257,6 +296,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.Error"
                         }
                     },
+                    "403": {
+                        "description": "Entry belongs to another user",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Entry not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -265,13 +316,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Create a new task for the authenticated user",
+                "description": "Changes the value or the date of the entry and re-evaluates the task for the affected days",
                 "consumes": [
                     "application/json"
                 ],
@@ -279,9 +330,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
+                    "entries"
                 ],
-                "summary": "Create a new task",
+                "summary": "Update an entry",
                 "parameters": [
                     {
                         "type": "string",
@@ -291,24 +342,31 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Task creation data",
-                        "name": "CreateTaskRequest",
+                        "type": "integer",
+                        "description": "Entry ID",
+                        "name": "entry_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "UpdateEntryRequest",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateTaskRequest"
+                            "$ref": "#/definitions/dto.UpdateEntryRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Task successfully created",
+                    "200": {
+                        "description": "Entry updated",
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateTaskResponse"
+                            "$ref": "#/definitions/dto.EntryChangeResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request format",
+                        "description": "Invalid request or value does not match the data type (code: VALIDATION_FAILED)",
                         "schema": {
                             "$ref": "#/definitions/api.Error"
                         }
@@ -319,6 +377,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.Error"
                         }
                     },
+                    "403": {
+                        "description": "Entry belongs to another user",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Entry not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -326,23 +396,21 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/tasks/{task_id}": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves a single task by its unique identifier",
+                "description": "Deletes the entry and re-evaluates the task for its day",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
+                    "entries"
                 ],
-                "summary": "Get a task by ID",
+                "summary": "Delete an entry",
                 "parameters": [
                     {
                         "type": "string",
@@ -352,23 +420,22 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Task ID",
-                        "name": "task_id",
+                        "type": "integer",
+                        "description": "Entry ID",
+                        "name": "entry_id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Task details",
+                        "description": "Entry deleted",
                         "schema": {
-                            "$ref": "#/definitions/dto.GetTaskByIDResponse"
+                            "$ref": "#/definitions/dto.EntryChangeResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid task ID format",
+                        "description": "Invalid entry ID",
                         "schema": {
                             "$ref": "#/definitions/api.Error"
                         }
@@ -379,8 +446,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.Error"
                         }
                     },
+                    "403": {
+                        "description": "Entry belongs to another user",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
                     "404": {
-                        "description": "Task not found",
+                        "description": "Entry not found",
                         "schema": {
                             "$ref": "#/definitions/api.Error"
                         }
@@ -393,202 +466,3358 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "api.Error": {
-            "description": "Common error response structure for API failures",
-            "type": "object",
-            "properties": {
-                "code": {
-                    "type": "string",
-                    "example": "UPPERCASE_CODE"
-                },
-                "details": {},
-                "message": {
-                    "type": "string",
-                    "example": "Brief message about the error."
-                }
-            }
         },
-        "dto.CreateTaskRequest": {
-            "type": "object",
-            "properties": {
-                "task": {
-                    "$ref": "#/definitions/dto.Task"
+        "/expressions/format": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Turns a requirement tree into its canonical text expression",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "requirements"
+                ],
+                "summary": "Format a requirement tree",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Requirement tree",
+                        "name": "FormatExpressionRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.FormatExpressionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Canonical expression",
+                        "schema": {
+                            "$ref": "#/definitions/dto.FormatExpressionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request format",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    }
                 }
             }
         },
-        "dto.CreateTaskResponse": {
-            "type": "object",
-            "properties": {
-                "task": {
-                    "$ref": "#/definitions/dto.Task"
+        "/expressions/parse": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Turns a text expression like \"pushups \u003e= 50 and not skipped\" into a requirement tree",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "requirements"
+                ],
+                "summary": "Parse a requirement expression",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Expression",
+                        "name": "ParseExpressionRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ParseExpressionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Requirement tree",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ParseExpressionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Syntax error with line and column in details (code: INVALID_EXPRESSION)",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/history": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns for every day of the range how many active tasks were due, completed, failed and skipped\nwith their average score, shaped for heatmaps and monthly calendars. Days after today are left out.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "history"
+                ],
+                "summary": "Get the history of all tasks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start of the range, YYYY-MM-DD or RFC 3339 (inclusive, default: a year before the end)",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the range, YYYY-MM-DD (inclusive) or RFC 3339 (exclusive), default: today",
+                        "name": "end",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Days of the range",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid range",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/ping": {
+            "get": {
+                "description": "Returns \"pong\" if the server is running",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "service"
+                ],
+                "summary": "Server health check",
+                "responses": {
+                    "200": {
+                        "description": "Server is running",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PongResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves the authenticated user's profile information",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profile"
+                ],
+                "summary": "Get user profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Profile retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProfileResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized (code: UNAUTHORIZED)",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Profile not found (code: PROFILE_NOT_FOUND)",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error (code: INTERNAL_ERROR)",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/settings": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Changes the settings of the authenticated user, omitted fields are left as they are.\nA new time zone or day end hour moves logged entries to other days, their outcomes are evaluated again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profile"
+                ],
+                "summary": "Update user settings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "New settings",
+                        "name": "UpdateSettingsRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateSettingsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Settings updated",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateSettingsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request format or time zone (code: VALIDATION_FAILED)",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/requirements/{requirement_id}/entries": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns entries of the requirement logged between start and end, dates are days in the user's time zone that end at their day end hour",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "entries"
+                ],
+                "summary": "Get entries of a requirement",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Requirement ID",
+                        "name": "requirement_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start of the range, YYYY-MM-DD or RFC 3339 (inclusive)",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the range, YYYY-MM-DD (inclusive) or RFC 3339 (exclusive)",
+                        "name": "end",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of entries",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetEntriesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Requirement belongs to another user",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Requirement not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a new entry for the requirement and re-evaluates the task for the day of the entry",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "entries"
+                ],
+                "summary": "Log a value for a requirement",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Requirement ID",
+                        "name": "requirement_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Entry data",
+                        "name": "CreateEntryRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateEntryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Entry created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.EntryChangeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or value does not match the data type (code: VALIDATION_FAILED)",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Requirement belongs to another user",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Requirement not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/requirements/{requirement_id}/trend": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the values logged for an int, float, duration or rating requirement by day, in its unit\nand durations in seconds, with a moving average, personal bests and week over week changes.\nPersonal bests follow the target, lower is better for \"run_time \u003c= 25m\". Days after today are left out.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "entries"
+                ],
+                "summary": "Get the trend of a requirement",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Requirement ID",
+                        "name": "requirement_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start of the range, YYYY-MM-DD or RFC 3339 (inclusive, default: 90 days before the end)",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the range, YYYY-MM-DD (inclusive) or RFC 3339 (exclusive), default: today",
+                        "name": "end",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Days of the moving average (default: 7, max: 365)",
+                        "name": "window",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Values by day and week",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetTrendResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query parameters or the requirement is not numeric (code: VALIDATION_FAILED)",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Requirement belongs to another user",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Requirement not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/search": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Finds tasks by the words in their title or description, requirement titles and notes of entries.\nEvery word has to match, words also match longer words they are the beginning of. The best matches come first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "Search tasks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Words to search for",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of results (default: 20, max: 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Matches with highlighted snippets",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SearchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns every tag of the user ordered by name",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "List tags",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tags",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetTagsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a tag that can be put on tasks, names are unique per user regardless of case",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Create a tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Tag data",
+                        "name": "CreateTagRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateTagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created tag",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TagResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request format, name or color (code: VALIDATION_FAILED)",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/{tag_id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Renames or recolors the tag, the tasks keep it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Update a tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Tag ID",
+                        "name": "tag_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "UpdateTagRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateTagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated tag",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TagResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request format, name or color (code: VALIDATION_FAILED)",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Tag belongs to another user",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Tag not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes the tag and removes it from every task, the tasks themselves are kept",
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Delete a tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Tag ID",
+                        "name": "tag_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Tag deleted"
+                    },
+                    "400": {
+                        "description": "Invalid tag ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Tag belongs to another user",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Tag not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves a page of tasks based on filter criteria (active/archived/completed) and detail level.\nPass next_cursor or prev_cursor of the response as cursor with the same sort and order to get the neighbouring page.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Get all tasks with filtering options",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "minimal",
+                            "basic",
+                            "full"
+                        ],
+                        "type": "string",
+                        "description": "Detail level",
+                        "name": "detail",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include active tasks (default: true)",
+                        "name": "active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include archived tasks (default: false)",
+                        "name": "archived",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include completed tasks (default: true)",
+                        "name": "completed",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include streaks and completion metrics (default: false)",
+                        "name": "stats",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of days the completion rate is calculated over (default: 30)",
+                        "name": "window",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only tasks scheduled for the date in YYYY-MM-DD format",
+                        "name": "due",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated tag IDs, only tasks with these tags",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "Whether tasks need any or all of the tags (default: any)",
+                        "name": "tag_match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated tag IDs, tasks with any of these tags are left out",
+                        "name": "exclude_tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only tasks starting on or after the date in YYYY-MM-DD format",
+                        "name": "start_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only tasks starting on or before the date in YYYY-MM-DD format",
+                        "name": "start_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only tasks ending on or after the date in YYYY-MM-DD format, tasks without an end date are included",
+                        "name": "end_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only tasks ending on or before the date in YYYY-MM-DD format, tasks without an end date are left out",
+                        "name": "end_to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "title",
+                            "created_at",
+                            "start_date",
+                            "streak"
+                        ],
+                        "type": "string",
+                        "description": "Sort key, ties are broken by ID (default: created_at). streak is not indexed, every task is read and sorted for each page, like with due",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Sort order (default: asc)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default: 50, max: 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor or prev_cursor of an earlier page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of tasks",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetAllTasksResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a new task for the authenticated user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Create a new task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Task creation data",
+                        "name": "CreateTaskRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateTaskRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Task successfully created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateTaskResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request format, requirement tree (code: VALIDATION_FAILED) or expression (code: INVALID_EXPRESSION)",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{task_id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves a single task by its unique identifier",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Get a task by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "task_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include streaks and completion metrics (default: false)",
+                        "name": "stats",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of days the completion rate is calculated over (default: 30)",
+                        "name": "window",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Task details",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetTaskByIDResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid task ID format",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Task belongs to another user",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Changes the fields that are set and replaces the requirement tree if one is given.\nThe new tree is a revision effective from effective_from, days before it keep their outcomes.\nRequirements that keep their ID are updated in place with their logged values, requirements without one are created and missing ones are removed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Update a task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "task_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "UpdateTaskRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateTaskRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated task",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateTaskResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request format, fields (code: VALIDATION_FAILED) or expression (code: INVALID_EXPRESSION)",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Task belongs to another user",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Moves the task to the trash, it can be restored until it is purged 30 days later",
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Delete a task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "task_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Task moved to the trash"
+                    },
+                    "400": {
+                        "description": "Invalid task ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Task belongs to another user",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{task_id}/archive": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Hides an active or completed task from the default task list, archiving an archived task does nothing",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Archive a task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "task_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Archived task",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateTaskResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid task ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Task belongs to another user",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{task_id}/complete": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Marks an active or archived task as done for good, use PUT /tasks/{task_id} with status 'active' to reopen it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Mark a task as completed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "task_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Completed task",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateTaskResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid task ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Task belongs to another user",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{task_id}/days": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns whether the task was completed and its partial credit score for every day something was logged on",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Get daily outcomes of a task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "task_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start of the range, YYYY-MM-DD or RFC 3339 (inclusive)",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the range, YYYY-MM-DD (inclusive) or RFC 3339 (exclusive)",
+                        "name": "end",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Outcomes ordered by date",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetTaskDaysResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid task ID or range",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Task belongs to another user",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{task_id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the state of the task for every day of the range: completed, failed, skipped, not_due\nor pending for today, with the score of the day. Days after today are left out.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "history"
+                ],
+                "summary": "Get the history of a task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "task_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start of the range, YYYY-MM-DD or RFC 3339 (inclusive, default: a year before the end)",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the range, YYYY-MM-DD (inclusive) or RFC 3339 (exclusive), default: today",
+                        "name": "end",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Days of the range",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid task ID or range",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Task belongs to another user",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{task_id}/revisions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns every version of the requirement tree with the days it is used for, oldest first.\nDays are always evaluated with the revision that was in effect on them.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Get the revision history of a task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "task_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Revisions ordered by the day they take effect",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetTaskRevisionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid task ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Task belongs to another user",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{task_id}/status": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Evaluates the requirement tree of the task against the values logged on the given date",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Evaluate a task for a day",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "task_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date in YYYY-MM-DD format (default: today in the time zone of the user)",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Task status for the day",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetTaskStatusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid task ID or date",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Task belongs to another user",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{task_id}/unarchive": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Makes an archived task active again",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Unarchive a task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "task_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Active task",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateTaskResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid task ID or the task is completed (code: VALIDATION_FAILED)",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Task belongs to another user",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/today": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the active tasks due on the date with their requirement tree annotated with the values\nlogged so far and whether every node is met, pending or failed. GET /today is the agenda of today.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "agenda"
+                ],
+                "summary": "Get the tasks due on a day",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date in YYYY-MM-DD format (default: today in the time zone of the user)",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tasks due on the date ordered by ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetAgendaResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid date",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the tasks in the trash, the most recently deleted first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "List deleted tasks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Deleted tasks",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetTrashResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash/{task_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes a task in the trash with its requirements and everything logged for it without waiting for the retention period",
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Delete a task for good",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "task_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Task deleted"
+                    },
+                    "400": {
+                        "description": "Invalid task ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Task is not in the trash",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash/{task_id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Takes the task out of the trash with everything logged for it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Restore a deleted task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "task_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Restored task",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateTaskResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid task ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Task is not in the trash",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Error"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "api.Error": {
+            "description": "Common error response structure for API failures",
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "UPPERCASE_CODE"
+                },
+                "details": {},
+                "message": {
+                    "type": "string",
+                    "example": "Brief message about the error."
+                }
+            }
+        },
+        "dto.AgendaRequirement": {
+            "type": "object",
+            "properties": {
+                "aggregation": {
+                    "type": "string"
+                },
+                "data_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "operands": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AgendaRequirement"
+                    }
+                },
+                "operator": {
+                    "type": "string"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "passed": {
+                    "type": "boolean"
+                },
+                "period": {
+                    "type": "string"
+                },
+                "period_days": {
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "number",
+                    "example": 0.9
+                },
+                "score_mode": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string",
+                    "enum": [
+                        "met",
+                        "pending",
+                        "failed"
+                    ]
+                },
+                "target_value": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "value": {
+                    "description": "Logged so far in the period of the atom, null if nothing was logged",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AgendaTask": {
+            "type": "object",
+            "properties": {
+                "completed": {
+                    "type": "boolean"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "recurrence": {
+                    "type": "string"
+                },
+                "requirement": {
+                    "$ref": "#/definitions/dto.AgendaRequirement"
+                },
+                "score": {
+                    "type": "number",
+                    "example": 0.9
+                },
+                "state": {
+                    "type": "string",
+                    "enum": [
+                        "met",
+                        "pending",
+                        "failed"
+                    ]
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Tag"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CheckInRequest": {
+            "type": "object",
+            "required": [
+                "values"
+            ],
+            "properties": {
+                "date": {
+                    "description": "Default: today",
+                    "type": "string",
+                    "example": "2025-01-31"
+                },
+                "values": {
+                    "description": "Requirement ID to value, only these requirements are changed",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.CheckInResponse": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string",
+                    "example": "2025-01-31"
+                },
+                "tasks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TaskStatus"
+                    }
+                }
+            }
+        },
+        "dto.CreateEntryRequest": {
+            "type": "object",
+            "properties": {
+                "entry_date": {
+                    "description": "Default: now",
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string",
+                    "example": "Felt easy, try a longer route"
+                },
+                "value": {
+                    "description": "Values of requirements with a unit can be followed by any compatible unit, durations are written like target values",
+                    "type": "string",
+                    "example": "3.1 mi"
+                }
+            }
+        },
+        "dto.CreateTagRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "color": {
+                    "description": "#rrggbb",
+                    "type": "string",
+                    "example": "#4caf50"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "health"
+                }
+            }
+        },
+        "dto.CreateTaskRequest": {
+            "type": "object",
+            "properties": {
+                "task": {
+                    "$ref": "#/definitions/dto.Task"
+                }
+            }
+        },
+        "dto.CreateTaskResponse": {
+            "type": "object",
+            "properties": {
+                "task": {
+                    "$ref": "#/definitions/dto.Task"
+                }
+            }
+        },
+        "dto.EntryChangeResponse": {
+            "type": "object",
+            "properties": {
+                "entry": {
+                    "$ref": "#/definitions/dto.RequirementEntry"
+                },
+                "task_status": {
+                    "$ref": "#/definitions/dto.TaskStatus"
+                }
+            }
+        },
+        "dto.EntryResponse": {
+            "type": "object",
+            "properties": {
+                "entry": {
+                    "$ref": "#/definitions/dto.RequirementEntry"
+                }
+            }
+        },
+        "dto.FormatExpressionRequest": {
+            "type": "object",
+            "properties": {
+                "requirement": {
+                    "$ref": "#/definitions/dto.Requirement"
+                }
+            }
+        },
+        "dto.FormatExpressionResponse": {
+            "type": "object",
+            "properties": {
+                "expression": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.GetAgendaResponse": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string",
+                    "example": "2025-01-31"
+                },
+                "tasks": {
+                    "description": "Tasks due on the date",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AgendaTask"
+                    }
+                }
+            }
+        },
+        "dto.GetAllTasksResponse": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "description": "Null on the last page",
+                    "type": "string"
+                },
+                "prev_cursor": {
+                    "description": "Null on the first page",
+                    "type": "string"
+                },
+                "tasks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Task"
+                    }
+                },
+                "total": {
+                    "description": "Tasks matching the filters on all pages",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.GetEntriesResponse": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.RequirementEntry"
+                    }
+                }
+            }
+        },
+        "dto.GetHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "description": "Every day of the range up to today, oldest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.HistoryDay"
+                    }
+                },
+                "summary": {
+                    "$ref": "#/definitions/dto.HistorySummary"
+                }
+            }
+        },
+        "dto.GetTagsResponse": {
+            "type": "object",
+            "properties": {
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Tag"
+                    }
+                }
+            }
+        },
+        "dto.GetTaskByIDResponse": {
+            "type": "object",
+            "properties": {
+                "task": {
+                    "$ref": "#/definitions/dto.Task"
+                }
+            }
+        },
+        "dto.GetTaskDaysResponse": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TaskDay"
+                    }
+                }
+            }
+        },
+        "dto.GetTaskRevisionsResponse": {
+            "type": "object",
+            "properties": {
+                "revisions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.RequirementRevision"
+                    }
+                }
+            }
+        },
+        "dto.GetTaskStatusResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "$ref": "#/definitions/dto.TaskStatus"
+                }
+            }
+        },
+        "dto.GetTrashResponse": {
+            "type": "object",
+            "properties": {
+                "tasks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TrashedTask"
+                    }
+                }
+            }
+        },
+        "dto.GetTrendResponse": {
+            "type": "object",
+            "properties": {
+                "data_type": {
+                    "type": "string",
+                    "enum": [
+                        "int",
+                        "float",
+                        "duration",
+                        "rating"
+                    ]
+                },
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TrendDay"
+                    }
+                },
+                "direction": {
+                    "description": "Which values are better, from the target of the requirement",
+                    "type": "string",
+                    "enum": [
+                        "higher",
+                        "lower"
+                    ]
+                },
+                "requirement_id": {
+                    "type": "integer"
+                },
+                "summary": {
+                    "$ref": "#/definitions/dto.TrendSummary"
+                },
+                "unit": {
+                    "type": "string",
+                    "example": "km"
+                },
+                "weeks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TrendWeek"
+                    }
+                },
+                "window": {
+                    "description": "Days of the moving average",
+                    "type": "integer",
+                    "example": 7
+                }
+            }
+        },
+        "dto.HistoryDay": {
+            "type": "object",
+            "properties": {
+                "completed": {
+                    "type": "integer"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2025-01-31"
+                },
+                "due": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "description": "Something was logged but not enough",
+                    "type": "integer"
+                },
+                "pending": {
+                    "description": "Today, not completed yet",
+                    "type": "integer"
+                },
+                "score": {
+                    "description": "Average score of the due tasks, skipped days score 0",
+                    "type": "number",
+                    "example": 0.75
+                },
+                "skipped": {
+                    "description": "Nothing was logged",
+                    "type": "integer"
+                },
+                "state": {
+                    "description": "Only in the history of a single task",
+                    "type": "string",
+                    "enum": [
+                        "completed",
+                        "failed",
+                        "skipped",
+                        "not_due",
+                        "pending"
+                    ]
+                }
+            }
+        },
+        "dto.HistorySummary": {
+            "type": "object",
+            "properties": {
+                "completed": {
+                    "type": "integer"
+                },
+                "completion_rate": {
+                    "description": "Share of the due days that are over and were completed",
+                    "type": "number",
+                    "example": 0.8
+                },
+                "due": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "skipped": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 8
+                }
+            }
+        },
+        "dto.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "auth_token": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/dto.User"
+                }
+            }
+        },
+        "dto.ParseExpressionRequest": {
+            "type": "object",
+            "required": [
+                "expression"
+            ],
+            "properties": {
+                "expression": {
+                    "type": "string",
+                    "example": "pushups \u003e= 50 and (run \u003e= 30m or not skipped)"
+                }
+            }
+        },
+        "dto.ParseExpressionResponse": {
+            "type": "object",
+            "properties": {
+                "expression": {
+                    "description": "Canonical form of the expression",
+                    "type": "string"
+                },
+                "requirement": {
+                    "$ref": "#/definitions/dto.Requirement"
+                }
+            }
+        },
+        "dto.PongResponse": {
+            "description": "A simple health check response.",
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "pong"
+                }
+            }
+        },
+        "dto.ProfileResponse": {
+            "type": "object",
+            "properties": {
+                "settings": {
+                    "$ref": "#/definitions/dto.UserSettings"
+                },
+                "user": {
+                    "$ref": "#/definitions/dto.User"
+                }
+            }
+        },
+        "dto.RegisterRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password",
+                "username"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 40,
+                    "minLength": 8
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.RegisterResponse": {
+            "type": "object",
+            "properties": {
+                "auth_token": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/dto.User"
+                }
+            }
+        },
+        "dto.Requirement": {
+            "type": "object",
+            "properties": {
+                "aggregation": {
+                    "description": "How entries of a day are combined, default: last",
+                    "type": "string",
+                    "enum": [
+                        "last",
+                        "sum",
+                        "count",
+                        "max",
+                        "min"
+                    ]
+                },
+                "data_type": {
+                    "type": "string",
+                    "enum": [
+                        "bool",
+                        "int",
+                        "float",
+                        "duration",
+                        "none",
+                        "choice",
+                        "text",
+                        "rating",
+                        "time"
+                    ]
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "operands": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Requirement"
+                    }
+                },
+                "operator": {
+                    "type": "string"
+                },
+                "options": {
+                    "description": "Allowed values of a choice",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "bad",
+                        "okay",
+                        "good"
+                    ]
+                },
+                "period": {
+                    "description": "Which entries are aggregated, default: day",
+                    "type": "string",
+                    "enum": [
+                        "day",
+                        "week",
+                        "month",
+                        "rolling"
+                    ]
+                },
+                "period_days": {
+                    "description": "Length of the rolling period",
+                    "type": "integer",
+                    "example": 7
+                },
+                "score_mode": {
+                    "description": "How 'and' combines scores of operands, default: average",
+                    "type": "string",
+                    "enum": [
+                        "average",
+                        "min"
+                    ]
+                },
+                "sort_order": {
+                    "type": "integer"
+                },
+                "target_value": {
+                    "description": "Durations are written like 1h30m, PT1H30M, 01:30:00 (H:MM:SS), 25:30 (MM:SS) or 90 (minutes) and returned in seconds like 5400s",
+                    "type": "string",
+                    "example": "30m"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "unit": {
+                    "description": "Unit of the target value, entries in other units of the same dimension are converted",
+                    "type": "string",
+                    "example": "km"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.RequirementEntry": {
+            "type": "object",
+            "properties": {
+                "entry_date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string",
+                    "example": "Felt easy, try a longer route"
+                },
+                "requirement_id": {
+                    "type": "integer"
+                },
+                "unit": {
+                    "description": "Unit the value was entered in",
+                    "type": "string",
+                    "example": "mi"
+                },
+                "value": {
+                    "type": "string",
+                    "example": "50"
+                }
+            }
+        },
+        "dto.RequirementRevision": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "effective_from": {
+                    "type": "string",
+                    "example": "2025-01-31"
+                },
+                "effective_until": {
+                    "description": "Last day the revision is used for, the latest revision has none",
+                    "type": "string",
+                    "example": "2025-02-28"
+                },
+                "expression": {
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Tasks whose requirements never changed have a single revision without an ID",
+                    "type": "integer"
+                },
+                "requirement": {
+                    "$ref": "#/definitions/dto.Requirement"
+                }
+            }
+        },
+        "dto.RequirementStatus": {
+            "type": "object",
+            "properties": {
+                "operands": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.RequirementStatus"
+                    }
+                },
+                "passed": {
+                    "type": "boolean"
+                },
+                "requirement_id": {
+                    "type": "integer"
+                },
+                "score": {
+                    "description": "Partial credit from 0 to 1",
+                    "type": "number",
+                    "example": 0.9
+                },
+                "state": {
+                    "description": "Pending while the period is not over and it can still be met",
+                    "type": "string",
+                    "enum": [
+                        "met",
+                        "pending",
+                        "failed"
+                    ]
+                },
+                "title": {
+                    "type": "string"
+                },
+                "value": {
+                    "description": "Nullable, nothing was logged",
+                    "type": "string"
                 }
             }
         },
-        "dto.GetAllTasksResponse": {
+        "dto.SearchResponse": {
             "type": "object",
             "properties": {
-                "tasks": {
+                "results": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/dto.Task"
+                        "$ref": "#/definitions/dto.SearchResult"
                     }
                 }
             }
         },
-        "dto.GetTaskByIDResponse": {
+        "dto.SearchResult": {
             "type": "object",
             "properties": {
-                "task": {
-                    "$ref": "#/definitions/dto.Task"
+                "entry_date": {
+                    "description": "Entries only",
+                    "type": "string"
+                },
+                "entry_id": {
+                    "description": "Entries only",
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "task",
+                        "requirement",
+                        "entry"
+                    ]
+                },
+                "requirement_id": {
+                    "description": "Requirements and entries only",
+                    "type": "integer"
+                },
+                "snippet": {
+                    "description": "Matched text with the matches wrapped in \u003cmark\u003e\u003c/mark\u003e, the rest of the text is not HTML escaped",
+                    "type": "string",
+                    "example": "Run \u003cmark\u003e5 km\u003c/mark\u003e before work"
+                },
+                "task_id": {
+                    "type": "integer"
+                },
+                "task_title": {
+                    "type": "string"
                 }
             }
         },
-        "dto.LoginRequest": {
+        "dto.Tag": {
             "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
             "properties": {
-                "email": {
-                    "type": "string"
+                "color": {
+                    "description": "Nullable, #rrggbb",
+                    "type": "string",
+                    "example": "#4caf50"
                 },
-                "password": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
                     "type": "string",
-                    "minLength": 8
+                    "example": "health"
                 }
             }
         },
-        "dto.LoginResponse": {
+        "dto.TagResponse": {
             "type": "object",
             "properties": {
-                "auth_token": {
-                    "type": "string"
-                },
-                "user": {
-                    "$ref": "#/definitions/dto.User"
+                "tag": {
+                    "$ref": "#/definitions/dto.Tag"
                 }
             }
         },
-        "dto.PongResponse": {
-            "description": "A simple health check response.",
+        "dto.Task": {
             "type": "object",
             "properties": {
-                "message": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "description": "Nullable",
+                    "type": "string"
+                },
+                "end_date": {
+                    "description": "Nullable",
+                    "type": "string"
+                },
+                "expression": {
+                    "description": "Text form of the requirement",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "recurrence": {
+                    "description": "Nullable, RRULE subset, daily if empty",
                     "type": "string",
-                    "example": "pong"
+                    "example": "FREQ=WEEKLY;BYDAY=MO,WE,FR"
+                },
+                "requirement": {
+                    "$ref": "#/definitions/dto.Requirement"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "stats": {
+                    "description": "Only with stats=true",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.TaskStats"
+                        }
+                    ]
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "archived",
+                        "completed"
+                    ]
+                },
+                "tag_ids": {
+                    "description": "Tags to create the task with, ignored in responses",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Tag"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "dto.ProfileResponse": {
+        "dto.TaskDay": {
             "type": "object",
             "properties": {
-                "user": {
-                    "$ref": "#/definitions/dto.User"
+                "completed": {
+                    "type": "boolean"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2025-01-31"
+                },
+                "score": {
+                    "description": "Partial credit from 0 to 1",
+                    "type": "number",
+                    "example": 0.9
                 }
             }
         },
-        "dto.RegisterRequest": {
+        "dto.TaskStats": {
             "type": "object",
-            "required": [
-                "email",
-                "password",
-                "username"
-            ],
             "properties": {
-                "email": {
-                    "type": "string"
+                "completed_days": {
+                    "description": "Completed days the task was due on, since it started",
+                    "type": "integer"
                 },
-                "password": {
+                "completion_rate": {
+                    "description": "Share of completed due days (or periods) in the window",
+                    "type": "number",
+                    "example": 0.75
+                },
+                "current_streak": {
+                    "type": "integer"
+                },
+                "longest_streak": {
+                    "type": "integer"
+                },
+                "streak_unit": {
+                    "description": "Streaks of tasks with X-TIMES are counted in met periods",
                     "type": "string",
-                    "maxLength": 40,
-                    "minLength": 8
+                    "enum": [
+                        "day",
+                        "period"
+                    ]
                 },
-                "username": {
-                    "type": "string"
+                "window_days": {
+                    "type": "integer",
+                    "example": 30
                 }
             }
         },
-        "dto.RegisterResponse": {
+        "dto.TaskStatus": {
             "type": "object",
             "properties": {
-                "auth_token": {
-                    "type": "string"
+                "completed": {
+                    "type": "boolean"
                 },
-                "user": {
-                    "$ref": "#/definitions/dto.User"
+                "date": {
+                    "type": "string",
+                    "example": "2025-01-31"
+                },
+                "due": {
+                    "description": "The task is scheduled for this day",
+                    "type": "boolean"
+                },
+                "requirement": {
+                    "$ref": "#/definitions/dto.RequirementStatus"
+                },
+                "score": {
+                    "type": "number",
+                    "example": 0.9
+                },
+                "state": {
+                    "type": "string",
+                    "enum": [
+                        "met",
+                        "pending",
+                        "failed"
+                    ]
+                },
+                "task_id": {
+                    "type": "integer"
                 }
             }
         },
-        "dto.Requirement": {
+        "dto.TrashedTask": {
             "type": "object",
             "properties": {
-                "data_type": {
+                "deleted_at": {
                     "type": "string"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "operands": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dto.Requirement"
-                    }
+                "purge_at": {
+                    "description": "When the task is deleted for good",
+                    "type": "string"
                 },
-                "operator": {
+                "status": {
+                    "description": "Status the task is restored with",
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "archived",
+                        "completed"
+                    ]
+                },
+                "title": {
                     "type": "string"
+                }
+            }
+        },
+        "dto.TrendDay": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string",
+                    "example": "2025-01-31"
                 },
-                "sort_order": {
+                "moving_average": {
+                    "description": "Average of the values in the window ending with the day",
+                    "type": "number",
+                    "example": 1544
+                },
+                "personal_best": {
+                    "description": "Better than every value logged before",
+                    "type": "boolean"
+                },
+                "value": {
+                    "description": "Nothing was logged when missing",
+                    "type": "number",
+                    "example": 1520
+                }
+            }
+        },
+        "dto.TrendRecord": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string",
+                    "example": "2025-01-31"
+                },
+                "value": {
+                    "type": "number",
+                    "example": 1480
+                }
+            }
+        },
+        "dto.TrendSummary": {
+            "type": "object",
+            "properties": {
+                "average": {
+                    "type": "number"
+                },
+                "best": {
+                    "description": "Best value ever, also before the range",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.TrendRecord"
+                        }
+                    ]
+                },
+                "count": {
+                    "description": "Days with a value",
                     "type": "integer"
                 },
-                "target_value": {
-                    "type": "string"
+                "max": {
+                    "$ref": "#/definitions/dto.TrendRecord"
                 },
-                "title": {
+                "min": {
+                    "$ref": "#/definitions/dto.TrendRecord"
+                }
+            }
+        },
+        "dto.TrendWeek": {
+            "type": "object",
+            "properties": {
+                "average": {
+                    "type": "number",
+                    "example": 1544
+                },
+                "change": {
+                    "description": "Relative to the average of the previous week",
+                    "type": "number",
+                    "example": -0.05
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "start": {
+                    "description": "First day of the week, the first and the last week of the range may be partial",
+                    "type": "string",
+                    "example": "2025-01-27"
+                }
+            }
+        },
+        "dto.UpdateEntryRequest": {
+            "type": "object",
+            "properties": {
+                "entry_date": {
                     "type": "string"
                 },
-                "type": {
+                "note": {
+                    "description": "An empty note removes it",
                     "type": "string"
                 },
                 "value": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "3.1 mi"
                 }
             }
         },
-        "dto.Task": {
+        "dto.UpdateSettingsRequest": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
+                "day_ends_at": {
+                    "type": "integer",
+                    "maximum": 23,
+                    "minimum": 0,
+                    "example": 4
+                },
+                "timezone": {
+                    "description": "IANA time zone name",
+                    "type": "string",
+                    "example": "Europe/Berlin"
+                },
+                "week_start": {
+                    "type": "string",
+                    "enum": [
+                        "monday",
+                        "tuesday",
+                        "wednesday",
+                        "thursday",
+                        "friday",
+                        "saturday",
+                        "sunday"
+                    ]
+                }
+            }
+        },
+        "dto.UpdateSettingsResponse": {
+            "type": "object",
+            "properties": {
+                "settings": {
+                    "$ref": "#/definitions/dto.UserSettings"
+                }
+            }
+        },
+        "dto.UpdateTagRequest": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "type": "string",
+                    "example": "#4caf50"
                 },
+                "name": {
+                    "type": "string",
+                    "example": "health"
+                }
+            }
+        },
+        "dto.UpdateTaskRequest": {
+            "type": "object",
+            "properties": {
                 "description": {
-                    "description": "Nullable",
                     "type": "string"
                 },
+                "effective_from": {
+                    "description": "First day the new requirement tree is used for, default: today",
+                    "type": "string",
+                    "example": "2025-02-01"
+                },
                 "end_date": {
-                    "description": "Nullable",
+                    "type": "string",
+                    "example": "2025-12-31"
+                },
+                "expression": {
+                    "description": "Atoms are matched with the existing ones by name",
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer"
+                "recurrence": {
+                    "type": "string",
+                    "example": "FREQ=WEEKLY;BYDAY=MO,WE,FR"
                 },
                 "requirement": {
                     "$ref": "#/definitions/dto.Requirement"
                 },
                 "start_date": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "2025-01-31"
                 },
-                "title": {
-                    "type": "string"
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "archived",
+                        "completed"
+                    ]
                 },
-                "updated_at": {
+                "tag_ids": {
+                    "description": "Replaces the tags of the task, an empty list removes them all",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "title": {
                     "type": "string"
                 }
             }
         },
+        "dto.UpdateTaskResponse": {
+            "type": "object",
+            "properties": {
+                "task": {
+                    "$ref": "#/definitions/dto.Task"
+                }
+            }
+        },
         "dto.User": {
             "type": "object",
             "properties": {
@@ -602,6 +3831,35 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "dto.UserSettings": {
+            "type": "object",
+            "properties": {
+                "day_ends_at": {
+                    "description": "Hour of the night the day ends at, entries logged before it count for the day before",
+                    "type": "integer",
+                    "example": 4
+                },
+                "timezone": {
+                    "description": "Days, weeks and months are split in this time zone",
+                    "type": "string",
+                    "example": "Europe/Berlin"
+                },
+                "week_start": {
+                    "description": "First day of weekly periods",
+                    "type": "string",
+                    "enum": [
+                        "monday",
+                        "tuesday",
+                        "wednesday",
+                        "thursday",
+                        "friday",
+                        "saturday",
+                        "sunday"
+                    ],
+                    "example": "monday"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -22,9 +22,11 @@ func (e *Error) SendAndAbort(c *gin.Context) {
 }
 
 func (e *Error) SendWithDetailsAndAbort(c *gin.Context, details any) {
-	e.Details = details
-	e.addTimingInfo(c)
-	c.AbortWithStatusJSON(e.HTTPStatus, e)
+	// Errors are shared between requests, so details go to a copy
+	withDetails := *e
+	withDetails.Details = details
+	withDetails.addTimingInfo(c)
+	c.AbortWithStatusJSON(withDetails.HTTPStatus, &withDetails)
 }
 
 // Helper to add timing information to error
@@ -61,6 +63,12 @@ var (
 		Code:       "TYPE_MISMATCH",
 		Message:    "Field type mismatch",
 	}
+
+	ValidationFailed = &Error{
+		HTTPStatus: http.StatusBadRequest,
+		Code:       "VALIDATION_FAILED",
+		Message:    "Validation failed",
+	}
 )
 
 // Auth related
@@ -134,3 +142,12 @@ var (
 		Message:    "Invalid task ID",
 	}
 )
+
+// Requirement expressions
+var (
+	InvalidExpression = &Error{
+		HTTPStatus: http.StatusBadRequest,
+		Code:       "INVALID_EXPRESSION",
+		Message:    "Invalid requirement expression",
+	}
+)
@@ -18,14 +18,23 @@ func Accepted(c *gin.Context, data any) {
 	sendResponse(c, 202, data)
 }
 
+// NoContent sends 204 without a body, for deletions that have nothing to return
+func NoContent(c *gin.Context) {
+	addLatencyHeader(c)
+	c.Status(204)
+}
+
 // Unified response handler
 func sendResponse(c *gin.Context, status int, data any) {
-	// Add timing headers if available
+	addLatencyHeader(c)
+	c.JSON(status, data)
+}
+
+// Add timing headers if available
+func addLatencyHeader(c *gin.Context) {
 	if start, exists := c.Get("request_start"); exists {
 		if startTime, ok := start.(time.Time); ok {
 			c.Header("Request-Latency", time.Since(startTime).String())
 		}
 	}
-
-	c.JSON(status, data)
 }
@@ -1,10 +1,13 @@
 package apperrors
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 type ValidationError struct {
 	Code    string
-	Field   string
+	Field   string // JSON path of the field, e.g. "requirement.operands[1].target_value"
 	Message string
 }
 
@@ -19,3 +22,14 @@ func NewValidationError(code string, field string, message string) *ValidationEr
 		Message: message,
 	}
 }
+
+// ValidationErrors collects every problem found in a request so they can be reported at once
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, fmt.Sprintf("%s: %s", err.Field, err.Message))
+	}
+	return fmt.Sprintf("validation failed: %s", strings.Join(messages, "; "))
+}
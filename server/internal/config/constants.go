@@ -5,3 +5,6 @@ const (
 	EnvDevelopment = "development"
 	EnvStaging     = "staging"
 )
+
+// DateFormat is the layout of calendar dates in query parameters and responses
+const DateFormat = "2006-01-02"
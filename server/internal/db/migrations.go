@@ -0,0 +1,226 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/boreymarf/task-fuss/server/internal/duration"
+	"github.com/boreymarf/task-fuss/server/internal/logger"
+)
+
+// addColumnIfMissing adds a column to a table created by an older version of the server.
+// CREATE TABLE IF NOT EXISTS leaves existing tables untouched, so new columns have to be added separately.
+func addColumnIfMissing(db DBTX, table string, column string, definition string) error {
+
+	columns, err := tableColumns(db, table)
+	if err != nil {
+		return err
+	}
+
+	if slices.Contains(columns, column) {
+		return nil
+	}
+
+	_, err = db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+	if err != nil {
+		return fmt.Errorf("failed to add column %s to %s: %w", column, table, err)
+	}
+
+	return nil
+}
+
+// rebuildTableIfOutdated recreates a table whose definition does not contain marker yet.
+// SQLite cannot change CHECK constraints of an existing table, so the table is created
+// again from createQuery under a temporary name, the rows are copied over and the
+// old table is replaced in one transaction. Columns missing in either table are skipped.
+func rebuildTableIfOutdated(db *sql.DB, table string, createQuery string, marker string) error {
+	return (&UnitOfWork{db: db}).Do(func(tx *sql.Tx) error {
+		return rebuildTable(tx, table, createQuery, marker)
+	})
+}
+
+func rebuildTable(db DBTX, table string, createQuery string, marker string) error {
+
+	var definition string
+	err := db.QueryRow(`SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?`, table).Scan(&definition)
+	if err != nil {
+		return fmt.Errorf("failed to read definition of %s: %w", table, err)
+	}
+
+	if strings.Contains(definition, marker) {
+		return nil
+	}
+
+	logger.Log.Info().Str("table", table).Msg("Rebuilding table with the new definition")
+
+	oldColumns, err := tableColumns(db, table)
+	if err != nil {
+		return err
+	}
+
+	tmp := table + "_rebuild"
+	query := strings.Replace(createQuery, "IF NOT EXISTS "+table, tmp, 1)
+	if query == createQuery {
+		return fmt.Errorf("create query of %s has to start with CREATE TABLE IF NOT EXISTS %s", table, table)
+	}
+
+	if _, err := db.Exec("DROP TABLE IF EXISTS " + tmp); err != nil {
+		return err
+	}
+	if _, err := db.Exec(query); err != nil {
+		return fmt.Errorf("failed to create %s: %w", tmp, err)
+	}
+
+	newColumns, err := tableColumns(db, tmp)
+	if err != nil {
+		return err
+	}
+
+	var common []string
+	for _, column := range newColumns {
+		if slices.Contains(oldColumns, column) {
+			common = append(common, column)
+		}
+	}
+	columnList := strings.Join(common, ", ")
+
+	statements := []string{
+		fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s", tmp, columnList, columnList, table),
+		"DROP TABLE " + table,
+		fmt.Sprintf("ALTER TABLE %s RENAME TO %s", tmp, table),
+	}
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			return fmt.Errorf("failed to rebuild %s: %w", table, err)
+		}
+	}
+
+	return nil
+}
+
+func tableColumns(db DBTX, table string) ([]string, error) {
+
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns of %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var (
+			cid          int
+			name         string
+			columnType   string
+			notNull      bool
+			defaultValue any
+			primaryKey   int
+		)
+		if err := rows.Scan(&cid, &name, &columnType, &notNull, &defaultValue, &primaryKey); err != nil {
+			return nil, fmt.Errorf("failed to scan column of %s: %w", table, err)
+		}
+		columns = append(columns, name)
+	}
+
+	return columns, rows.Err()
+}
+
+// canonicalizeDurations rewrites duration values stored before durations were kept as seconds
+// with a unit. Plain numbers were stored as seconds and get the unit, other values are parsed.
+// Values that cannot be parsed are left as they are.
+func canonicalizeDurations(db *sql.DB) error {
+
+	migrations := []struct {
+		selectQuery string
+		updateQuery string
+	}{
+		{
+			selectQuery: `SELECT id, target_value FROM requirements
+				WHERE data_type = 'duration' AND (target_value NOT GLOB '*s' OR target_value GLOB '*[^0-9s]*')`,
+			updateQuery: `UPDATE requirements SET target_value = ? WHERE id = ?`,
+		},
+		{
+			selectQuery: `SELECT e.id, e.value FROM requirement_entries e
+				JOIN requirements r ON r.id = e.requirement_id
+				WHERE r.data_type = 'duration' AND (e.value NOT GLOB '*s' OR e.value GLOB '*[^0-9s]*')`,
+			updateQuery: `UPDATE requirement_entries SET value = ? WHERE id = ?`,
+		},
+	}
+
+	return (&UnitOfWork{db: db}).Do(func(tx *sql.Tx) error {
+		for _, migration := range migrations {
+			values, err := readValues(tx, migration.selectQuery)
+			if err != nil {
+				return err
+			}
+
+			for id, value := range values {
+				if duration.IsCanonical(value) {
+					continue
+				}
+				if value != "" && strings.Trim(value, "0123456789") == "" {
+					value += "s"
+				}
+				d, err := duration.Parse(value)
+				if err != nil {
+					logger.Log.Warn().Int64("id", id).Str("value", value).Msg("Stored duration cannot be parsed, leaving it as is")
+					continue
+				}
+				if _, err := tx.Exec(migration.updateQuery, duration.Canonical(d), id); err != nil {
+					return fmt.Errorf("failed to store canonical duration: %w", err)
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// dropRuleWeekStarts removes WKST from recurrence rules stored before weeks started on the
+// first day of the week in the user's settings, which is the only place it is set now.
+func dropRuleWeekStarts(db DBTX) error {
+
+	values, err := readValues(db, `SELECT id, recurrence FROM tasks WHERE recurrence LIKE '%WKST=%'`)
+	if err != nil {
+		return err
+	}
+
+	for id, rule := range values {
+		var parts []string
+		for _, part := range strings.Split(rule, ";") {
+			if !strings.HasPrefix(strings.TrimSpace(part), "WKST=") {
+				parts = append(parts, part)
+			}
+		}
+
+		logger.Log.Info().Int64("id", id).Str("recurrence", rule).Msg("Dropping WKST from stored recurrence rule")
+		if _, err := db.Exec(`UPDATE tasks SET recurrence = ? WHERE id = ?`, strings.Join(parts, ";"), id); err != nil {
+			return fmt.Errorf("failed to store recurrence without WKST: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// readValues reads rows of id and value pairs.
+func readValues(db DBTX, query string) (map[int64]string, error) {
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stored values: %w", err)
+	}
+	defer rows.Close()
+
+	values := make(map[int64]string)
+	for rows.Next() {
+		var id int64
+		var value string
+		if err := rows.Scan(&id, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan stored value: %w", err)
+		}
+		values[id] = value
+	}
+
+	return values, rows.Err()
+}
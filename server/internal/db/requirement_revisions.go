@@ -0,0 +1,89 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/boreymarf/task-fuss/server/internal/logger"
+	"github.com/boreymarf/task-fuss/server/internal/models"
+)
+
+func (r *RequirementRepository) createRevisionsTable() error {
+	query := `CREATE TABLE IF NOT EXISTS requirement_revisions (
+	id             INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+	task_id        INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+	effective_from DATETIME NOT NULL,
+	tree           TEXT NOT NULL,
+	created_at     DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`
+
+	_, err := r.db.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	// One revision per task per day, effective_from is always stored as the start of the day
+	query = `CREATE UNIQUE INDEX IF NOT EXISTS idx_requirement_revisions_task_day ON requirement_revisions (task_id, effective_from)`
+
+	_, err = r.db.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpsertRevision stores the revision, a revision of the task effective from the same day is replaced.
+func (r *RequirementRepository) UpsertRevision(revision *models.RequirementRevision) error {
+	logger.Log.Debug().
+		Int64("task_id", revision.TaskID).
+		Time("effective_from", revision.EffectiveFrom).
+		Msg("Trying to upsert requirement revision to the db...")
+
+	query := `INSERT INTO requirement_revisions (task_id, effective_from, tree) VALUES (?, ?, ?)
+	ON CONFLICT (task_id, effective_from) DO UPDATE SET tree = excluded.tree, created_at = CURRENT_TIMESTAMP
+	RETURNING id, created_at`
+
+	err := r.db.QueryRow(query, revision.TaskID, revision.EffectiveFrom.UTC(), revision.Tree).Scan(&revision.ID, &revision.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to upsert requirement revision: %w", err)
+	}
+
+	return nil
+}
+
+// GetRevisionsByTaskID returns the revisions of the task ordered by the day they take effect.
+func (r *RequirementRepository) GetRevisionsByTaskID(taskID int64) ([]models.RequirementRevision, error) {
+
+	query := `SELECT id, task_id, effective_from, tree, created_at
+		FROM requirement_revisions
+		WHERE task_id = ?
+		ORDER BY effective_from`
+
+	rows, err := r.db.Query(query, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query requirement revisions: %w", err)
+	}
+	defer rows.Close()
+
+	var revisions []models.RequirementRevision
+	for rows.Next() {
+		var revision models.RequirementRevision
+		err := rows.Scan(
+			&revision.ID,
+			&revision.TaskID,
+			&revision.EffectiveFrom,
+			&revision.Tree,
+			&revision.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan requirement revision: %w", err)
+		}
+		revisions = append(revisions, revision)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after scanning requirement revisions: %w", err)
+	}
+
+	return revisions, nil
+}
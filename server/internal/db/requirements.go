@@ -2,6 +2,7 @@ package db
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strconv"
@@ -14,7 +15,7 @@ import (
 )
 
 type RequirementRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
 func InitRequirementRepository(db *sql.DB) (*RequirementRepository, error) {
@@ -25,32 +26,142 @@ func InitRequirementRepository(db *sql.DB) (*RequirementRepository, error) {
 		return nil, fmt.Errorf("migration failed: %w", err)
 	}
 
+	// Data types added after the first version are not allowed by the old CHECK constraint
+	if err := rebuildTableIfOutdated(db, "requirements", requirementsTable, "'time'"); err != nil {
+		return nil, fmt.Errorf("migration failed: %w", err)
+	}
+
+	// Everything built next to the table comes after the rebuild, which drops the old table
+	if err := repo.createRevisionsTable(); err != nil {
+		return nil, fmt.Errorf("migration failed: %w", err)
+	}
+
 	logger.Log.Debug().Msg("taskRepository initialization completed")
 
 	return repo, nil
 }
 
-func (r *RequirementRepository) CreateTable() error {
-	query := `CREATE TABLE IF NOT EXISTS requirements (
+// WithTx returns a copy of the repository that runs its queries in tx.
+func (r *RequirementRepository) WithTx(tx *sql.Tx) *RequirementRepository {
+	return &RequirementRepository{db: tx}
+}
+
+// requirementsTable is the current definition of the table, older databases are rebuilt to match it
+const requirementsTable = `CREATE TABLE IF NOT EXISTS requirements (
   id           INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
   task_id      INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
   parent_id    INTEGER REFERENCES requirements(id) ON DELETE CASCADE,
 	title        TEXT NOT NULL,
   type         TEXT NOT NULL CHECK (type IN ('atom', 'condition')),
-  data_type    TEXT CHECK (data_type IN ('bool', 'int', 'float', 'duration', 'none')),
+  data_type    TEXT CHECK (data_type IN ('bool', 'int', 'float', 'duration', 'none', 'choice', 'text', 'rating', 'time')),
   operator     TEXT CHECK (operator IN ('or', 'not', 'and', '==', '>=', '<=', '!=', '>', '<')),
   target_value TEXT,
-  sort_order   INTEGER NOT NULL DEFAULT 0
+  options      TEXT,
+  unit         TEXT,
+  aggregation  TEXT CHECK (aggregation IN ('last', 'sum', 'count', 'max', 'min')),
+  period       TEXT CHECK (period IN ('day', 'week', 'month', 'rolling')),
+  period_days  INTEGER CHECK (period_days > 0),
+  score_mode   TEXT CHECK (score_mode IN ('average', 'min')),
+  sort_order   INTEGER NOT NULL DEFAULT 0,
+  retired      INTEGER NOT NULL DEFAULT 0 CHECK (retired IN (0, 1))
   )`
 
+func (r *RequirementRepository) CreateTable() error {
+	query := requirementsTable
+
 	_, err := r.db.Exec(query)
 	if err != nil {
 		return err
 	}
 
+	if err := addColumnIfMissing(r.db, "requirements", "aggregation", "TEXT CHECK (aggregation IN ('last', 'sum', 'count', 'max', 'min'))"); err != nil {
+		return err
+	}
+
+	if err := addColumnIfMissing(r.db, "requirements", "period", "TEXT CHECK (period IN ('day', 'week', 'month', 'rolling'))"); err != nil {
+		return err
+	}
+
+	if err := addColumnIfMissing(r.db, "requirements", "period_days", "INTEGER CHECK (period_days > 0)"); err != nil {
+		return err
+	}
+
+	if err := addColumnIfMissing(r.db, "requirements", "options", "TEXT"); err != nil {
+		return err
+	}
+
+	if err := addColumnIfMissing(r.db, "requirements", "unit", "TEXT"); err != nil {
+		return err
+	}
+
+	if err := addColumnIfMissing(r.db, "requirements", "score_mode", "TEXT CHECK (score_mode IN ('average', 'min'))"); err != nil {
+		return err
+	}
+
+	// Requirements removed from the tree are kept while older revisions of the tree use them
+	if err := addColumnIfMissing(r.db, "requirements", "retired", "INTEGER NOT NULL DEFAULT 0 CHECK (retired IN (0, 1))"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+const requirementColumns = `id, task_id, parent_id, title, type, data_type, operator, target_value, options, unit, aggregation, period, period_days, score_mode, sort_order, retired`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRequirement(row scanner, req *models.Requirement) error {
+	var options sql.NullString
+
+	err := row.Scan(
+		&req.ID,
+		&req.TaskID,
+		&req.ParentID,
+		&req.Title,
+		&req.Type,
+		&req.DataType,
+		&req.Operator,
+		&req.TargetValue,
+		&options,
+		&req.Unit,
+		&req.Aggregation,
+		&req.Period,
+		&req.PeriodDays,
+		&req.ScoreMode,
+		&req.SortOrder,
+		&req.Retired,
+	)
+	if err != nil {
+		return err
+	}
+
+	req.Options = nil
+	if options.Valid {
+		if err := json.Unmarshal([]byte(options.String), &req.Options); err != nil {
+			return fmt.Errorf("requirement %d has invalid options: %w", req.ID, err)
+		}
+	}
+
 	return nil
 }
 
+// encodeOptions stores options as a JSON array, NULL when there are none.
+func encodeOptions(options []string) (*string, error) {
+	if len(options) == 0 {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(options)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded := string(data)
+	return &encoded, nil
+}
+
 func (r *RequirementRepository) CreateRequirement(requirement *models.Requirement) error {
 	logger.Log.Debug().
 		Str("title", requirement.Title).
@@ -64,8 +175,19 @@ func (r *RequirementRepository) CreateRequirement(requirement *models.Requiremen
 		data_type,
 		operator,
 		target_value,
+		options,
+		unit,
+		aggregation,
+		period,
+		period_days,
+		score_mode,
 		sort_order
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	options, err := encodeOptions(requirement.Options)
+	if err != nil {
+		return err
+	}
 
 	result, err := r.db.Exec(
 		query,
@@ -76,6 +198,12 @@ func (r *RequirementRepository) CreateRequirement(requirement *models.Requiremen
 		requirement.DataType,
 		requirement.Operator,
 		requirement.TargetValue,
+		options,
+		requirement.Unit,
+		requirement.Aggregation,
+		requirement.Period,
+		requirement.PeriodDays,
+		requirement.ScoreMode,
 		requirement.SortOrder,
 	)
 
@@ -105,6 +233,28 @@ func (r *RequirementRepository) CreateRequirement(requirement *models.Requiremen
 	return nil
 }
 
+func (r *RequirementRepository) GetRequirementByID(id int64) (models.Requirement, error) {
+
+	var req models.Requirement
+
+	query := `SELECT ` + requirementColumns + `
+		FROM requirements WHERE id = ?`
+
+	err := scanRequirement(r.db.QueryRow(query, id), &req)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Log.Warn().
+			Int64("requirementID", id).
+			Msg("No requirement was found")
+		return models.Requirement{}, fmt.Errorf("requirement %d not found: %w", id, err)
+	} else if err != nil {
+		return models.Requirement{}, err
+	}
+
+	return req, nil
+}
+
+// GetRequirementsByTaskIDs returns the current requirement trees of the tasks, retired requirements are left out.
 func (r *RequirementRepository) GetRequirementsByTaskIDs(taskIDs []int64) ([]models.Requirement, error) {
 
 	var stringIDs []string
@@ -114,8 +264,8 @@ func (r *RequirementRepository) GetRequirementsByTaskIDs(taskIDs []int64) ([]mod
 	}
 	idQuery := strings.Join(stringIDs, ", ")
 
-	query := fmt.Sprintf(`SELECT id, task_id, parent_id, title, type, data_type, operator, target_value, sort_order 
-		FROM requirements WHERE task_id IN (%s)`, idQuery)
+	query := fmt.Sprintf(`SELECT %s
+		FROM requirements WHERE task_id IN (%s) AND retired = 0`, requirementColumns, idQuery)
 
 	rows, err := r.db.Query(query)
 	if err != nil {
@@ -126,18 +276,7 @@ func (r *RequirementRepository) GetRequirementsByTaskIDs(taskIDs []int64) ([]mod
 	var requirements []models.Requirement
 	for rows.Next() {
 		var req models.Requirement
-		err := rows.Scan(
-			&req.ID,
-			&req.TaskID,
-			&req.ParentID,
-			&req.Title,
-			&req.Type,
-			&req.DataType,
-			&req.Operator,
-			&req.TargetValue,
-			&req.SortOrder,
-		)
-		if err != nil {
+		if err := scanRequirement(rows, &req); err != nil {
 			return nil, fmt.Errorf("failed to scan requirement: %w", err)
 		}
 		requirements = append(requirements, req)
@@ -149,3 +288,128 @@ func (r *RequirementRepository) GetRequirementsByTaskIDs(taskIDs []int64) ([]mod
 
 	return requirements, nil
 }
+
+// UpdateRequirement overwrites every field of the requirement except its task.
+// A retired requirement becomes part of the current tree again.
+func (r *RequirementRepository) UpdateRequirement(requirement *models.Requirement) error {
+
+	query := `UPDATE requirements SET
+		parent_id = ?,
+		title = ?,
+		type = ?,
+		data_type = ?,
+		operator = ?,
+		target_value = ?,
+		options = ?,
+		unit = ?,
+		aggregation = ?,
+		period = ?,
+		period_days = ?,
+		score_mode = ?,
+		sort_order = ?,
+		retired = 0
+	WHERE id = ?`
+
+	options, err := encodeOptions(requirement.Options)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.Exec(
+		query,
+		requirement.ParentID,
+		requirement.Title,
+		requirement.Type,
+		requirement.DataType,
+		requirement.Operator,
+		requirement.TargetValue,
+		options,
+		requirement.Unit,
+		requirement.Aggregation,
+		requirement.Period,
+		requirement.PeriodDays,
+		requirement.ScoreMode,
+		requirement.SortOrder,
+		requirement.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("requirement %d not found: %w", requirement.ID, sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+// DeleteRequirements deletes the requirements, their children have to be in ids too.
+func (r *RequirementRepository) DeleteRequirements(ids []int64) error {
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var stringIDs []string
+	for _, id := range ids {
+		stringIDs = append(stringIDs, strconv.FormatInt(id, 10))
+	}
+
+	query := fmt.Sprintf(`DELETE FROM requirements WHERE id IN (%s)`, strings.Join(stringIDs, ", "))
+
+	if _, err := r.db.Exec(query); err != nil {
+		return fmt.Errorf("failed to delete requirements: %w", err)
+	}
+
+	return nil
+}
+
+// RetireRequirements removes the requirements from the current tree but keeps them and their
+// entries for the older revisions of the tree that still use them.
+func (r *RequirementRepository) RetireRequirements(ids []int64) error {
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var stringIDs []string
+	for _, id := range ids {
+		stringIDs = append(stringIDs, strconv.FormatInt(id, 10))
+	}
+
+	query := fmt.Sprintf(`UPDATE requirements SET retired = 1 WHERE id IN (%s)`, strings.Join(stringIDs, ", "))
+
+	if _, err := r.db.Exec(query); err != nil {
+		return fmt.Errorf("failed to retire requirements: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteRequirementsByTaskIDs deletes every requirement and revision of the tasks, retired ones included.
+func (r *RequirementRepository) DeleteRequirementsByTaskIDs(taskIDs []int64) error {
+
+	if len(taskIDs) == 0 {
+		return nil
+	}
+
+	var stringIDs []string
+	for _, id := range taskIDs {
+		stringIDs = append(stringIDs, strconv.FormatInt(id, 10))
+	}
+	idQuery := strings.Join(stringIDs, ", ")
+
+	if _, err := r.db.Exec(fmt.Sprintf(`DELETE FROM requirement_revisions WHERE task_id IN (%s)`, idQuery)); err != nil {
+		return fmt.Errorf("failed to delete requirement revisions: %w", err)
+	}
+
+	if _, err := r.db.Exec(fmt.Sprintf(`DELETE FROM requirements WHERE task_id IN (%s)`, idQuery)); err != nil {
+		return fmt.Errorf("failed to delete requirements: %w", err)
+	}
+
+	return nil
+}
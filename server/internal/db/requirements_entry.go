@@ -2,13 +2,18 @@ package db
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/boreymarf/task-fuss/server/internal/logger"
+	"github.com/boreymarf/task-fuss/server/internal/models"
 )
 
 type RequirementEntryRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
 func InitRequirementEntryRepository(db *sql.DB) (*RequirementEntryRepository, error) {
@@ -19,17 +24,28 @@ func InitRequirementEntryRepository(db *sql.DB) (*RequirementEntryRepository, er
 		return nil, fmt.Errorf("migration failed: %w", err)
 	}
 
+	if err := canonicalizeDurations(db); err != nil {
+		return nil, fmt.Errorf("migration failed: %w", err)
+	}
+
 	logger.Log.Debug().Msg("Repository initialization completed")
 
 	return repo, nil
 }
 
+// WithTx returns a copy of the repository that runs its queries in tx.
+func (r *RequirementEntryRepository) WithTx(tx *sql.Tx) *RequirementEntryRepository {
+	return &RequirementEntryRepository{db: tx}
+}
+
 func (r *RequirementEntryRepository) CreateTable() error {
 	query := `CREATE TABLE IF NOT EXISTS requirement_entries (
 	id 							INTEGER NOT NULL PRIMARY KEY,
 	requirement_id 	INTEGER NOT NULL REFERENCES requirements(id) ON DELETE CASCADE,
 	entry_date 			DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
-	value						TEXT NOT NULL
+	value						TEXT NOT NULL,
+	unit						TEXT,
+	note						TEXT
 	)`
 
 	_, err := r.db.Exec(query)
@@ -37,5 +53,234 @@ func (r *RequirementEntryRepository) CreateTable() error {
 		return err
 	}
 
+	if err := addColumnIfMissing(r.db, "requirement_entries", "unit", "TEXT"); err != nil {
+		return err
+	}
+
+	if err := addColumnIfMissing(r.db, "requirement_entries", "note", "TEXT"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *RequirementEntryRepository) CreateEntry(entry *models.RequirementEntry) error {
+	logger.Log.Debug().
+		Int64("requirement_id", entry.RequirementID).
+		Time("entry_date", entry.EntryDate).
+		Msg("Trying to create new requirement entry to the db...")
+
+	query := `INSERT INTO requirement_entries (requirement_id, entry_date, value, unit, note) VALUES (?, ?, ?, ?, ?)`
+
+	result, err := r.db.Exec(query, entry.RequirementID, entry.EntryDate.UTC(), entry.Value, entry.Unit, entry.Note)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	entry.ID = id
+
+	return nil
+}
+
+func (r *RequirementEntryRepository) GetEntryByID(id int64) (models.RequirementEntry, error) {
+
+	var entry models.RequirementEntry
+	logger.Log.Debug().Int64("id", id).Msg("requirementEntryRepository tries to find entry")
+
+	query := `SELECT id, requirement_id, entry_date, value, unit, note
+	FROM requirement_entries
+	WHERE id = ?`
+
+	err := r.db.QueryRow(query, id).Scan(
+		&entry.ID,
+		&entry.RequirementID,
+		&entry.EntryDate,
+		&entry.Value,
+		&entry.Unit,
+		&entry.Note,
+	)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Log.Warn().
+			Int64("entryID", id).
+			Msg("No requirement entry was found")
+		return models.RequirementEntry{}, fmt.Errorf("requirement entry %d not found: %w", id, err)
+	} else if err != nil {
+		return models.RequirementEntry{}, err
+	}
+
+	return entry, nil
+}
+
+func (r *RequirementEntryRepository) UpdateEntry(entry *models.RequirementEntry) error {
+
+	query := `UPDATE requirement_entries SET entry_date = ?, value = ?, unit = ?, note = ? WHERE id = ?`
+
+	result, err := r.db.Exec(query, entry.EntryDate.UTC(), entry.Value, entry.Unit, entry.Note, entry.ID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("requirement entry %d not found: %w", entry.ID, sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+func (r *RequirementEntryRepository) DeleteEntry(id int64) error {
+
+	query := `DELETE FROM requirement_entries WHERE id = ?`
+
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("requirement entry %d not found: %w", id, sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+// GetEntriesByRequirementIDs returns entries of the given requirements
+// recorded in the half-open interval [from, to).
+func (r *RequirementEntryRepository) GetEntriesByRequirementIDs(requirementIDs []int64, from time.Time, to time.Time) ([]models.RequirementEntry, error) {
+
+	if len(requirementIDs) == 0 {
+		return nil, nil
+	}
+
+	var stringIDs []string
+	for _, id := range requirementIDs {
+		stringIDs = append(stringIDs, strconv.FormatInt(id, 10))
+	}
+	idQuery := strings.Join(stringIDs, ", ")
+
+	query := fmt.Sprintf(`SELECT id, requirement_id, entry_date, value, unit, note
+		FROM requirement_entries
+		WHERE requirement_id IN (%s) AND entry_date >= ? AND entry_date < ?
+		ORDER BY entry_date, id`, idQuery)
+
+	rows, err := r.db.Query(query, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query requirement entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []models.RequirementEntry
+	for rows.Next() {
+		var entry models.RequirementEntry
+		err := rows.Scan(
+			&entry.ID,
+			&entry.RequirementID,
+			&entry.EntryDate,
+			&entry.Value,
+			&entry.Unit,
+			&entry.Note,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan requirement entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after scanning requirement entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// DeleteEntriesByRequirementIDs deletes every entry of the requirements.
+func (r *RequirementEntryRepository) DeleteEntriesByRequirementIDs(requirementIDs []int64) error {
+
+	if len(requirementIDs) == 0 {
+		return nil
+	}
+
+	var stringIDs []string
+	for _, id := range requirementIDs {
+		stringIDs = append(stringIDs, strconv.FormatInt(id, 10))
+	}
+
+	query := fmt.Sprintf(`DELETE FROM requirement_entries WHERE requirement_id IN (%s)`, strings.Join(stringIDs, ", "))
+
+	if _, err := r.db.Exec(query); err != nil {
+		return fmt.Errorf("failed to delete requirement entries: %w", err)
+	}
+
+	return nil
+}
+
+// CountEntriesByRequirementIDs returns the number of entries of every requirement that has any.
+func (r *RequirementEntryRepository) CountEntriesByRequirementIDs(requirementIDs []int64) (map[int64]int, error) {
+
+	counts := make(map[int64]int)
+	if len(requirementIDs) == 0 {
+		return counts, nil
+	}
+
+	var stringIDs []string
+	for _, id := range requirementIDs {
+		stringIDs = append(stringIDs, strconv.FormatInt(id, 10))
+	}
+
+	query := fmt.Sprintf(`SELECT requirement_id, COUNT(*)
+		FROM requirement_entries
+		WHERE requirement_id IN (%s)
+		GROUP BY requirement_id`, strings.Join(stringIDs, ", "))
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count requirement entries: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		var count int
+		if err := rows.Scan(&id, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan requirement entry count: %w", err)
+		}
+		counts[id] = count
+	}
+
+	return counts, rows.Err()
+}
+
+// DeleteEntriesByTaskIDs deletes the entries of every requirement of the tasks, retired ones included.
+func (r *RequirementEntryRepository) DeleteEntriesByTaskIDs(taskIDs []int64) error {
+
+	if len(taskIDs) == 0 {
+		return nil
+	}
+
+	var stringIDs []string
+	for _, id := range taskIDs {
+		stringIDs = append(stringIDs, strconv.FormatInt(id, 10))
+	}
+
+	query := fmt.Sprintf(`DELETE FROM requirement_entries
+		WHERE requirement_id IN (SELECT id FROM requirements WHERE task_id IN (%s))`, strings.Join(stringIDs, ", "))
+
+	if _, err := r.db.Exec(query); err != nil {
+		return fmt.Errorf("failed to delete requirement entries: %w", err)
+	}
+
 	return nil
 }
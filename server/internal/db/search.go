@@ -0,0 +1,391 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/boreymarf/task-fuss/server/internal/logger"
+	"github.com/boreymarf/task-fuss/server/internal/models"
+)
+
+// Markers around the matched words in search snippets
+const (
+	SnippetOpen  = "<mark>"
+	SnippetClose = "</mark>"
+)
+
+// snippetWords is roughly how many words a snippet shows around the match
+const snippetWords = 12
+
+// SearchRepository looks up tasks, requirements and entry notes by the words in them.
+//
+// FTS5 is only compiled into go-sqlite3 with the sqlite_fts5 build tag, which every build of the
+// server is expected to pass. With it the text is indexed in FTS5 tables that triggers keep in sync
+// and results are ranked with bm25. Without it every search scans the tables with LIKE and ranks
+// by the number of matches, which is only meant for development.
+type SearchRepository struct {
+	db       DBTX
+	fullText bool
+}
+
+func InitSearchRepository(db *sql.DB) (*SearchRepository, error) {
+
+	repo := &SearchRepository{db: db}
+
+	if err := db.QueryRow(`SELECT sqlite_compileoption_used('ENABLE_FTS5')`).Scan(&repo.fullText); err != nil {
+		return nil, err
+	}
+
+	if repo.fullText {
+		if err := repo.CreateTables(); err != nil {
+			return nil, fmt.Errorf("migration failed: %w", err)
+		}
+	} else {
+		logger.Log.Error().Msg("FTS5 is not compiled in, search falls back to scanning every row with LIKE. Build the server with -tags sqlite_fts5")
+
+		// The triggers of an index created by an FTS5 build fail every write without the module
+		if err := repo.DropTriggers(); err != nil {
+			return nil, fmt.Errorf("migration failed: %w", err)
+		}
+	}
+
+	logger.Log.Debug().Bool("fts5", repo.fullText).Msg("searchRepository initialization completed")
+
+	return repo, nil
+}
+
+// FullText reports whether the FTS5 index is used.
+func (r *SearchRepository) FullText() bool {
+	return r.fullText
+}
+
+// searchIndexes are the FTS5 indexes and the columns of the tables they index
+var searchIndexes = []struct {
+	name    string
+	table   string
+	columns []string
+}{
+	{name: "tasks_fts", table: "tasks", columns: []string{"title", "description"}},
+	{name: "requirements_fts", table: "requirements", columns: []string{"title"}},
+	{name: "entry_notes_fts", table: "requirement_entries", columns: []string{"note"}},
+}
+
+// searchTriggerSuffixes name the insert, delete and update triggers of every index
+var searchTriggerSuffixes = []string{"_ai", "_ad", "_au"}
+
+// CreateTables creates the FTS5 indexes with the triggers that keep them in sync.
+// The indexes only point at the rows of the indexed tables instead of keeping a copy of the text.
+// An index is rebuilt only when it or one of its triggers was missing, rows written meanwhile are not indexed.
+func (r *SearchRepository) CreateTables() error {
+
+	for _, index := range searchIndexes {
+		complete, err := r.indexComplete(index.name)
+		if err != nil {
+			return err
+		}
+
+		columns := strings.Join(index.columns, ", ")
+		oldColumns := "old." + strings.Join(index.columns, ", old.")
+		newColumns := "new." + strings.Join(index.columns, ", new.")
+
+		statements := []string{
+			fmt.Sprintf(`CREATE VIRTUAL TABLE IF NOT EXISTS %s USING fts5(%s, content='%s', content_rowid='id', tokenize='unicode61 remove_diacritics 2')`,
+				index.name, columns, index.table),
+			fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS %s_ai AFTER INSERT ON %s BEGIN
+				INSERT INTO %s (rowid, %s) VALUES (new.id, %s);
+			END`, index.name, index.table, index.name, columns, newColumns),
+			fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS %s_ad AFTER DELETE ON %s BEGIN
+				INSERT INTO %s (%s, rowid, %s) VALUES ('delete', old.id, %s);
+			END`, index.name, index.table, index.name, index.name, columns, oldColumns),
+			fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS %s_au AFTER UPDATE OF %s ON %s BEGIN
+				INSERT INTO %s (%s, rowid, %s) VALUES ('delete', old.id, %s);
+				INSERT INTO %s (rowid, %s) VALUES (new.id, %s);
+			END`, index.name, columns, index.table, index.name, index.name, columns, oldColumns, index.name, columns, newColumns),
+		}
+
+		// Rows written while the triggers were missing, for example by an older version of the server,
+		// a build without FTS5 or while a table was rebuilt, are only indexed by rebuilding the index
+		if !complete {
+			logger.Log.Info().Str("index", index.name).Msg("Rebuilding search index")
+			statements = append(statements, fmt.Sprintf(`INSERT INTO %s (%s) VALUES ('rebuild')`, index.name, index.name))
+		}
+
+		for _, statement := range statements {
+			if _, err := r.db.Exec(statement); err != nil {
+				return fmt.Errorf("failed to create search index %s: %w", index.name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// indexComplete reports whether the index and all of its triggers exist.
+func (r *SearchRepository) indexComplete(name string) (bool, error) {
+
+	names := []any{name}
+	for _, suffix := range searchTriggerSuffixes {
+		names = append(names, name+suffix)
+	}
+
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE name IN (?, ?, ?, ?)`, names...).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up search index %s: %w", name, err)
+	}
+
+	return count == len(names), nil
+}
+
+// DropTriggers removes the triggers that keep the FTS5 indexes in sync, the indexes themselves
+// cannot be touched without the module. They are rebuilt once FTS5 is available again.
+func (r *SearchRepository) DropTriggers() error {
+
+	for _, index := range searchIndexes {
+		for _, suffix := range searchTriggerSuffixes {
+			if _, err := r.db.Exec(fmt.Sprintf(`DROP TRIGGER IF EXISTS %s%s`, index.name, suffix)); err != nil {
+				return fmt.Errorf("failed to drop trigger of search index %s: %w", index.name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Search returns the tasks, requirements and entry notes of the owner that contain every term,
+// the best matches first. Terms also match words they are the beginning of.
+func (r *SearchRepository) Search(ownerID int64, terms []string, limit int) ([]models.SearchHit, error) {
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	if r.fullText {
+		return r.searchFullText(ownerID, terms, limit)
+	}
+	return r.searchLike(ownerID, terms, limit)
+}
+
+func (r *SearchRepository) searchFullText(ownerID int64, terms []string, limit int) ([]models.SearchHit, error) {
+
+	// Every term is quoted, so the words of the user are never read as FTS5 operators
+	quoted := make([]string, 0, len(terms))
+	for _, term := range terms {
+		quoted = append(quoted, `"`+strings.ReplaceAll(term, `"`, `""`)+`"*`)
+	}
+	match := strings.Join(quoted, " ")
+
+	// The entries come first, the type of entry_date is taken from the first SELECT of the union.
+	// Title matches of tasks weigh more than matches in their description. Conditions are left out,
+	// their titles repeat the titles of their atoms.
+	query := `SELECT 'entry' AS kind, t.id, t.title, r.id, e.id, e.entry_date,
+			snippet(entry_notes_fts, 0, ?, ?, '…', ?), bm25(entry_notes_fts) AS rank
+		FROM entry_notes_fts
+		JOIN requirement_entries e ON e.id = entry_notes_fts.rowid
+		JOIN requirements r ON r.id = e.requirement_id
+		JOIN tasks t ON t.id = r.task_id
+		WHERE entry_notes_fts MATCH ? AND t.owner_id = ? AND t.deleted_at IS NULL
+	UNION ALL
+	SELECT 'task', t.id, t.title, NULL, NULL, NULL,
+			snippet(tasks_fts, -1, ?, ?, '…', ?), bm25(tasks_fts, 5.0, 1.0)
+		FROM tasks_fts
+		JOIN tasks t ON t.id = tasks_fts.rowid
+		WHERE tasks_fts MATCH ? AND t.owner_id = ? AND t.deleted_at IS NULL
+	UNION ALL
+	SELECT 'requirement', t.id, t.title, r.id, NULL, NULL,
+			snippet(requirements_fts, 0, ?, ?, '…', ?), bm25(requirements_fts)
+		FROM requirements_fts
+		JOIN requirements r ON r.id = requirements_fts.rowid
+		JOIN tasks t ON t.id = r.task_id
+		WHERE requirements_fts MATCH ? AND r.type = 'atom' AND r.retired = 0 AND t.owner_id = ? AND t.deleted_at IS NULL
+	ORDER BY rank
+	LIMIT ?`
+
+	var args []any
+	for range 3 {
+		args = append(args, SnippetOpen, SnippetClose, snippetWords, match, ownerID)
+	}
+	args = append(args, limit)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search: %w", err)
+	}
+	defer rows.Close()
+
+	var hits []models.SearchHit
+	for rows.Next() {
+		var hit models.SearchHit
+		err := rows.Scan(&hit.Kind, &hit.TaskID, &hit.TaskTitle, &hit.RequirementID, &hit.EntryID, &hit.EntryDate, &hit.Snippet, &hit.Rank)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan search hit: %w", err)
+		}
+		hits = append(hits, hit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after scanning search hits: %w", err)
+	}
+
+	return hits, nil
+}
+
+func (r *SearchRepository) searchLike(ownerID int64, terms []string, limit int) ([]models.SearchHit, error) {
+
+	// Each term has to be in one of the columns, the snippet is made from the column with the most matches
+	where := func(columns ...string) (string, []any) {
+		var conditions []string
+		var args []any
+		for _, term := range terms {
+			pattern := "%" + escapeLike(term) + "%"
+			var alternatives []string
+			for _, column := range columns {
+				alternatives = append(alternatives, column+` LIKE ? ESCAPE '\'`)
+				args = append(args, pattern)
+			}
+			conditions = append(conditions, "("+strings.Join(alternatives, " OR ")+")")
+		}
+		return strings.Join(conditions, " AND "), args
+	}
+
+	taskWhere, taskArgs := where("t.title", "t.description")
+	requirementWhere, requirementArgs := where("r.title")
+	noteWhere, noteArgs := where("e.note")
+
+	query := `SELECT 'entry', t.id, t.title, r.id, e.id, e.entry_date, '', e.note
+		FROM requirement_entries e
+		JOIN requirements r ON r.id = e.requirement_id
+		JOIN tasks t ON t.id = r.task_id
+		WHERE ` + noteWhere + ` AND t.owner_id = ? AND t.deleted_at IS NULL
+	UNION ALL
+	SELECT 'task', t.id, t.title, NULL, NULL, NULL, t.title, t.description
+		FROM tasks t
+		WHERE ` + taskWhere + ` AND t.owner_id = ? AND t.deleted_at IS NULL
+	UNION ALL
+	SELECT 'requirement', t.id, t.title, r.id, NULL, NULL, r.title, NULL
+		FROM requirements r
+		JOIN tasks t ON t.id = r.task_id
+		WHERE ` + requirementWhere + ` AND r.type = 'atom' AND r.retired = 0 AND t.owner_id = ? AND t.deleted_at IS NULL`
+
+	var args []any
+	args = append(append(args, noteArgs...), ownerID)
+	args = append(append(args, taskArgs...), ownerID)
+	args = append(append(args, requirementArgs...), ownerID)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search: %w", err)
+	}
+	defer rows.Close()
+
+	var hits []models.SearchHit
+	for rows.Next() {
+		var hit models.SearchHit
+		var primary string
+		var secondary sql.NullString
+		err := rows.Scan(&hit.Kind, &hit.TaskID, &hit.TaskTitle, &hit.RequirementID, &hit.EntryID, &hit.EntryDate, &primary, &secondary)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan search hit: %w", err)
+		}
+
+		// Titles weigh more like in the FTS5 ranking, ranks are negative so the best match is the lowest
+		primarySnippet, primaryMatches := highlightTerms(primary, terms)
+		secondarySnippet, secondaryMatches := highlightTerms(secondary.String, terms)
+		hit.Snippet = primarySnippet
+		if secondaryMatches > primaryMatches {
+			hit.Snippet = secondarySnippet
+		}
+		hit.Rank = -float64(5*primaryMatches + secondaryMatches)
+
+		hits = append(hits, hit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after scanning search hits: %w", err)
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].Rank < hits[j].Rank })
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+
+	return hits, nil
+}
+
+func escapeLike(term string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(term)
+}
+
+// highlightTerms marks the terms in text like snippet() of FTS5 and returns how many were marked.
+// Long texts are cut to the words around the first match.
+func highlightTerms(text string, terms []string) (string, int) {
+	if text == "" {
+		return "", 0
+	}
+
+	lower := strings.ToLower(text)
+	type match struct{ start, end int }
+	var matches []match
+	for _, term := range terms {
+		term = strings.ToLower(term)
+		for offset := 0; ; {
+			i := strings.Index(lower[offset:], term)
+			if i < 0 {
+				break
+			}
+			matches = append(matches, match{offset + i, offset + i + len(term)})
+			offset += i + len(term)
+		}
+	}
+	// Lowercasing can change the length of some characters, such matches are not marked
+	if len(lower) != len(text) || len(matches) == 0 {
+		return cutWords(text, 0), 0
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].start < matches[j].start })
+
+	var builder strings.Builder
+	last := 0
+	for _, m := range matches {
+		if m.start < last {
+			continue
+		}
+		builder.WriteString(text[last:m.start])
+		builder.WriteString(SnippetOpen)
+		builder.WriteString(text[m.start:m.end])
+		builder.WriteString(SnippetClose)
+		last = m.end
+	}
+	builder.WriteString(text[last:])
+
+	marked := builder.String()
+	return cutWords(marked, strings.Index(marked, SnippetOpen)), len(matches)
+}
+
+// cutWords keeps about snippetWords words of text starting a few words before the byte offset.
+func cutWords(text string, offset int) string {
+	words := strings.Fields(text)
+	if len(words) <= snippetWords {
+		return text
+	}
+
+	first := 0
+	for position, i := 0, 0; i < len(words); i++ {
+		position = strings.Index(text[position:], words[i]) + position
+		if position+len(words[i]) > offset {
+			first = max(0, i-snippetWords/4)
+			break
+		}
+		position += len(words[i])
+	}
+	last := min(len(words), first+snippetWords)
+
+	snippet := strings.Join(words[first:last], " ")
+	if first > 0 {
+		snippet = "…" + snippet
+	}
+	if last < len(words) {
+		snippet += "…"
+	}
+	return snippet
+}
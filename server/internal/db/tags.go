@@ -0,0 +1,252 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/boreymarf/task-fuss/server/internal/apperrors"
+	"github.com/boreymarf/task-fuss/server/internal/logger"
+	"github.com/boreymarf/task-fuss/server/internal/models"
+	"github.com/mattn/go-sqlite3"
+)
+
+type TagRepository struct {
+	db DBTX
+}
+
+func InitTagRepository(db *sql.DB) (*TagRepository, error) {
+
+	repo := &TagRepository{db: db}
+
+	if err := repo.CreateTable(); err != nil {
+		return nil, fmt.Errorf("migration failed: %w", err)
+	}
+
+	logger.Log.Debug().Msg("tagRepository initialization completed")
+
+	return repo, nil
+}
+
+// WithTx returns a copy of the repository that runs its queries in tx.
+func (r *TagRepository) WithTx(tx *sql.Tx) *TagRepository {
+	return &TagRepository{db: tx}
+}
+
+func (r *TagRepository) CreateTable() error {
+	// Names are unique per user regardless of case, "Health" and "health" are the same tag
+	query := `CREATE TABLE IF NOT EXISTS tags (
+	id         INTEGER NOT NULL PRIMARY KEY,
+	owner_id   INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	name       TEXT NOT NULL COLLATE NOCASE,
+	color      TEXT,
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (owner_id, name)
+	)`
+
+	_, err := r.db.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	query = `CREATE TABLE IF NOT EXISTS task_tags (
+	task_id INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+	tag_id  INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+	PRIMARY KEY (task_id, tag_id)
+	)`
+
+	_, err = r.db.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	// Filtering tasks by tag looks the pairs up by tag
+	query = `CREATE INDEX IF NOT EXISTS idx_task_tags_tag ON task_tags (tag_id)`
+
+	_, err = r.db.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CreateTag stores the tag, apperrors.ErrDuplicate is returned if the owner already has a tag with the name.
+func (r *TagRepository) CreateTag(tag *models.Tag) error {
+
+	query := `INSERT INTO tags (owner_id, name, color) VALUES (?, ?, ?) RETURNING id, created_at`
+
+	err := r.db.QueryRow(query, tag.OwnerID, tag.Name, tag.Color).Scan(&tag.ID, &tag.CreatedAt)
+	if err != nil {
+		return duplicateOr(err)
+	}
+
+	return nil
+}
+
+func (r *TagRepository) GetTagByID(id int64) (models.Tag, error) {
+
+	var tag models.Tag
+
+	query := `SELECT id, owner_id, name, color, created_at FROM tags WHERE id = ?`
+
+	err := r.db.QueryRow(query, id).Scan(&tag.ID, &tag.OwnerID, &tag.Name, &tag.Color, &tag.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Tag{}, fmt.Errorf("tag %d not found: %w", id, err)
+	} else if err != nil {
+		return models.Tag{}, err
+	}
+
+	return tag, nil
+}
+
+// GetTagsByOwnerID returns every tag of the user ordered by name.
+func (r *TagRepository) GetTagsByOwnerID(ownerID int64) ([]models.Tag, error) {
+
+	query := `SELECT id, owner_id, name, color, created_at FROM tags WHERE owner_id = ? ORDER BY name, id`
+
+	rows, err := r.db.Query(query, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tags: %w", err)
+	}
+	defer rows.Close()
+
+	var tags []models.Tag
+	for rows.Next() {
+		var tag models.Tag
+		if err := rows.Scan(&tag.ID, &tag.OwnerID, &tag.Name, &tag.Color, &tag.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after scanning tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// UpdateTag stores the name and color of the tag.
+func (r *TagRepository) UpdateTag(tag *models.Tag) error {
+
+	query := `UPDATE tags SET name = ?, color = ? WHERE id = ?`
+
+	result, err := r.db.Exec(query, tag.Name, tag.Color, tag.ID)
+	if err != nil {
+		return duplicateOr(err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("tag %d not found: %w", tag.ID, sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+// DeleteTag deletes the tag and removes it from every task.
+func (r *TagRepository) DeleteTag(id int64) error {
+
+	if _, err := r.db.Exec(`DELETE FROM task_tags WHERE tag_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to untag tasks: %w", err)
+	}
+
+	if _, err := r.db.Exec(`DELETE FROM tags WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+
+	return nil
+}
+
+// SetTaskTags replaces the tags of the task with tagIDs.
+func (r *TagRepository) SetTaskTags(taskID int64, tagIDs []int64) error {
+
+	if _, err := r.db.Exec(`DELETE FROM task_tags WHERE task_id = ?`, taskID); err != nil {
+		return fmt.Errorf("failed to untag task: %w", err)
+	}
+
+	for _, tagID := range tagIDs {
+		if _, err := r.db.Exec(`INSERT OR IGNORE INTO task_tags (task_id, tag_id) VALUES (?, ?)`, taskID, tagID); err != nil {
+			return fmt.Errorf("failed to tag task: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetTagsByTaskIDs returns the tags of every task that has any, ordered by name.
+func (r *TagRepository) GetTagsByTaskIDs(taskIDs []int64) (map[int64][]models.Tag, error) {
+
+	tags := make(map[int64][]models.Tag)
+	if len(taskIDs) == 0 {
+		return tags, nil
+	}
+
+	var stringIDs []string
+	for _, id := range taskIDs {
+		stringIDs = append(stringIDs, strconv.FormatInt(id, 10))
+	}
+
+	query := fmt.Sprintf(`SELECT task_tags.task_id, tags.id, tags.owner_id, tags.name, tags.color, tags.created_at
+		FROM task_tags
+		JOIN tags ON tags.id = task_tags.tag_id
+		WHERE task_tags.task_id IN (%s)
+		ORDER BY tags.name, tags.id`, strings.Join(stringIDs, ", "))
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query task tags: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID int64
+		var tag models.Tag
+		if err := rows.Scan(&taskID, &tag.ID, &tag.OwnerID, &tag.Name, &tag.Color, &tag.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan task tag: %w", err)
+		}
+		tags[taskID] = append(tags[taskID], tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after scanning task tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// DeleteTaskTagsByTaskIDs removes every tag from the tasks, the tags themselves are kept.
+func (r *TagRepository) DeleteTaskTagsByTaskIDs(taskIDs []int64) error {
+
+	if len(taskIDs) == 0 {
+		return nil
+	}
+
+	var stringIDs []string
+	for _, id := range taskIDs {
+		stringIDs = append(stringIDs, strconv.FormatInt(id, 10))
+	}
+
+	query := fmt.Sprintf(`DELETE FROM task_tags WHERE task_id IN (%s)`, strings.Join(stringIDs, ", "))
+
+	if _, err := r.db.Exec(query); err != nil {
+		return fmt.Errorf("failed to untag tasks: %w", err)
+	}
+
+	return nil
+}
+
+// duplicateOr turns a unique constraint violation into apperrors.ErrDuplicate and returns other errors as they are.
+func duplicateOr(err error) error {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+		return apperrors.ErrDuplicate
+	}
+	return err
+}
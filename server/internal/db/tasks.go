@@ -4,15 +4,20 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/boreymarf/task-fuss/server/internal/apperrors"
+	"github.com/boreymarf/task-fuss/server/internal/config"
 	"github.com/boreymarf/task-fuss/server/internal/logger"
 	"github.com/boreymarf/task-fuss/server/internal/models"
 	"github.com/mattn/go-sqlite3"
 )
 
 type TaskRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
 func InitTaskRepository(db *sql.DB) (*TaskRepository, error) {
@@ -23,29 +28,75 @@ func InitTaskRepository(db *sql.DB) (*TaskRepository, error) {
 		return nil, fmt.Errorf("migration failed: %w", err)
 	}
 
+	// The 'completed' status is not allowed by the old CHECK constraint
+	if err := rebuildTableIfOutdated(db, "tasks", tasksTable, "'completed'"); err != nil {
+		return nil, fmt.Errorf("migration failed: %w", err)
+	}
+
+	// Indexes go with the table when it is rebuilt, so they are created after the rebuild
+	if err := repo.createIndexes(); err != nil {
+		return nil, fmt.Errorf("migration failed: %w", err)
+	}
+
+	if err := dropRuleWeekStarts(db); err != nil {
+		return nil, fmt.Errorf("migration failed: %w", err)
+	}
+
 	logger.Log.Debug().Msg("Repository initialization completed")
 
 	return repo, nil
 }
 
-func (r *TaskRepository) CreateTable() error {
-	query := `CREATE TABLE IF NOT EXISTS tasks (
+// WithTx returns a copy of the repository that runs its queries in tx.
+func (r *TaskRepository) WithTx(tx *sql.Tx) *TaskRepository {
+	return &TaskRepository{db: tx}
+}
+
+// tasksTable is the current definition of the table, older databases are rebuilt to match it
+const tasksTable = `CREATE TABLE IF NOT EXISTS tasks (
 		id              INTEGER NOT NULL PRIMARY KEY,
 		owner_id        INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
-		status          VARCHAR(255) NOT NULL DEFAULT 'active' CHECK(status IN ('archived', 'active')),
+		status          VARCHAR(255) NOT NULL DEFAULT 'active' CHECK(status IN ('archived', 'active', 'completed')),
 		title           VARCHAR(255) NOT NULL,
 		description     VARCHAR(255),
 		created_at      DATETIME DEFAULT CURRENT_TIMESTAMP,
 		updated_at      DATETIME DEFAULT CURRENT_TIMESTAMP,
 		start_date      DATETIME DEFAULT CURRENT_TIMESTAMP,
-		end_date        DATETIME DEFAULT NULL
+		end_date        DATETIME DEFAULT NULL,
+		recurrence      TEXT DEFAULT NULL,
+		deleted_at      DATETIME DEFAULT NULL
     )`
 
+func (r *TaskRepository) CreateTable() error {
+	query := tasksTable
+
 	_, err := r.db.Exec(query)
 	if err != nil {
 		return err
 	}
 
+	// NULL means the task is due every day
+	if err := addColumnIfMissing(r.db, "tasks", "recurrence", "TEXT DEFAULT NULL"); err != nil {
+		return err
+	}
+
+	// Tasks in the trash have the time they were deleted, NULL for every other task
+	if err := addColumnIfMissing(r.db, "tasks", "deleted_at", "DATETIME DEFAULT NULL"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *TaskRepository) createIndexes() error {
+	// The task list is read page by page in the order of these keys, the expressions match taskSortKeys
+	for sort, key := range taskSortKeys {
+		query := fmt.Sprintf(`CREATE INDEX IF NOT EXISTS idx_tasks_owner_%s ON tasks (owner_id, %s, id)`, sort, key)
+		if _, err := r.db.Exec(query); err != nil {
+			return fmt.Errorf("failed to create index on %s: %w", sort, err)
+		}
+	}
+
 	return nil
 }
 
@@ -55,9 +106,9 @@ func (r *TaskRepository) CreateTask(task models.Task) (*models.Task, error) {
 		Int64("owner_id", task.OwnerID).
 		Msg("Trying to Create new task to the db...")
 
-	query := `INSERT INTO tasks (owner_id, title, description) VALUES (?, ?, ?)`
+	query := `INSERT INTO tasks (owner_id, title, description, recurrence, start_date) VALUES (?, ?, ?, ?, COALESCE(?, CURRENT_TIMESTAMP))`
 
-	result, err := r.db.Exec(query, task.OwnerID, task.Title, task.Description)
+	result, err := r.db.Exec(query, task.OwnerID, task.Title, task.Description, task.Recurrence, task.StartDate)
 
 	if err != nil {
 		var sqliteErr sqlite3.Error
@@ -83,14 +134,15 @@ func (r *TaskRepository) CreateTask(task models.Task) (*models.Task, error) {
 	return &createdTask, nil
 }
 
+// GetTaskByID returns the task unless it is in the trash.
 func (r *TaskRepository) GetTaskByID(id int64) (models.Task, error) {
 
 	var task models.Task
 	logger.Log.Debug().Int64("id", id).Msg("taskRepository tries to find task")
 
-	query := `SELECT id, owner_id, title, description, created_at, updated_at, start_date, end_date, status
+	query := `SELECT id, owner_id, title, description, created_at, updated_at, start_date, end_date, status, recurrence
 	FROM tasks
-	WHERE id = ?`
+	WHERE id = ? AND deleted_at IS NULL`
 
 	row := r.db.QueryRow(query, id)
 
@@ -104,6 +156,7 @@ func (r *TaskRepository) GetTaskByID(id int64) (models.Task, error) {
 		&task.StartDate,
 		&task.EndDate,
 		&task.Status,
+		&task.Recurrence,
 	)
 
 	if errors.Is(err, sql.ErrNoRows) {
@@ -118,16 +171,101 @@ func (r *TaskRepository) GetTaskByID(id int64) (models.Task, error) {
 	return task, nil
 }
 
+// UpdateTask stores the editable fields of the task and bumps updated_at.
+func (r *TaskRepository) UpdateTask(task *models.Task) error {
+
+	query := `UPDATE tasks SET
+		title = ?,
+		description = ?,
+		status = ?,
+		start_date = ?,
+		end_date = ?,
+		recurrence = ?,
+		updated_at = CURRENT_TIMESTAMP
+	WHERE id = ?`
+
+	result, err := r.db.Exec(
+		query,
+		task.Title,
+		task.Description,
+		task.Status,
+		task.StartDate,
+		task.EndDate,
+		task.Recurrence,
+		task.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("task %d not found: %w", task.ID, sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+// taskSortKeys are the expressions the task list can be sorted by. Times are written the same way
+// whether they come from the CURRENT_TIMESTAMP default or from the driver, so they compare as text.
+// lower() of SQLite only folds ASCII letters.
+var taskSortKeys = map[string]string{
+	"title":      "lower(title)",
+	"created_at": "COALESCE(strftime('%Y-%m-%d %H:%M:%f', created_at), '')",
+	"start_date": "COALESCE(strftime('%Y-%m-%d %H:%M:%f', start_date), '')",
+}
+
 type GetAllTasksOptions struct {
-	DetailLevel  string
-	ShowArchived bool
-	ShowActive   bool
-	UserID       int64
+	DetailLevel   string
+	ShowArchived  bool
+	ShowActive    bool
+	ShowCompleted bool
+	UserID        int64
+	// IncludeTagIDs keeps tasks with any of the tags, or with all of them if MatchAllTags is set
+	IncludeTagIDs []int64
+	MatchAllTags  bool
+	// ExcludeTagIDs drops tasks with any of the tags
+	ExcludeTagIDs []int64
+	// Inclusive day ranges of the start and end dates, nil leaves the side open.
+	// A task without an end date never ends, so it passes EndFrom and fails EndTo.
+	StartFrom *time.Time
+	StartTo   *time.Time
+	EndFrom   *time.Time
+	EndTo     *time.Time
+	// SortBy is "title", "created_at" or "start_date", tasks are ordered by ID without it and ties are
+	// broken by ID. The value of the key is returned in SortKey of every task.
+	SortBy string
+	Desc   bool
+	// After keeps the tasks that come after the key in the sort order, Before the ones that come before it.
+	// With Before the tasks closest to the key are the ones kept by Limit, they are still returned in sort order.
+	After  *TaskKey
+	Before *TaskKey
+	// Limit is the maximum number of tasks returned, 0 returns all of them
+	Limit int
+}
+
+// TaskKey is the position of a task in the sorted task list.
+type TaskKey struct {
+	Value string
+	ID    int64
 }
 
 func (r *TaskRepository) GetAllTasks(opts *GetAllTasksOptions) ([]models.Task, error) {
 
-	query := `SELECT 
+	sortKey := "''"
+	if opts.SortBy != "" {
+		key, ok := taskSortKeys[opts.SortBy]
+		if !ok {
+			return nil, fmt.Errorf("unknown sort key '%s'", opts.SortBy)
+		}
+		sortKey = key
+	}
+
+	where, args := taskFilters(opts)
+	query := fmt.Sprintf(`SELECT
 		id,
 		owner_id,
 		title,
@@ -136,22 +274,42 @@ func (r *TaskRepository) GetAllTasks(opts *GetAllTasksOptions) ([]models.Task, e
 		updated_at,
 		start_date,
 		end_date,
-		status
-	FROM 
+		status,
+		recurrence,
+		%s
+	FROM
 		tasks
-	WHERE 
-		owner_id = ?
-  AND (
-    (status = 'archived' AND ?) 
-    OR 
-    (status = 'active' AND ?)
-  )`
+	WHERE %s`, sortKey, where)
 
-	rows, err := r.db.Query(query,
-		opts.UserID,
-		opts.ShowArchived,
-		opts.ShowActive,
-	)
+	// Reading the page before the key walks the list backwards, the rows are reversed after the scan
+	desc := opts.Desc
+	if opts.Before != nil {
+		desc = !desc
+	}
+	direction, comparison := "ASC", ">"
+	if desc {
+		direction, comparison = "DESC", "<"
+	}
+
+	if key := opts.After; key != nil || opts.Before != nil {
+		if key == nil {
+			key = opts.Before
+		}
+		query += fmt.Sprintf(`
+  AND (%s, id) %s (?, ?)`, sortKey, comparison)
+		args = append(args, key.Value, key.ID)
+	}
+
+	query += fmt.Sprintf(`
+	ORDER BY %s %s, id %s`, sortKey, direction, direction)
+
+	if opts.Limit > 0 {
+		query += `
+	LIMIT ?`
+		args = append(args, opts.Limit)
+	}
+
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		logger.Log.Error().Err(err).Msg("Failed to execute query")
 		return nil, fmt.Errorf("failed to get tasks: %w", err)
@@ -172,6 +330,8 @@ func (r *TaskRepository) GetAllTasks(opts *GetAllTasksOptions) ([]models.Task, e
 			&task.StartDate,
 			&task.EndDate,
 			&task.Status,
+			&task.Recurrence,
+			&task.SortKey,
 		)
 		if err != nil {
 			logger.Log.Error().Err(err).Msg("Failed to scan task row")
@@ -183,11 +343,13 @@ func (r *TaskRepository) GetAllTasks(opts *GetAllTasksOptions) ([]models.Task, e
 			OwnerID: task.OwnerID,
 			Title:   task.Title,
 			Status:  task.Status,
+			SortKey: task.SortKey,
 		}
 
 		if opts.DetailLevel == "basic" || opts.DetailLevel == "full" {
 			filteredTask.StartDate = task.StartDate
 			filteredTask.EndDate = task.EndDate
+			filteredTask.Recurrence = task.Recurrence
 		}
 
 		if opts.DetailLevel == "full" {
@@ -200,5 +362,223 @@ func (r *TaskRepository) GetAllTasks(opts *GetAllTasksOptions) ([]models.Task, e
 
 	}
 
+	if opts.Before != nil {
+		slices.Reverse(tasks)
+	}
+
 	return tasks, nil
 }
+
+// CountTasks counts the tasks that pass the filters of opts, sorting and paging are ignored.
+func (r *TaskRepository) CountTasks(opts *GetAllTasksOptions) (int, error) {
+
+	where, args := taskFilters(opts)
+
+	var count int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM tasks WHERE `+where, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count tasks: %w", err)
+	}
+
+	return count, nil
+}
+
+// taskFilters returns the WHERE clause that keeps the tasks of the owner passing the filters of opts.
+func taskFilters(opts *GetAllTasksOptions) (string, []any) {
+
+	query := `owner_id = ?
+  AND deleted_at IS NULL
+  AND (
+    (status = 'archived' AND ?) 
+    OR 
+    (status = 'active' AND ?)
+    OR
+    (status = 'completed' AND ?)
+  )`
+
+	args := []any{
+		opts.UserID,
+		opts.ShowArchived,
+		opts.ShowActive,
+		opts.ShowCompleted,
+	}
+
+	if len(opts.IncludeTagIDs) > 0 {
+		placeholders, tagArgs := inPlaceholders(opts.IncludeTagIDs)
+		if opts.MatchAllTags {
+			query += fmt.Sprintf(`
+  AND id IN (SELECT task_id FROM task_tags WHERE tag_id IN (%s) GROUP BY task_id HAVING COUNT(*) = ?)`, placeholders)
+			tagArgs = append(tagArgs, countDistinct(opts.IncludeTagIDs))
+		} else {
+			query += fmt.Sprintf(`
+  AND id IN (SELECT task_id FROM task_tags WHERE tag_id IN (%s))`, placeholders)
+		}
+		args = append(args, tagArgs...)
+	}
+
+	if len(opts.ExcludeTagIDs) > 0 {
+		placeholders, tagArgs := inPlaceholders(opts.ExcludeTagIDs)
+		query += fmt.Sprintf(`
+  AND id NOT IN (SELECT task_id FROM task_tags WHERE tag_id IN (%s))`, placeholders)
+		args = append(args, tagArgs...)
+	}
+
+	// date() reads both the CURRENT_TIMESTAMP default and the format the driver stores times in
+	if opts.StartFrom != nil {
+		query += `
+  AND date(start_date) >= ?`
+		args = append(args, opts.StartFrom.Format(config.DateFormat))
+	}
+	if opts.StartTo != nil {
+		query += `
+  AND date(start_date) <= ?`
+		args = append(args, opts.StartTo.Format(config.DateFormat))
+	}
+	if opts.EndFrom != nil {
+		query += `
+  AND (end_date IS NULL OR date(end_date) >= ?)`
+		args = append(args, opts.EndFrom.Format(config.DateFormat))
+	}
+	if opts.EndTo != nil {
+		query += `
+  AND date(end_date) <= ?`
+		args = append(args, opts.EndTo.Format(config.DateFormat))
+	}
+
+	return query, args
+}
+
+// TrashTask moves the task of the owner to the trash.
+func (r *TaskRepository) TrashTask(id int64, ownerID int64) error {
+
+	query := `UPDATE tasks SET deleted_at = CURRENT_TIMESTAMP
+	WHERE id = ? AND owner_id = ? AND deleted_at IS NULL`
+
+	return r.execOnTask(id, query, id, ownerID)
+}
+
+// RestoreTask takes the task of the owner out of the trash.
+func (r *TaskRepository) RestoreTask(id int64, ownerID int64) error {
+
+	query := `UPDATE tasks SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP
+	WHERE id = ? AND owner_id = ? AND deleted_at IS NOT NULL`
+
+	return r.execOnTask(id, query, id, ownerID)
+}
+
+// execOnTask runs a query changing a single task, sql.ErrNoRows is returned when it changed nothing.
+func (r *TaskRepository) execOnTask(id int64, query string, args ...any) error {
+
+	result, err := r.db.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("task %d not found: %w", id, sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+// GetDeletedTasks returns the tasks of the owner that are in the trash, the most recently deleted first.
+func (r *TaskRepository) GetDeletedTasks(ownerID int64) ([]models.Task, error) {
+
+	query := `SELECT id, owner_id, title, description, created_at, updated_at, start_date, end_date, status, recurrence, deleted_at
+	FROM tasks
+	WHERE owner_id = ? AND deleted_at IS NOT NULL
+	ORDER BY deleted_at DESC, id DESC`
+
+	return r.queryDeletedTasks(query, ownerID)
+}
+
+// GetTasksDeletedBefore returns the tasks of every user that were moved to the trash before the time.
+func (r *TaskRepository) GetTasksDeletedBefore(before time.Time) ([]models.Task, error) {
+
+	query := `SELECT id, owner_id, title, description, created_at, updated_at, start_date, end_date, status, recurrence, deleted_at
+	FROM tasks
+	WHERE deleted_at IS NOT NULL AND deleted_at < ?
+	ORDER BY id`
+
+	return r.queryDeletedTasks(query, before.UTC())
+}
+
+func (r *TaskRepository) queryDeletedTasks(query string, args ...any) ([]models.Task, error) {
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query deleted tasks: %w", err)
+	}
+	defer rows.Close()
+
+	var tasks []models.Task
+	for rows.Next() {
+		var task models.Task
+		err := rows.Scan(
+			&task.ID,
+			&task.OwnerID,
+			&task.Title,
+			&task.Description,
+			&task.CreatedAt,
+			&task.UpdatedAt,
+			&task.StartDate,
+			&task.EndDate,
+			&task.Status,
+			&task.Recurrence,
+			&task.DeletedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan deleted task: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after scanning deleted tasks: %w", err)
+	}
+
+	return tasks, nil
+}
+
+// DeleteTasks removes the tasks for good, everything else belonging to them has to be deleted separately.
+func (r *TaskRepository) DeleteTasks(ids []int64) error {
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var stringIDs []string
+	for _, id := range ids {
+		stringIDs = append(stringIDs, strconv.FormatInt(id, 10))
+	}
+
+	query := fmt.Sprintf(`DELETE FROM tasks WHERE id IN (%s)`, strings.Join(stringIDs, ", "))
+
+	if _, err := r.db.Exec(query); err != nil {
+		return fmt.Errorf("failed to delete tasks: %w", err)
+	}
+
+	return nil
+}
+
+// inPlaceholders returns "?, ?, ?" for the IDs together with the IDs as query arguments.
+func inPlaceholders(ids []int64) (string, []any) {
+	placeholders := make([]string, 0, len(ids))
+	args := make([]any, 0, len(ids))
+	for _, id := range ids {
+		placeholders = append(placeholders, "?")
+		args = append(args, id)
+	}
+	return strings.Join(placeholders, ", "), args
+}
+
+func countDistinct(ids []int64) int {
+	seen := make(map[int64]bool)
+	for _, id := range ids {
+		seen[id] = true
+	}
+	return len(seen)
+}
@@ -3,12 +3,16 @@ package db
 import (
 	"database/sql"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/boreymarf/task-fuss/server/internal/logger"
+	"github.com/boreymarf/task-fuss/server/internal/models"
 )
 
 type TaskEntryRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
 func InitTaskEntryRepository(db *sql.DB) (*TaskEntryRepository, error) {
@@ -24,12 +28,18 @@ func InitTaskEntryRepository(db *sql.DB) (*TaskEntryRepository, error) {
 	return repo, nil
 }
 
+// WithTx returns a copy of the repository that runs its queries in tx.
+func (r *TaskEntryRepository) WithTx(tx *sql.Tx) *TaskEntryRepository {
+	return &TaskEntryRepository{db: tx}
+}
+
 func (r *TaskEntryRepository) CreateTable() error {
 	query := `CREATE TABLE IF NOT EXISTS task_entries (
 	id 						INTEGER NOT NULL PRIMARY KEY,
 	task_id 			INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
 	entry_date 		DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
-	completed			BOOLEAN NOT NULL DEFAULT FALSE CHECK (completed IN (0, 1))
+	completed			BOOLEAN NOT NULL DEFAULT FALSE CHECK (completed IN (0, 1)),
+	score 				REAL NOT NULL DEFAULT 0 CHECK (score BETWEEN 0 AND 1)
 	)`
 
 	_, err := r.db.Exec(query)
@@ -37,5 +47,118 @@ func (r *TaskEntryRepository) CreateTable() error {
 		return err
 	}
 
+	if err := addColumnIfMissing(r.db, "task_entries", "score", "REAL NOT NULL DEFAULT 0 CHECK (score BETWEEN 0 AND 1)"); err != nil {
+		return err
+	}
+
+	// One row per task per day, entry_date is always stored as the start of the day
+	query = `CREATE UNIQUE INDEX IF NOT EXISTS idx_task_entries_task_day ON task_entries (task_id, entry_date)`
+
+	_, err = r.db.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpsertTaskEntry creates the entry of the task for the day or updates the existing one.
+func (r *TaskEntryRepository) UpsertTaskEntry(entry *models.TaskEntry) error {
+	logger.Log.Debug().
+		Int64("task_id", entry.TaskID).
+		Time("entry_date", entry.EntryDate).
+		Bool("completed", entry.Completed).
+		Float64("score", entry.Score).
+		Msg("Trying to upsert task entry to the db...")
+
+	query := `INSERT INTO task_entries (task_id, entry_date, completed, score) VALUES (?, ?, ?, ?)
+	ON CONFLICT (task_id, entry_date) DO UPDATE SET completed = excluded.completed, score = excluded.score
+	RETURNING id`
+
+	err := r.db.QueryRow(query, entry.TaskID, entry.EntryDate.UTC(), entry.Completed, entry.Score).Scan(&entry.ID)
+	if err != nil {
+		return fmt.Errorf("failed to upsert task entry: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteTaskEntry deletes the outcome of the task for the day, if there is one.
+func (r *TaskEntryRepository) DeleteTaskEntry(taskID int64, date time.Time) error {
+
+	query := `DELETE FROM task_entries WHERE task_id = ? AND entry_date = ?`
+
+	if _, err := r.db.Exec(query, taskID, date.UTC()); err != nil {
+		return fmt.Errorf("failed to delete task entry: %w", err)
+	}
+
+	return nil
+}
+
+// GetTaskEntriesByTaskIDs returns entries of the given tasks for days in [from, to) ordered by date.
+func (r *TaskEntryRepository) GetTaskEntriesByTaskIDs(taskIDs []int64, from time.Time, to time.Time) ([]models.TaskEntry, error) {
+
+	if len(taskIDs) == 0 {
+		return nil, nil
+	}
+
+	var stringIDs []string
+	for _, id := range taskIDs {
+		stringIDs = append(stringIDs, strconv.FormatInt(id, 10))
+	}
+	idQuery := strings.Join(stringIDs, ", ")
+
+	query := fmt.Sprintf(`SELECT id, task_id, entry_date, completed, score
+		FROM task_entries
+		WHERE task_id IN (%s) AND entry_date >= ? AND entry_date < ?
+		ORDER BY task_id, entry_date`, idQuery)
+
+	rows, err := r.db.Query(query, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query task entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []models.TaskEntry
+	for rows.Next() {
+		var entry models.TaskEntry
+		err := rows.Scan(
+			&entry.ID,
+			&entry.TaskID,
+			&entry.EntryDate,
+			&entry.Completed,
+			&entry.Score,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan task entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after scanning task entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// DeleteTaskEntriesByTaskIDs deletes every stored outcome of the tasks.
+func (r *TaskEntryRepository) DeleteTaskEntriesByTaskIDs(taskIDs []int64) error {
+
+	if len(taskIDs) == 0 {
+		return nil
+	}
+
+	var stringIDs []string
+	for _, id := range taskIDs {
+		stringIDs = append(stringIDs, strconv.FormatInt(id, 10))
+	}
+
+	query := fmt.Sprintf(`DELETE FROM task_entries WHERE task_id IN (%s)`, strings.Join(stringIDs, ", "))
+
+	if _, err := r.db.Exec(query); err != nil {
+		return fmt.Errorf("failed to delete task entries: %w", err)
+	}
+
 	return nil
 }
@@ -0,0 +1,70 @@
+package db
+
+import (
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	database, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	return database
+}
+
+// An upgraded database has to have the sort indexes from the first start, the rebuild drops the old ones.
+func TestInitTaskRepositoryIndexesAfterRebuild(t *testing.T) {
+	database := openTestDB(t)
+
+	// The table as it was before the 'completed' status
+	_, err := database.Exec(`CREATE TABLE tasks (
+		id          INTEGER NOT NULL PRIMARY KEY,
+		owner_id    INTEGER NOT NULL,
+		status      VARCHAR(255) NOT NULL DEFAULT 'active' CHECK(status IN ('archived', 'active')),
+		title       VARCHAR(255) NOT NULL,
+		description VARCHAR(255),
+		created_at  DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at  DATETIME DEFAULT CURRENT_TIMESTAMP,
+		start_date  DATETIME DEFAULT CURRENT_TIMESTAMP,
+		end_date    DATETIME DEFAULT NULL
+	)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := database.Exec(`INSERT INTO tasks (owner_id, title) VALUES (1, 'old task')`); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := InitTaskRepository(database); err != nil {
+		t.Fatalf("InitTaskRepository returned error: %v", err)
+	}
+
+	var definition string
+	if err := database.QueryRow(`SELECT sql FROM sqlite_master WHERE type = 'table' AND name = 'tasks'`).Scan(&definition); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(definition, "'completed'") {
+		t.Fatalf("tasks was not rebuilt: %s", definition)
+	}
+
+	for sort := range taskSortKeys {
+		var count int
+		err := database.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'index' AND name = ?`, "idx_tasks_owner_"+sort).Scan(&count)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != 1 {
+			t.Errorf("index on %s is missing after the rebuild", sort)
+		}
+	}
+
+	var title string
+	if err := database.QueryRow(`SELECT title FROM tasks`).Scan(&title); err != nil || title != "old task" {
+		t.Errorf("task was not kept by the rebuild: %q, %v", title, err)
+	}
+}
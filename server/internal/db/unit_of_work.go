@@ -0,0 +1,56 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/boreymarf/task-fuss/server/internal/logger"
+)
+
+// DBTX is implemented by both *sql.DB and *sql.Tx, so repositories can run
+// their queries either directly or as a part of a transaction.
+type DBTX interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// UnitOfWork groups changes made by several repositories into one transaction.
+// Repositories join the transaction through their WithTx methods.
+type UnitOfWork struct {
+	db *sql.DB
+}
+
+func InitUnitOfWork(db *sql.DB) (*UnitOfWork, error) {
+	return &UnitOfWork{db: db}, nil
+}
+
+// Do runs fn inside a transaction. The transaction is committed if fn returns nil
+// and rolled back if it returns an error or panics.
+func (u *UnitOfWork) Do(fn func(tx *sql.Tx) error) (err error) {
+
+	tx, err := u.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			logger.Log.Error().Err(rbErr).Msg("Failed to roll back transaction")
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -12,7 +12,7 @@ import (
 )
 
 type UserRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
 func InitUserRepository(db *sql.DB) (*UserRepository, error) {
@@ -27,6 +27,11 @@ func InitUserRepository(db *sql.DB) (*UserRepository, error) {
 	return repo, nil
 }
 
+// WithTx returns a copy of the repository that runs its queries in tx.
+func (r *UserRepository) WithTx(tx *sql.Tx) *UserRepository {
+	return &UserRepository{db: tx}
+}
+
 func (r *UserRepository) CreateTable() error {
 	query := `CREATE TABLE IF NOT EXISTS users (
 	id 				INTEGER NOT NULL PRIMARY KEY,
@@ -34,7 +39,10 @@ func (r *UserRepository) CreateTable() error {
 	email 				VARCHAR(255) UNIQUE NOT NULL,
 	password_hash VARCHAR(255) NOT NULL,
 	created_at 		DATETIME DEFAULT CURRENT_TIMESTAMP,
-	updated_at 		DATETIME DEFAULT CURRENT_TIMESTAMP
+	updated_at 		DATETIME DEFAULT CURRENT_TIMESTAMP,
+	week_start 		INTEGER NOT NULL DEFAULT 1 CHECK (week_start BETWEEN 0 AND 6),
+	timezone 			TEXT NOT NULL DEFAULT 'UTC',
+	day_end_hour 	INTEGER NOT NULL DEFAULT 0 CHECK (day_end_hour BETWEEN 0 AND 23)
 	)`
 
 	_, err := r.db.Exec(query)
@@ -42,6 +50,20 @@ func (r *UserRepository) CreateTable() error {
 		return err
 	}
 
+	// 0 is Sunday like time.Weekday, weeks start on Monday by default
+	if err := addColumnIfMissing(r.db, "users", "week_start", "INTEGER NOT NULL DEFAULT 1 CHECK (week_start BETWEEN 0 AND 6)"); err != nil {
+		return err
+	}
+
+	// Days are split at midnight UTC unless the user says otherwise
+	if err := addColumnIfMissing(r.db, "users", "timezone", "TEXT NOT NULL DEFAULT 'UTC'"); err != nil {
+		return err
+	}
+
+	if err := addColumnIfMissing(r.db, "users", "day_end_hour", "INTEGER NOT NULL DEFAULT 0 CHECK (day_end_hour BETWEEN 0 AND 23)"); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -87,7 +109,7 @@ func (r *UserRepository) GetUserByID(id int64, user *models.User) error {
 
 	logger.Log.Debug().Int64("id", id).Msg("UserRepository tries to find user")
 
-	query := `SELECT id, name, password_hash, email, created_at, updated_at 
+	query := `SELECT id, name, password_hash, email, created_at, updated_at, week_start, timezone, day_end_hour
 	FROM users 
 	WHERE id = ?`
 
@@ -100,6 +122,9 @@ func (r *UserRepository) GetUserByID(id int64, user *models.User) error {
 		&user.Email,
 		&user.CreatedAt,
 		&user.UpdatedAt,
+		&user.WeekStart,
+		&user.Timezone,
+		&user.DayEndHour,
 	)
 
 	if errors.Is(err, sql.ErrNoRows) {
@@ -118,7 +143,7 @@ func (r *UserRepository) GetUserByEmail(email string, user *models.User) error {
 
 	logger.Log.Debug().Str("email", email).Msg("UserRepository tries to find user")
 
-	query := `SELECT id, name, password_hash, email, created_at, updated_at 
+	query := `SELECT id, name, password_hash, email, created_at, updated_at, week_start, timezone, day_end_hour
 	FROM users 
 	WHERE email = ? COLLATE NOCASE`
 
@@ -131,6 +156,9 @@ func (r *UserRepository) GetUserByEmail(email string, user *models.User) error {
 		&user.Email,
 		&user.CreatedAt,
 		&user.UpdatedAt,
+		&user.WeekStart,
+		&user.Timezone,
+		&user.DayEndHour,
 	)
 
 	if errors.Is(err, sql.ErrNoRows) {
@@ -182,6 +210,27 @@ func (r *UserRepository) GetAllUsers() ([]models.User, error) {
 	return users, nil
 }
 
+// UpdateSettings stores the settings of the user, the rest of the fields are not changed.
+func (r *UserRepository) UpdateSettings(user *models.User) error {
+
+	query := `UPDATE users SET week_start = ?, timezone = ?, day_end_hour = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`
+
+	result, err := r.db.Exec(query, user.WeekStart, user.Timezone, user.DayEndHour, user.ID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("user %d not found: %w", user.ID, sql.ErrNoRows)
+	}
+
+	return nil
+}
+
 func (r *UserRepository) Exists(userID int64) (bool, error) {
 	query := `SELECT EXISTS(SELECT 1 FROM users WHERE id = ?)`
 	var exists bool
@@ -0,0 +1,168 @@
+package dsl
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/boreymarf/task-fuss/server/internal/dto"
+	"github.com/boreymarf/task-fuss/server/internal/duration"
+	"github.com/boreymarf/task-fuss/server/internal/timeofday"
+)
+
+// Format turns a requirement tree into its canonical text form, Parse(Format(r)) gives the same tree.
+// Atoms with data type "none" or "text" have no syntax of their own and are written as bare names,
+// ratings are written as integers and choices with a quoted target, which Parse does not accept.
+// Score modes are not a part of the language, conditions always get the default one.
+func Format(r *dto.Requirement) string {
+	var sb strings.Builder
+	writeNode(&sb, r)
+	return sb.String()
+}
+
+func writeNode(sb *strings.Builder, r *dto.Requirement) {
+	if r.Type != "condition" {
+		writeAtom(sb, r)
+		return
+	}
+
+	operator := ""
+	if r.Operator != nil {
+		operator = *r.Operator
+	}
+
+	if operator == "not" {
+		sb.WriteString("not ")
+		for i := range r.Operands {
+			writeOperand(sb, operator, &r.Operands[i])
+		}
+		return
+	}
+
+	for i := range r.Operands {
+		if i > 0 {
+			sb.WriteString(" " + operator + " ")
+		}
+		writeOperand(sb, operator, &r.Operands[i])
+	}
+}
+
+// writeOperand wraps the operand in parentheses when it would otherwise bind differently.
+func writeOperand(sb *strings.Builder, parentOperator string, operand *dto.Requirement) {
+	needParens := false
+
+	if operand.Type == "condition" && operand.Operator != nil {
+		switch *operand.Operator {
+		case "or":
+			needParens = true
+		case "and":
+			needParens = parentOperator != "or"
+		}
+	}
+
+	if needParens {
+		sb.WriteString("(")
+		writeNode(sb, operand)
+		sb.WriteString(")")
+	} else {
+		writeNode(sb, operand)
+	}
+}
+
+func writeAtom(sb *strings.Builder, r *dto.Requirement) {
+	aggregation := "last"
+	if r.Aggregation != nil {
+		aggregation = *r.Aggregation
+	}
+
+	dataType := "none"
+	if r.DataType != nil {
+		dataType = *r.DataType
+	}
+
+	if aggregation != "last" {
+		sb.WriteString(aggregation + "(" + formatName(r.Title) + ")")
+	} else {
+		sb.WriteString(formatName(r.Title))
+	}
+
+	// Counts are compared as integers whatever the data type is
+	if aggregation == "count" {
+		dataType = "int"
+	}
+
+	operator := "=="
+	if r.Operator != nil {
+		operator = *r.Operator
+	}
+
+	if dataType == "none" || dataType == "text" || r.TargetValue == nil {
+		return
+	}
+
+	target := *r.TargetValue
+
+	switch dataType {
+	case "bool":
+		if b, err := strconv.ParseBool(target); err == nil {
+			target = strconv.FormatBool(b)
+		}
+		// A period can only follow a comparison, so the full form is kept then
+		if operator == "==" && target == "true" && r.Period == nil {
+			return
+		}
+	case "float":
+		if !strings.ContainsAny(target, ".eE") {
+			target += ".0"
+		}
+	case "choice":
+		target = quote(target)
+	case "time":
+		if t, err := timeofday.Parse(target); err == nil {
+			target = timeofday.Format(t)
+		}
+	case "duration":
+		if d, err := duration.Parse(target); err == nil {
+			target = duration.Format(d)
+		}
+	}
+
+	sb.WriteString(" " + operator + " " + target)
+
+	if r.Unit != nil && aggregation != "count" {
+		sb.WriteString(" " + *r.Unit)
+	}
+
+	if r.Period != nil {
+		switch *r.Period {
+		case "week", "month":
+			sb.WriteString(" per " + *r.Period)
+		case "rolling":
+			if r.PeriodDays != nil {
+				sb.WriteString(" per " + strconv.Itoa(*r.PeriodDays) + " days")
+			}
+		}
+	}
+}
+
+// formatName returns the name as is when it is a valid identifier, otherwise quotes it.
+func formatName(name string) string {
+	isIdent := name != ""
+	for i, r := range name {
+		if (i == 0 && !isIdentStart(r)) || !isIdentPart(r) {
+			isIdent = false
+			break
+		}
+	}
+
+	if _, isKeyword := keywords[strings.ToLower(name)]; isIdent && !isKeyword {
+		return name
+	}
+
+	return quote(name)
+}
+
+func quote(s string) string {
+	escaped := strings.ReplaceAll(s, `\`, `\\`)
+	escaped = strings.ReplaceAll(escaped, `"`, `\"`)
+	return `"` + escaped + `"`
+}
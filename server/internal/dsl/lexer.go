@@ -0,0 +1,279 @@
+package dsl
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/boreymarf/task-fuss/server/internal/duration"
+	"github.com/boreymarf/task-fuss/server/internal/timeofday"
+	"github.com/boreymarf/task-fuss/server/internal/units"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenDuration
+	tokenTime
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenTrue
+	tokenFalse
+)
+
+func (k tokenKind) String() string {
+	switch k {
+	case tokenEOF:
+		return "end of input"
+	case tokenIdent:
+		return "name"
+	case tokenString:
+		return "quoted name"
+	case tokenNumber:
+		return "number"
+	case tokenDuration:
+		return "duration"
+	case tokenTime:
+		return "time"
+	case tokenOperator:
+		return "comparison operator"
+	case tokenLParen:
+		return "'('"
+	case tokenRParen:
+		return "')'"
+	case tokenAnd:
+		return "'and'"
+	case tokenOr:
+		return "'or'"
+	case tokenNot:
+		return "'not'"
+	case tokenTrue, tokenFalse:
+		return "boolean"
+	default:
+		return "unknown token"
+	}
+}
+
+type token struct {
+	kind   tokenKind
+	text   string
+	line   int
+	column int
+}
+
+var keywords = map[string]tokenKind{
+	"and":   tokenAnd,
+	"or":    tokenOr,
+	"not":   tokenNot,
+	"true":  tokenTrue,
+	"false": tokenFalse,
+}
+
+// aggregations are not reserved, they are only special when followed by "("
+var aggregations = map[string]struct{}{
+	"last":  {},
+	"sum":   {},
+	"count": {},
+	"max":   {},
+	"min":   {},
+}
+
+type lexer struct {
+	src    []rune
+	pos    int
+	line   int
+	column int
+}
+
+func newLexer(src string) *lexer {
+	return &lexer{src: []rune(src), line: 1, column: 1}
+}
+
+func (l *lexer) peekRune(offset int) rune {
+	if l.pos+offset >= len(l.src) {
+		return 0
+	}
+	return l.src[l.pos+offset]
+}
+
+func (l *lexer) advance() rune {
+	r := l.src[l.pos]
+	l.pos++
+	if r == '\n' {
+		l.line++
+		l.column = 1
+	} else {
+		l.column++
+	}
+	return r
+}
+
+func (l *lexer) errorf(line int, column int, format string, args ...any) *Error {
+	return &Error{Line: line, Column: column, Message: fmt.Sprintf(format, args...)}
+}
+
+// tokenize splits the whole source into tokens, the last one is always tokenEOF.
+func (l *lexer) tokenize() ([]token, error) {
+	var tokens []token
+
+	for {
+		for l.pos < len(l.src) && unicode.IsSpace(l.src[l.pos]) {
+			l.advance()
+		}
+
+		line, column := l.line, l.column
+
+		if l.pos >= len(l.src) {
+			tokens = append(tokens, token{kind: tokenEOF, line: line, column: column})
+			return tokens, nil
+		}
+
+		r := l.peekRune(0)
+
+		switch {
+		case r == '(':
+			l.advance()
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", line: line, column: column})
+
+		case r == ')':
+			l.advance()
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", line: line, column: column})
+
+		case strings.ContainsRune("=!<>", r):
+			op := string(l.advance())
+			if l.peekRune(0) == '=' {
+				op += string(l.advance())
+			}
+			switch op {
+			case "=":
+				op = "=="
+			case "!":
+				return nil, l.errorf(line, column, "unexpected '!', use 'not' or '!='")
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: op, line: line, column: column})
+
+		case r == '"':
+			text, err := l.readString()
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text, line: line, column: column})
+
+		case unicode.IsDigit(r) || (r == '-' && unicode.IsDigit(l.peekRune(1))) || (r == '.' && unicode.IsDigit(l.peekRune(1))):
+			tok, err := l.readNumber()
+			if err != nil {
+				return nil, err
+			}
+			tok.line, tok.column = line, column
+			tokens = append(tokens, tok)
+
+		case isIdentStart(r):
+			var sb strings.Builder
+			for l.pos < len(l.src) && isIdentPart(l.src[l.pos]) {
+				sb.WriteRune(l.advance())
+			}
+			text := sb.String()
+			kind := tokenIdent
+			if keyword, ok := keywords[strings.ToLower(text)]; ok {
+				kind = keyword
+			}
+			tokens = append(tokens, token{kind: kind, text: text, line: line, column: column})
+
+		default:
+			return nil, l.errorf(line, column, "unexpected character '%c'", r)
+		}
+	}
+}
+
+func (l *lexer) readString() (string, error) {
+	line, column := l.line, l.column
+	l.advance() // opening quote
+
+	var sb strings.Builder
+	for {
+		if l.pos >= len(l.src) {
+			return "", l.errorf(line, column, "unterminated quoted name")
+		}
+
+		r := l.advance()
+		switch r {
+		case '"':
+			return sb.String(), nil
+		case '\\':
+			if l.pos >= len(l.src) {
+				return "", l.errorf(line, column, "unterminated quoted name")
+			}
+			escaped := l.advance()
+			if escaped != '"' && escaped != '\\' {
+				return "", l.errorf(l.line, l.column-2, "unknown escape sequence '\\%c'", escaped)
+			}
+			sb.WriteRune(escaped)
+		case '\n':
+			return "", l.errorf(line, column, "unterminated quoted name")
+		default:
+			sb.WriteRune(r)
+		}
+	}
+}
+
+// readNumber reads integers, decimals, durations like "30m" or "1h30m" and times of day like "07:00".
+func (l *lexer) readNumber() (token, error) {
+	line, column := l.line, l.column
+
+	var sb strings.Builder
+	if l.peekRune(0) == '-' {
+		sb.WriteRune(l.advance())
+	}
+
+	kind := tokenNumber
+	for l.pos < len(l.src) {
+		r := l.src[l.pos]
+		if unicode.IsDigit(r) || r == '.' {
+			sb.WriteRune(l.advance())
+		} else if unicode.IsLetter(r) {
+			kind = tokenDuration
+			sb.WriteRune(l.advance())
+		} else if r == ':' && unicode.IsDigit(l.peekRune(1)) {
+			kind = tokenTime
+			sb.WriteRune(l.advance())
+		} else {
+			break
+		}
+	}
+
+	text := sb.String()
+	if kind == tokenDuration {
+		if _, err := duration.Parse(text); err != nil {
+			if _, unit := units.Split(text); unit != "" {
+				if _, ok := units.Lookup(unit); ok {
+					return token{}, l.errorf(line, column, "invalid duration '%s', put a space between a number and its unit", text)
+				}
+			}
+			return token{}, l.errorf(line, column, "invalid duration '%s'", text)
+		}
+	} else if kind == tokenTime {
+		if _, err := timeofday.Parse(text); err != nil {
+			return token{}, l.errorf(line, column, "invalid time of day '%s', use HH:MM or HH:MM:SS", text)
+		}
+	} else if strings.Count(text, ".") > 1 {
+		return token{}, l.errorf(line, column, "invalid number '%s'", text)
+	}
+
+	return token{kind: kind, text: text}, nil
+}
+
+func isIdentStart(r rune) bool {
+	return unicode.IsLetter(r) || r == '_'
+}
+
+func isIdentPart(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
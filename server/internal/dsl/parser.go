@@ -0,0 +1,348 @@
+// Package dsl implements a small text language for requirement trees.
+//
+// Grammar, from the lowest precedence to the highest:
+//
+//	expr    = and { "or" and }
+//	and     = unary { "and" unary }
+//	unary   = "not" unary | primary
+//	primary = "(" expr ")" | aggregation "(" name ")" comparison | name [ comparison ]
+//	comparison = operator value [ unit ] [ "per" period ]
+//	name    = identifier | quoted string
+//	value   = integer | decimal | duration | time | "true" | "false"
+//	aggregation = "sum" | "count" | "max" | "min" | "last"
+//	period  = "day" | "week" | "month" | integer "days"
+//
+// The data type of an atom is inferred from its value: "pushups >= 50" is an int,
+// "weight <= 80.5" is a float, "run >= 30m" is a duration, "woke_up <= 07:00" is
+// a time of day and a bare name like "meditated" is a bool that has to be true.
+// Choices, text and ratings cannot be told apart from other types by their values,
+// such atoms have to be created as a tree.
+//
+// Atoms logged several times a day can be aggregated: "sum(water) >= 2000" adds
+// all the entries of the day up and "count(coffee) <= 2" counts them. A period
+// widens the entries to the whole week, month or the last N days:
+// "count(gym) >= 3 per week", "sum(pages) >= 300 per 7 days".
+//
+// Numbers can be followed by a unit, "sum(run) >= 20 km per week" also accepts runs
+// logged in miles. The unit has to be separated from the number by a space, because
+// "5m" is a duration of five minutes.
+package dsl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/boreymarf/task-fuss/server/internal/dto"
+	"github.com/boreymarf/task-fuss/server/internal/duration"
+	"github.com/boreymarf/task-fuss/server/internal/timeofday"
+	"github.com/boreymarf/task-fuss/server/internal/units"
+)
+
+// Error is a syntax error with a position in the source, both are 1-based.
+type Error struct {
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Message)
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// Parse turns the expression into a requirement tree ready to be passed to TaskService.CreateTask.
+func Parse(src string) (*dto.Requirement, error) {
+	tokens, err := newLexer(src).tokenize()
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+
+	if p.peek().kind == tokenEOF {
+		return nil, p.errorf(p.peek(), "expression is empty")
+	}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, p.errorf(tok, "unexpected %s", describe(tok))
+	}
+
+	return root, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) errorf(tok token, format string, args ...any) *Error {
+	return &Error{Line: tok.line, Column: tok.column, Message: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) parseOr() (*dto.Requirement, error) {
+	return p.parseChain(tokenOr, "or", p.parseAnd)
+}
+
+func (p *parser) parseAnd() (*dto.Requirement, error) {
+	return p.parseChain(tokenAnd, "and", p.parseUnary)
+}
+
+// parseChain collects "a op b op c" into a single condition with three operands.
+func (p *parser) parseChain(kind tokenKind, operator string, operand func() (*dto.Requirement, error)) (*dto.Requirement, error) {
+	first, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.peek().kind != kind {
+		return first, nil
+	}
+
+	operands := []*dto.Requirement{first}
+	for p.peek().kind == kind {
+		p.next()
+		next, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, next)
+	}
+
+	return newCondition(operator, operands), nil
+}
+
+func (p *parser) parseUnary() (*dto.Requirement, error) {
+	if p.peek().kind == tokenNot {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return newCondition("not", []*dto.Requirement{operand}), nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (*dto.Requirement, error) {
+	tok := p.next()
+
+	switch tok.kind {
+	case tokenLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, p.errorf(closing, "expected ')' to close '(' at line %d, column %d, got %s", tok.line, tok.column, describe(closing))
+		}
+		return inner, nil
+
+	case tokenIdent, tokenString:
+		if _, isAggregation := aggregations[strings.ToLower(tok.text)]; isAggregation && tok.kind == tokenIdent && p.peek().kind == tokenLParen {
+			return p.parseAggregation(tok)
+		}
+		if strings.TrimSpace(tok.text) == "" {
+			return nil, p.errorf(tok, "name cannot be empty")
+		}
+		return p.parseAtom(tok.text)
+
+	default:
+		return nil, p.errorf(tok, "expected a name, 'not' or '(', got %s", describe(tok))
+	}
+}
+
+func (p *parser) parseAtom(name string) (*dto.Requirement, error) {
+	atom := &dto.Requirement{
+		Title: name,
+		Type:  "atom",
+	}
+
+	if p.peek().kind != tokenOperator {
+		dataType := "bool"
+		atom.DataType = &dataType
+		return atom, nil
+	}
+
+	opToken := p.next()
+	operator := opToken.text
+	atom.Operator = &operator
+
+	valueToken := p.next()
+
+	var dataType, target string
+	switch valueToken.kind {
+	case tokenNumber:
+		target = valueToken.text
+		dataType = "int"
+		if strings.Contains(target, ".") {
+			dataType = "float"
+		}
+	case tokenDuration:
+		d, _ := duration.Parse(valueToken.text)
+		target = duration.Canonical(d)
+		dataType = "duration"
+	case tokenTime:
+		t, _ := timeofday.Parse(valueToken.text)
+		target = timeofday.Format(t)
+		dataType = "time"
+	case tokenTrue, tokenFalse:
+		target = strings.ToLower(valueToken.text)
+		dataType = "bool"
+		if operator != "==" && operator != "!=" {
+			return nil, p.errorf(opToken, "operator '%s' cannot be used with a boolean, use '==' or '!='", operator)
+		}
+	case tokenString:
+		// The options of a choice cannot be declared in the language
+		return nil, p.errorf(valueToken, "choices are not supported in expressions, create the requirement as a tree")
+	default:
+		return nil, p.errorf(valueToken, "expected a value after '%s', got %s", operator, describe(valueToken))
+	}
+
+	atom.DataType = &dataType
+	atom.TargetValue = &target
+
+	if tok := p.peek(); valueToken.kind == tokenNumber && tok.kind == tokenIdent && !strings.EqualFold(tok.text, "per") {
+		unit, ok := units.Lookup(tok.text)
+		if !ok {
+			return nil, p.errorf(tok, "unknown unit '%s'", tok.text)
+		}
+		p.next()
+		atom.Unit = &unit.Symbol
+	}
+
+	if tok := p.peek(); tok.kind == tokenIdent && strings.EqualFold(tok.text, "per") {
+		p.next()
+		if err := p.parsePeriod(atom); err != nil {
+			return nil, err
+		}
+	}
+
+	return atom, nil
+}
+
+// parsePeriod parses what follows "per": "week", "month", "day" or "N days".
+func (p *parser) parsePeriod(atom *dto.Requirement) error {
+	tok := p.next()
+
+	var period string
+	switch {
+	case tok.kind == tokenIdent && isPeriodName(tok.text):
+		period = strings.ToLower(tok.text)
+
+	case tok.kind == tokenNumber:
+		days, err := strconv.Atoi(tok.text)
+		if err != nil || days < 1 {
+			return p.errorf(tok, "number of days must be a positive integer, got '%s'", tok.text)
+		}
+		if unit := p.next(); unit.kind != tokenIdent || (!strings.EqualFold(unit.text, "days") && !strings.EqualFold(unit.text, "day")) {
+			return p.errorf(unit, "expected 'days' after '%d', got %s", days, describe(unit))
+		}
+		period = "rolling"
+		atom.PeriodDays = &days
+
+	default:
+		return p.errorf(tok, "expected 'day', 'week', 'month' or a number of days after 'per', got %s", describe(tok))
+	}
+
+	atom.Period = &period
+	return nil
+}
+
+func isPeriodName(name string) bool {
+	switch strings.ToLower(name) {
+	case "day", "week", "month":
+		return true
+	}
+	return false
+}
+
+// parseAggregation parses "agg(name) op value", the aggregation name has already been consumed.
+func (p *parser) parseAggregation(aggToken token) (*dto.Requirement, error) {
+	aggregation := strings.ToLower(aggToken.text)
+	p.next()
+
+	nameToken := p.next()
+	if nameToken.kind != tokenIdent && nameToken.kind != tokenString {
+		return nil, p.errorf(nameToken, "expected a name inside '%s(', got %s", aggregation, describe(nameToken))
+	}
+	if strings.TrimSpace(nameToken.text) == "" {
+		return nil, p.errorf(nameToken, "name cannot be empty")
+	}
+
+	if closing := p.next(); closing.kind != tokenRParen {
+		return nil, p.errorf(closing, "expected ')' after '%s(%s', got %s", aggregation, nameToken.text, describe(closing))
+	}
+
+	if p.peek().kind != tokenOperator {
+		return nil, p.errorf(p.peek(), "expected an operator after '%s(...)', got %s", aggregation, describe(p.peek()))
+	}
+
+	atom, err := p.parseAtom(nameToken.text)
+	if err != nil {
+		return nil, err
+	}
+
+	switch aggregation {
+	case "count":
+		if *atom.DataType != "int" || atom.Unit != nil {
+			return nil, p.errorf(aggToken, "count must be compared with an integer")
+		}
+		// The counted entries carry no value of their own
+		dataType := "none"
+		atom.DataType = &dataType
+	case "sum", "max", "min":
+		if *atom.DataType == "bool" {
+			return nil, p.errorf(aggToken, "%s cannot be used with a boolean", aggregation)
+		}
+		if *atom.DataType == "time" && aggregation == "sum" {
+			return nil, p.errorf(aggToken, "sum cannot be used with a time of day")
+		}
+	}
+
+	atom.Aggregation = &aggregation
+
+	return atom, nil
+}
+
+func newCondition(operator string, operands []*dto.Requirement) *dto.Requirement {
+	condition := &dto.Requirement{
+		Type:     "condition",
+		Operator: &operator,
+	}
+
+	for i, operand := range operands {
+		operand.SortOrder = i
+		condition.Operands = append(condition.Operands, *operand)
+	}
+
+	// Conditions have no names in the language, so the title is the expression itself
+	condition.Title = Format(condition)
+
+	return condition
+}
+
+func describe(tok token) string {
+	switch tok.kind {
+	case tokenEOF, tokenLParen, tokenRParen, tokenAnd, tokenOr, tokenNot:
+		return tok.kind.String()
+	}
+	return fmt.Sprintf("%s '%s'", tok.kind, tok.text)
+}
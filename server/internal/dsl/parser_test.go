@@ -0,0 +1,231 @@
+package dsl
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/boreymarf/task-fuss/server/internal/dto"
+)
+
+func TestParseAtoms(t *testing.T) {
+	tests := []struct {
+		src         string
+		dataType    string
+		operator    string
+		target      string
+		unit        string
+		aggregation string
+		period      string
+		periodDays  int
+	}{
+		{src: "meditated", dataType: "bool"},
+		{src: `"read a book"`, dataType: "bool"},
+		{src: "pushups >= 50", dataType: "int", operator: ">=", target: "50"},
+		{src: "weight <= 80.5", dataType: "float", operator: "<=", target: "80.5"},
+		{src: "temperature > -2", dataType: "int", operator: ">", target: "-2"},
+		{src: "run >= 30m", dataType: "duration", operator: ">=", target: "1800s"},
+		{src: "run >= 1h30m", dataType: "duration", operator: ">=", target: "5400s"},
+		{src: "woke_up <= 07:00", dataType: "time", operator: "<=", target: "07:00"},
+		{src: "woke_up <= 6:30:15", dataType: "time", operator: "<=", target: "06:30:15"},
+		{src: "smoked == false", dataType: "bool", operator: "==", target: "false"},
+		{src: "smoked = TRUE", dataType: "bool", operator: "==", target: "true"},
+		{src: "run >= 5 km", dataType: "int", operator: ">=", target: "5", unit: "km"},
+		{src: "sum(water) >= 2000", dataType: "int", operator: ">=", target: "2000", aggregation: "sum"},
+		{src: "count(coffee) <= 2", dataType: "none", operator: "<=", target: "2", aggregation: "count"},
+		{src: "count(gym) >= 3 per week", dataType: "none", operator: ">=", target: "3", aggregation: "count", period: "week"},
+		{src: "sum(pages) >= 300 per 7 days", dataType: "int", operator: ">=", target: "300", aggregation: "sum", period: "rolling", periodDays: 7},
+		{src: "sum(run) >= 20 km per month", dataType: "int", operator: ">=", target: "20", unit: "km", aggregation: "sum", period: "month"},
+		{src: "max(sleep) >= 8h per 1 day", dataType: "duration", operator: ">=", target: "28800s", aggregation: "max", period: "rolling", periodDays: 1},
+		{src: "sum > 3", dataType: "int", operator: ">", target: "3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			r, err := Parse(tt.src)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %v", tt.src, err)
+			}
+			if r.Type != "atom" {
+				t.Fatalf("type = %q, want atom", r.Type)
+			}
+
+			check := func(field string, got *string, want string) {
+				t.Helper()
+				value := ""
+				if got != nil {
+					value = *got
+				}
+				if value != want {
+					t.Errorf("%s = %q, want %q", field, value, want)
+				}
+			}
+			check("data_type", r.DataType, tt.dataType)
+			check("operator", r.Operator, tt.operator)
+			check("target_value", r.TargetValue, tt.target)
+			check("unit", r.Unit, tt.unit)
+			check("aggregation", r.Aggregation, tt.aggregation)
+			check("period", r.Period, tt.period)
+
+			periodDays := 0
+			if r.PeriodDays != nil {
+				periodDays = *r.PeriodDays
+			}
+			if periodDays != tt.periodDays {
+				t.Errorf("period_days = %d, want %d", periodDays, tt.periodDays)
+			}
+		})
+	}
+}
+
+// shape writes the tree with every condition in parentheses, so precedence is visible.
+func shape(r *dto.Requirement) string {
+	if r.Type != "condition" {
+		return r.Title
+	}
+
+	var parts []string
+	for i := range r.Operands {
+		parts = append(parts, shape(&r.Operands[i]))
+	}
+
+	if *r.Operator == "not" {
+		return "(not " + parts[0] + ")"
+	}
+	return "(" + strings.Join(parts, " "+*r.Operator+" ") + ")"
+}
+
+func TestParsePrecedence(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"a and b", "(a and b)"},
+		{"a and b and c", "(a and b and c)"},
+		{"a or b or c", "(a or b or c)"},
+		{"a or b and c", "(a or (b and c))"},
+		{"a and b or c", "((a and b) or c)"},
+		{"a and b or c and d", "((a and b) or (c and d))"},
+		{"not a and b", "((not a) and b)"},
+		{"not a or b", "((not a) or b)"},
+		{"not not a", "(not (not a))"},
+		{"not (a or b)", "(not (a or b))"},
+		{"(a or b) and c", "((a or b) and c)"},
+		{"a and (b or c)", "(a and (b or c))"},
+		{"((a))", "a"},
+		{"a AND b Or NOT c", "((a and b) or (not c))"},
+		{"x >= 1 or y < 2 and z", "(x or (y and z))"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			r, err := Parse(tt.src)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %v", tt.src, err)
+			}
+			if got := shape(r); got != tt.want {
+				t.Errorf("Parse(%q) = %s, want %s", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatRoundTrip(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"meditated", "meditated"},
+		{"meditated == true", "meditated"},
+		{`"read a book" and pushups>=50`, `"read a book" and pushups >= 50`},
+		{"run >= 90m", "run >= 1h30m"},
+		{"weight <= 80", "weight <= 80"},
+		{"woke_up <= 7:00", "woke_up <= 07:00"},
+		{"a or (b or c)", "a or (b or c)"},
+		{"(a and b) or c", "a and b or c"},
+		{"not (a and b)", "not (a and b)"},
+		{"count(gym) >= 3 per week", "count(gym) >= 3 per week"},
+		{"sum(run) >= 20 km per 7 days", "sum(run) >= 20 km per 7 days"},
+		{`"and" or "say \"hi\""`, `"and" or "say \"hi\""`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			r, err := Parse(tt.src)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %v", tt.src, err)
+			}
+
+			formatted := Format(r)
+			if formatted != tt.want {
+				t.Errorf("Format(Parse(%q)) = %q, want %q", tt.src, formatted, tt.want)
+			}
+
+			again, err := Parse(formatted)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %v", formatted, err)
+			}
+			if Format(again) != formatted {
+				t.Errorf("Format is not stable: %q became %q", formatted, Format(again))
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		src     string
+		line    int
+		column  int
+		message string
+	}{
+		{"", 1, 1, "expression is empty"},
+		{"   ", 1, 4, "expression is empty"},
+		{"a and", 1, 6, "expected a name, 'not' or '(', got end of input"},
+		{"a b", 1, 3, "unexpected name 'b'"},
+		{"(a or b", 1, 8, "expected ')' to close '(' at line 1, column 1, got end of input"},
+		{"a)", 1, 2, "unexpected ')'"},
+		{"pushups >=", 1, 11, "expected a value after '>=', got end of input"},
+		{"pushups >= and", 1, 12, "expected a value after '>=', got 'and'"},
+		{"a ! b", 1, 3, "unexpected '!', use 'not' or '!='"},
+		{"a & b", 1, 3, "unexpected character '&'"},
+		{`"unterminated`, 1, 1, "unterminated quoted name"},
+		{`"bad \n escape"`, 1, 6, `unknown escape sequence '\n'`},
+		{`""`, 1, 1, "name cannot be empty"},
+		{"run >= 5km", 1, 8, "invalid duration '5km', put a space between a number and its unit"},
+		{"run >= 5x", 1, 8, "invalid duration '5x'"},
+		{"woke_up <= 25:00", 1, 12, "invalid time of day '25:00', use HH:MM or HH:MM:SS"},
+		{"weight <= 1.2.3", 1, 11, "invalid number '1.2.3'"},
+		{"run >= 5 parsecs", 1, 10, "unknown unit 'parsecs'"},
+		{"smoked > true", 1, 8, "operator '>' cannot be used with a boolean, use '==' or '!='"},
+		{`mood == "good"`, 1, 9, "choices are not supported in expressions, create the requirement as a tree"},
+		{"count(gym) >= 3 per fortnight", 1, 21, "expected 'day', 'week', 'month' or a number of days after 'per', got name 'fortnight'"},
+		{"sum(pages) >= 300 per 0 days", 1, 23, "number of days must be a positive integer, got '0'"},
+		{"sum(pages) >= 300 per 7 weeks", 1, 25, "expected 'days' after '7', got name 'weeks'"},
+		{"sum(1) >= 3", 1, 5, "expected a name inside 'sum(', got number '1'"},
+		{"sum(water >= 3", 1, 11, "expected ')' after 'sum(water', got comparison operator '>='"},
+		{"sum(water)", 1, 11, "expected an operator after 'sum(...)', got end of input"},
+		{"count(coffee) <= 2.5", 1, 1, "count must be compared with an integer"},
+		{"max(smoked) == true", 1, 1, "max cannot be used with a boolean"},
+		{"sum(woke_up) <= 07:00", 1, 1, "sum cannot be used with a time of day"},
+		{"a and\n  (b or\n   c", 3, 5, "expected ')' to close '(' at line 2, column 3, got end of input"},
+		{"a and\n\tb >=\n\t\t@", 3, 3, "unexpected character '@'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			_, err := Parse(tt.src)
+
+			var syntaxErr *Error
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Parse(%q) error = %v, want *Error", tt.src, err)
+			}
+			if syntaxErr.Line != tt.line || syntaxErr.Column != tt.column {
+				t.Errorf("position = %d:%d, want %d:%d (%s)", syntaxErr.Line, syntaxErr.Column, tt.line, tt.column, syntaxErr.Message)
+			}
+			if syntaxErr.Message != tt.message {
+				t.Errorf("message = %q, want %q", syntaxErr.Message, tt.message)
+			}
+		})
+	}
+}
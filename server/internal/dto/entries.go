@@ -0,0 +1,48 @@
+package dto
+
+import "time"
+
+type RequirementEntry struct {
+	ID            int64     `json:"id"`
+	RequirementID int64     `json:"requirement_id"`
+	EntryDate     time.Time `json:"entry_date"`
+	Value         string    `json:"value" example:"50"`
+	Unit          *string   `json:"unit,omitempty" example:"mi"` // Unit the value was entered in
+	Note          *string   `json:"note,omitempty" example:"Felt easy, try a longer route"`
+}
+
+type CreateEntryRequest struct {
+	Value     string     `json:"value" example:"3.1 mi"` // Values of requirements with a unit can be followed by any compatible unit, durations are written like target values
+	EntryDate *time.Time `json:"entry_date,omitempty"`   // Default: now
+	Note      *string    `json:"note,omitempty" example:"Felt easy, try a longer route"`
+}
+
+type UpdateEntryRequest struct {
+	Value     *string    `json:"value,omitempty" example:"3.1 mi"`
+	EntryDate *time.Time `json:"entry_date,omitempty"`
+	Note      *string    `json:"note,omitempty"` // An empty note removes it
+}
+
+type EntryResponse struct {
+	Entry RequirementEntry `json:"entry"`
+}
+
+// EntryChangeResponse carries the status of the task re-evaluated after the change
+type EntryChangeResponse struct {
+	Entry      *RequirementEntry `json:"entry,omitempty"`
+	TaskStatus TaskStatus        `json:"task_status"`
+}
+
+type GetEntriesResponse struct {
+	Entries []RequirementEntry `json:"entries"`
+}
+
+type CheckInRequest struct {
+	Date   string            `json:"date,omitempty" example:"2025-01-31"`                   // Default: today
+	Values map[string]string `json:"values" binding:"required" swaggertype:"object,string"` // Requirement ID to value, only these requirements are changed
+}
+
+type CheckInResponse struct {
+	Date  string       `json:"date" example:"2025-01-31"`
+	Tasks []TaskStatus `json:"tasks"`
+}
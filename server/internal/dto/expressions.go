@@ -0,0 +1,18 @@
+package dto
+
+type ParseExpressionRequest struct {
+	Expression string `json:"expression" binding:"required" example:"pushups >= 50 and (run >= 30m or not skipped)"`
+}
+
+type ParseExpressionResponse struct {
+	Requirement Requirement `json:"requirement"`
+	Expression  string      `json:"expression"` // Canonical form of the expression
+}
+
+type FormatExpressionRequest struct {
+	Requirement Requirement `json:"requirement"`
+}
+
+type FormatExpressionResponse struct {
+	Expression string `json:"expression"`
+}
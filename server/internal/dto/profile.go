@@ -1,5 +1,22 @@
 package dto
 
 type ProfileResponse struct {
-	User User `json:"user"`
+	User     User         `json:"user"`
+	Settings UserSettings `json:"settings"`
+}
+
+type UserSettings struct {
+	WeekStart string `json:"week_start" enums:"monday,tuesday,wednesday,thursday,friday,saturday,sunday" example:"monday"` // First day of weekly periods and of weeks in recurrence rules
+	Timezone  string `json:"timezone" example:"Europe/Berlin"`                                                             // Days, weeks and months are split in this time zone
+	DayEndsAt int    `json:"day_ends_at" example:"4"`                                                                      // Hour of the night the day ends at, entries logged before it count for the day before
+}
+
+type UpdateSettingsRequest struct {
+	WeekStart *string `json:"week_start" binding:"omitempty,oneof=monday tuesday wednesday thursday friday saturday sunday"`
+	Timezone  *string `json:"timezone" example:"Europe/Berlin"` // IANA time zone name
+	DayEndsAt *int    `json:"day_ends_at" binding:"omitempty,min=0,max=23" example:"4"`
+}
+
+type UpdateSettingsResponse struct {
+	Settings UserSettings `json:"settings"`
 }
@@ -0,0 +1,19 @@
+package dto
+
+import "time"
+
+// SearchResult is a task, a requirement or an entry note that matched the search
+type SearchResult struct {
+	Kind          string     `json:"kind" enums:"task,requirement,entry"`
+	TaskID        int64      `json:"task_id"`
+	TaskTitle     string     `json:"task_title"`
+	RequirementID *int64     `json:"requirement_id,omitempty"` // Requirements and entries only
+	EntryID       *int64     `json:"entry_id,omitempty"`       // Entries only
+	EntryDate     *time.Time `json:"entry_date,omitempty"`     // Entries only
+	// Matched text with the matches wrapped in <mark></mark>, the rest of the text is not HTML escaped
+	Snippet string `json:"snippet" example:"Run <mark>5 km</mark> before work"`
+}
+
+type SearchResponse struct {
+	Results []SearchResult `json:"results"`
+}
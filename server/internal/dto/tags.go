@@ -0,0 +1,26 @@
+package dto
+
+type Tag struct {
+	ID    int64   `json:"id"`
+	Name  string  `json:"name" example:"health"`
+	Color *string `json:"color,omitempty" example:"#4caf50"` // Nullable, #rrggbb
+}
+
+type CreateTagRequest struct {
+	Name  string  `json:"name" binding:"required" example:"health"`
+	Color *string `json:"color,omitempty" example:"#4caf50"` // #rrggbb
+}
+
+// UpdateTagRequest changes only the fields that are set, an empty color clears it.
+type UpdateTagRequest struct {
+	Name  *string `json:"name,omitempty" example:"health"`
+	Color *string `json:"color,omitempty" example:"#4caf50"`
+}
+
+type TagResponse struct {
+	Tag Tag `json:"tag"`
+}
+
+type GetTagsResponse struct {
+	Tags []Tag `json:"tags"`
+}
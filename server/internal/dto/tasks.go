@@ -6,20 +6,41 @@ type Task struct {
 	ID          int64        `json:"id"`
 	Title       string       `json:"title"`
 	Requirement *Requirement `json:"requirement,omitempty"`
+	Expression  *string      `json:"expression,omitempty"`  // Text form of the requirement
 	Description *string      `json:"description,omitempty"` // Nullable
+	Status      string       `json:"status,omitempty" enums:"active,archived,completed"`
 	CreatedAt   *time.Time   `json:"created_at,omitempty"`
 	UpdatedAt   *time.Time   `json:"updated_at,omitempty"`
 	StartDate   *time.Time   `json:"start_date,omitempty"`
-	EndDate     *time.Time   `json:"end_date,omitempty"` // Nullable
+	EndDate     *time.Time   `json:"end_date,omitempty"`                                        // Nullable
+	Recurrence  *string      `json:"recurrence,omitempty" example:"FREQ=WEEKLY;BYDAY=MO,WE,FR"` // Nullable, RRULE subset, daily if empty
+	Stats       *TaskStats   `json:"stats,omitempty"`                                           // Only with stats=true
+	Tags        []Tag        `json:"tags,omitempty"`
+	TagIDs      []int64      `json:"tag_ids,omitempty"` // Tags to create the task with, ignored in responses
+}
+
+type TaskStats struct {
+	CurrentStreak  int     `json:"current_streak"`
+	LongestStreak  int     `json:"longest_streak"`
+	StreakUnit     string  `json:"streak_unit" enums:"day,period"` // Streaks of tasks with X-TIMES are counted in met periods
+	CompletedDays  int     `json:"completed_days"`                 // Completed days the task was due on, since it started
+	CompletionRate float64 `json:"completion_rate" example:"0.75"` // Share of completed due days (or periods) in the window
+	WindowDays     int     `json:"window_days" example:"30"`
 }
 
 type Requirement struct {
 	ID          int64         `json:"id"`
 	Title       string        `json:"title"`
 	Type        string        `json:"type"`
-	DataType    *string       `json:"data_type,omitempty"`
+	DataType    *string       `json:"data_type,omitempty" enums:"bool,int,float,duration,none,choice,text,rating,time"`
 	Operator    *string       `json:"operator,omitempty"`
-	TargetValue *string       `json:"target_value,omitempty"`
+	TargetValue *string       `json:"target_value,omitempty" example:"30m"`                 // Durations are written like 1h30m, PT1H30M, 01:30:00 (H:MM:SS), 25:30 (MM:SS) or 90 (minutes) and returned in seconds like 5400s
+	Options     []string      `json:"options,omitempty" example:"bad,okay,good"`            // Allowed values of a choice
+	Unit        *string       `json:"unit,omitempty" example:"km"`                          // Unit of the target value, entries in other units of the same dimension are converted
+	Aggregation *string       `json:"aggregation,omitempty" enums:"last,sum,count,max,min"` // How entries of a day are combined, default: last
+	Period      *string       `json:"period,omitempty" enums:"day,week,month,rolling"`      // Which entries are aggregated, default: day
+	PeriodDays  *int          `json:"period_days,omitempty" example:"7"`                    // Length of the rolling period
+	ScoreMode   *string       `json:"score_mode,omitempty" enums:"average,min"`             // How 'and' combines scores of operands, default: average
 	Value       *string       `json:"value,omitempty"`
 	Operands    []Requirement `json:"operands,omitempty"`
 	SortOrder   int           `json:"sort_order"`
@@ -29,6 +50,54 @@ type CreateTaskRequest struct {
 	Task Task `json:"task"`
 }
 
+// UpdateTaskRequest changes only the fields that are set, an empty description, end_date or recurrence clears it.
+// A new requirement tree becomes a revision effective from effective_from, today by default, days before it
+// keep being evaluated with the older revisions. Requirement nodes that keep their ID are updated in place
+// together with their logged values, nodes without an ID are created and nodes left out are removed.
+type UpdateTaskRequest struct {
+	Title         *string      `json:"title,omitempty"`
+	Description   *string      `json:"description,omitempty"`
+	StartDate     *string      `json:"start_date,omitempty" example:"2025-01-31"`
+	EndDate       *string      `json:"end_date,omitempty" example:"2025-12-31"`
+	Status        *string      `json:"status,omitempty" binding:"omitempty,oneof=active archived completed"`
+	Recurrence    *string      `json:"recurrence,omitempty" example:"FREQ=WEEKLY;BYDAY=MO,WE,FR"`
+	Requirement   *Requirement `json:"requirement,omitempty"`
+	Expression    *string      `json:"expression,omitempty"`                          // Atoms are matched with the existing ones by name
+	EffectiveFrom *string      `json:"effective_from,omitempty" example:"2025-02-01"` // First day the new requirement tree is used for, default: today
+	TagIDs        *[]int64     `json:"tag_ids,omitempty"`                             // Replaces the tags of the task, an empty list removes them all
+}
+
+type UpdateTaskResponse struct {
+	Task Task `json:"task"`
+}
+
+// RequirementRevision is the requirement tree of a task as it was defined for a range of days.
+type RequirementRevision struct {
+	ID             int64       `json:"id,omitempty"` // Tasks whose requirements never changed have a single revision without an ID
+	EffectiveFrom  string      `json:"effective_from" example:"2025-01-31"`
+	EffectiveUntil *string     `json:"effective_until,omitempty" example:"2025-02-28"` // Last day the revision is used for, the latest revision has none
+	CreatedAt      *time.Time  `json:"created_at,omitempty"`
+	Requirement    Requirement `json:"requirement"`
+	Expression     string      `json:"expression"`
+}
+
+type GetTaskRevisionsResponse struct {
+	Revisions []RequirementRevision `json:"revisions"`
+}
+
+// TrashedTask is a deleted task that can still be restored until PurgeAt
+type TrashedTask struct {
+	ID        int64     `json:"id"`
+	Title     string    `json:"title"`
+	Status    string    `json:"status" enums:"active,archived,completed"` // Status the task is restored with
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"` // When the task is deleted for good
+}
+
+type GetTrashResponse struct {
+	Tasks []TrashedTask `json:"tasks"`
+}
+
 type CreateTaskResponse struct {
 	Task Task `json:"task"`
 }
@@ -38,5 +107,106 @@ type GetTaskByIDResponse struct {
 }
 
 type GetAllTasksResponse struct {
-	Tasks []Task `json:"tasks"`
+	Tasks      []Task  `json:"tasks"`
+	Total      int     `json:"total"`       // Tasks matching the filters on all pages
+	NextCursor *string `json:"next_cursor"` // Null on the last page
+	PrevCursor *string `json:"prev_cursor"` // Null on the first page
+}
+
+type RequirementStatus struct {
+	RequirementID int64               `json:"requirement_id"`
+	Title         string              `json:"title"`
+	Passed        bool                `json:"passed"`
+	State         string              `json:"state" enums:"met,pending,failed"` // Pending while the period is not over and it can still be met
+	Score         float64             `json:"score" example:"0.9"`              // Partial credit from 0 to 1
+	Value         *string             `json:"value,omitempty"`                  // Nullable, nothing was logged
+	Operands      []RequirementStatus `json:"operands,omitempty"`
+}
+
+type TaskStatus struct {
+	TaskID      int64             `json:"task_id"`
+	Date        string            `json:"date" example:"2025-01-31"`
+	Due         bool              `json:"due"` // The task is scheduled for this day
+	Completed   bool              `json:"completed"`
+	State       string            `json:"state" enums:"met,pending,failed"`
+	Score       float64           `json:"score" example:"0.9"`
+	Requirement RequirementStatus `json:"requirement"`
+}
+
+type GetTaskStatusResponse struct {
+	Status TaskStatus `json:"status"`
+}
+
+// TaskDay is the stored outcome of a task for one day
+type TaskDay struct {
+	Date      string  `json:"date" example:"2025-01-31"`
+	Completed bool    `json:"completed"`
+	Score     float64 `json:"score" example:"0.9"` // Partial credit from 0 to 1
+}
+
+type GetTaskDaysResponse struct {
+	Days []TaskDay `json:"days"`
+}
+
+// AgendaRequirement is a requirement node together with its outcome for the day of the agenda
+type AgendaRequirement struct {
+	ID          int64               `json:"id"`
+	Title       string              `json:"title"`
+	Type        string              `json:"type"`
+	DataType    *string             `json:"data_type,omitempty"`
+	Operator    *string             `json:"operator,omitempty"`
+	TargetValue *string             `json:"target_value,omitempty"`
+	Options     []string            `json:"options,omitempty"`
+	Unit        *string             `json:"unit,omitempty"`
+	Aggregation *string             `json:"aggregation,omitempty"`
+	Period      *string             `json:"period,omitempty"`
+	PeriodDays  *int                `json:"period_days,omitempty"`
+	ScoreMode   *string             `json:"score_mode,omitempty"`
+	Value       *string             `json:"value,omitempty"` // Logged so far in the period of the atom, null if nothing was logged
+	Passed      bool                `json:"passed"`
+	State       string              `json:"state" enums:"met,pending,failed"`
+	Score       float64             `json:"score" example:"0.9"`
+	Operands    []AgendaRequirement `json:"operands,omitempty"`
+}
+
+type AgendaTask struct {
+	ID          int64             `json:"id"`
+	Title       string            `json:"title"`
+	Description *string           `json:"description,omitempty"`
+	Recurrence  *string           `json:"recurrence,omitempty"`
+	Tags        []Tag             `json:"tags,omitempty"`
+	Completed   bool              `json:"completed"`
+	State       string            `json:"state" enums:"met,pending,failed"`
+	Score       float64           `json:"score" example:"0.9"`
+	Requirement AgendaRequirement `json:"requirement"`
+}
+
+type GetAgendaResponse struct {
+	Date  string       `json:"date" example:"2025-01-31"`
+	Tasks []AgendaTask `json:"tasks"` // Tasks due on the date
+}
+
+// HistoryDay is the outcome of one day, for a single task the counts are 0 or 1
+type HistoryDay struct {
+	Date      string  `json:"date" example:"2025-01-31"`
+	State     string  `json:"state,omitempty" enums:"completed,failed,skipped,not_due,pending"` // Only in the history of a single task
+	Score     float64 `json:"score" example:"0.75"`                                             // Average score of the due tasks, skipped days score 0
+	Due       int     `json:"due"`
+	Completed int     `json:"completed"`
+	Failed    int     `json:"failed"`  // Something was logged but not enough
+	Skipped   int     `json:"skipped"` // Nothing was logged
+	Pending   int     `json:"pending"` // Today, not completed yet
+}
+
+type HistorySummary struct {
+	Due            int     `json:"due"`
+	Completed      int     `json:"completed"`
+	Failed         int     `json:"failed"`
+	Skipped        int     `json:"skipped"`
+	CompletionRate float64 `json:"completion_rate" example:"0.8"` // Share of the due days that are over and were completed
+}
+
+type GetHistoryResponse struct {
+	Days    []HistoryDay   `json:"days"` // Every day of the range up to today, oldest first
+	Summary HistorySummary `json:"summary"`
 }
@@ -0,0 +1,40 @@
+package dto
+
+// TrendDay is the value of a day in the unit of the requirement, durations are in seconds
+type TrendDay struct {
+	Date          string   `json:"date" example:"2025-01-31"`
+	Value         *float64 `json:"value,omitempty" example:"1520"`          // Nothing was logged when missing
+	MovingAverage *float64 `json:"moving_average,omitempty" example:"1544"` // Average of the values in the window ending with the day
+	PersonalBest  bool     `json:"personal_best,omitempty"`                 // Better than every value logged before
+}
+
+type TrendWeek struct {
+	Start   string   `json:"start" example:"2025-01-27"` // First day of the week, the first and the last week of the range may be partial
+	Count   int      `json:"count"`
+	Average *float64 `json:"average,omitempty" example:"1544"`
+	Change  *float64 `json:"change,omitempty" example:"-0.05"` // Relative to the average of the previous week
+}
+
+type TrendRecord struct {
+	Date  string  `json:"date" example:"2025-01-31"`
+	Value float64 `json:"value" example:"1480"`
+}
+
+type TrendSummary struct {
+	Count   int          `json:"count"` // Days with a value
+	Average *float64     `json:"average,omitempty"`
+	Min     *TrendRecord `json:"min,omitempty"`
+	Max     *TrendRecord `json:"max,omitempty"`
+	Best    *TrendRecord `json:"best,omitempty"` // Best value ever, also before the range
+}
+
+type GetTrendResponse struct {
+	RequirementID int64        `json:"requirement_id"`
+	DataType      string       `json:"data_type" enums:"int,float,duration,rating"`
+	Unit          *string      `json:"unit,omitempty" example:"km"`
+	Direction     string       `json:"direction,omitempty" enums:"higher,lower"` // Which values are better, from the target of the requirement
+	Window        int          `json:"window" example:"7"`                       // Days of the moving average
+	Days          []TrendDay   `json:"days"`
+	Weeks         []TrendWeek  `json:"weeks"`
+	Summary       TrendSummary `json:"summary"`
+}
@@ -0,0 +1,161 @@
+// Package duration reads durations written in any of the formats people use:
+//
+//	1h30m      Go style
+//	PT1H30M    ISO 8601, days and weeks are 24 hours and 7 days long
+//	01:30:00   clock, H:MM:SS
+//	90:00      clock, MM:SS, the way run and lap times are written
+//	90         plain minutes
+//
+// Durations are stored as whole seconds in Go style, "5400s", see Canonical.
+// The unit keeps stored values from being read as minutes when they are parsed again.
+package duration
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	isoPattern   = regexp.MustCompile(`^P(?:([\d.]+)W)?(?:([\d.]+)D)?(?:T(?:([\d.]+)H)?(?:([\d.]+)M)?(?:([\d.]+)S)?)?$`)
+	clockPattern = regexp.MustCompile(`^(\d+):(\d{2})(?::(\d{2}))?$`)
+	// canonicalPattern matches the form durations are stored in
+	canonicalPattern = regexp.MustCompile(`^\d+s$`)
+)
+
+// isoUnits are the sizes of the ISO 8601 designators in the order of isoPattern groups
+var isoUnits = []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+
+// Parse reads a duration in any of the supported formats.
+// Negative durations and fractions of a second are rejected.
+func Parse(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("'' is not a duration")
+	}
+
+	var d time.Duration
+	var err error
+
+	switch {
+	case strings.HasPrefix(strings.ToUpper(s), "P"):
+		d, err = parseISO(strings.ToUpper(s))
+	case strings.Contains(s, ":"):
+		d, err = parseClock(s)
+	case isDigits(s):
+		var minutes int64
+		minutes, err = strconv.ParseInt(s, 10, 64)
+		if err != nil || minutes > math.MaxInt64/int64(time.Minute) {
+			return 0, fmt.Errorf("'%s' is too long", s)
+		}
+		d = time.Duration(minutes) * time.Minute
+	default:
+		d, err = time.ParseDuration(s)
+		if err != nil {
+			err = fmt.Errorf("'%s' is not a duration", s)
+		}
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if d < 0 {
+		return 0, fmt.Errorf("'%s' is negative", s)
+	}
+	if d%time.Second != 0 {
+		return 0, fmt.Errorf("'%s' is not a whole number of seconds", s)
+	}
+
+	return d, nil
+}
+
+func parseISO(s string) (time.Duration, error) {
+	match := isoPattern.FindStringSubmatch(s)
+	if match == nil || s == "P" || strings.HasSuffix(s, "T") {
+		if strings.ContainsAny(strings.SplitN(s, "T", 2)[0], "YM") {
+			return 0, fmt.Errorf("'%s' has years or months, which have no fixed length, use weeks or days", s)
+		}
+		return 0, fmt.Errorf("'%s' is not an ISO 8601 duration", s)
+	}
+
+	var total float64
+	for i, group := range match[1:] {
+		if group == "" {
+			continue
+		}
+		n, err := strconv.ParseFloat(group, 64)
+		if err != nil {
+			return 0, fmt.Errorf("'%s' is not an ISO 8601 duration", s)
+		}
+		total += n * float64(isoUnits[i])
+	}
+
+	return time.Duration(math.Round(total)), nil
+}
+
+// parseClock reads H:MM:SS, or MM:SS when there are only two parts. The first part may be above 59,
+// "90:00" is an hour and a half.
+func parseClock(s string) (time.Duration, error) {
+	match := clockPattern.FindStringSubmatch(s)
+	if match == nil {
+		return 0, fmt.Errorf("'%s' is not a clock duration, use H:MM:SS or MM:SS", s)
+	}
+
+	first, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil || first > math.MaxInt64/int64(time.Hour) {
+		return 0, fmt.Errorf("'%s' is too long", s)
+	}
+	second, _ := strconv.Atoi(match[2])
+
+	if match[3] == "" {
+		if second > 59 {
+			return 0, fmt.Errorf("'%s' has seconds above 59", s)
+		}
+		return time.Duration(first)*time.Minute + time.Duration(second)*time.Second, nil
+	}
+
+	third, _ := strconv.Atoi(match[3])
+	if second > 59 || third > 59 {
+		return 0, fmt.Errorf("'%s' has minutes or seconds above 59", s)
+	}
+
+	return time.Duration(first)*time.Hour + time.Duration(second)*time.Minute + time.Duration(third)*time.Second, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Canonical returns the duration as whole seconds in Go style, which is how durations are stored.
+func Canonical(d time.Duration) string {
+	return strconv.FormatInt(int64(d/time.Second), 10) + "s"
+}
+
+// IsCanonical reports whether s is in the form Canonical returns.
+func IsCanonical(s string) bool {
+	return canonicalPattern.MatchString(s)
+}
+
+// Format writes the duration Go style without zero units, "1h30m" instead of "1h30m0s".
+func Format(d time.Duration) string {
+	if d == 0 {
+		return "0s"
+	}
+
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
@@ -0,0 +1,167 @@
+package duration
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		// Go style
+		{"1h30m", 90 * time.Minute},
+		{"90s", 90 * time.Second},
+		{"1.5h", 90 * time.Minute},
+		{"5400s", 90 * time.Minute},
+		{"0s", 0},
+		{" 45m ", 45 * time.Minute},
+
+		// ISO 8601
+		{"PT1H30M", 90 * time.Minute},
+		{"PT90S", 90 * time.Second},
+		{"PT1M", time.Minute},
+		{"P1D", 24 * time.Hour},
+		{"P1W", 7 * 24 * time.Hour},
+		{"P1DT2H", 26 * time.Hour},
+		{"pt0.5h", 30 * time.Minute},
+
+		// Plain numbers are minutes
+		{"90", 90 * time.Minute},
+		{"0", 0},
+		{"5", 5 * time.Minute},
+
+		// Two parts are minutes and seconds, three parts hours, minutes and seconds
+		{"25:30", 25*time.Minute + 30*time.Second},
+		{"90:00", 90 * time.Minute},
+		{"0:45", 45 * time.Second},
+		{"01:30:00", 90 * time.Minute},
+		{"1:30:00", 90 * time.Minute},
+		{"100:00:01", 100*time.Hour + time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Parse(tt.in)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"   ",
+		"1.5",
+		"-5m",
+		"-5",
+		"500ms",
+		"1h30",
+		"abc",
+		"P",
+		"PT",
+		"P1M",
+		"P1Y",
+		"25:60",
+		"1:60:00",
+		"1:30:60",
+		"1:5",
+		":30",
+		"1:30:00:00",
+		"99999999999999999999",
+		"9999999999999:00:00",
+	}
+
+	for _, in := range tests {
+		t.Run(in, func(t *testing.T) {
+			if got, err := Parse(in); err == nil {
+				t.Errorf("Parse(%q) = %v, want an error", in, got)
+			}
+		})
+	}
+}
+
+// Stored values have to come back as the same duration, a plain number would be read as minutes.
+func TestCanonicalRoundTrip(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"90", "5400s"},
+		{"25:30", "1530s"},
+		{"1:30:00", "5400s"},
+		{"PT1H30M", "5400s"},
+		{"0s", "0s"},
+		{"5400s", "5400s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			d, err := Parse(tt.in)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %v", tt.in, err)
+			}
+
+			canonical := Canonical(d)
+			if canonical != tt.want {
+				t.Errorf("Canonical(%v) = %q, want %q", d, canonical, tt.want)
+			}
+			if !IsCanonical(canonical) {
+				t.Errorf("IsCanonical(%q) = false", canonical)
+			}
+
+			again, err := Parse(canonical)
+			if err != nil || again != d {
+				t.Errorf("Parse(%q) = %v, %v, want %v", canonical, again, err, d)
+			}
+		})
+	}
+}
+
+func TestIsCanonical(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"5400s", true},
+		{"0s", true},
+		{"5400", false},
+		{"1h30s", false},
+		{"90m", false},
+		{"s", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsCanonical(tt.in); got != tt.want {
+			t.Errorf("IsCanonical(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want string
+	}{
+		{0, "0s"},
+		{45 * time.Second, "45s"},
+		{30 * time.Minute, "30m"},
+		{90 * time.Minute, "1h30m"},
+		{2 * time.Hour, "2h"},
+		{time.Hour + 5*time.Second, "1h0m5s"},
+		{25*time.Minute + 30*time.Second, "25m30s"},
+	}
+
+	for _, tt := range tests {
+		if got := Format(tt.in); got != tt.want {
+			t.Errorf("Format(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
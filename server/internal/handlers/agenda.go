@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"github.com/boreymarf/task-fuss/server/internal/api"
+	"github.com/boreymarf/task-fuss/server/internal/config"
+	"github.com/boreymarf/task-fuss/server/internal/dto"
+	"github.com/boreymarf/task-fuss/server/internal/security"
+	"github.com/boreymarf/task-fuss/server/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// GetAgenda godoc
+// @Summary Get the tasks due on a day
+// @Description Returns the active tasks due on the date with their requirement tree annotated with the values
+// @Description logged so far and whether every node is met, pending or failed. GET /today is the agenda of today.
+// @Tags agenda
+// @Security ApiKeyAuth
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param date query string false "Date in YYYY-MM-DD format (default: today in the time zone of the user)"
+// @Success 200 {object} dto.GetAgendaResponse "Tasks due on the date ordered by ID"
+// @Failure 400 {object} api.Error "Invalid date"
+// @Failure 401 {object} api.Error "Unauthorized"
+// @Failure 500 {object} api.Error "Internal server error"
+// @Router /agenda [get]
+// @Router /today [get]
+func (h *TaskHandler) GetAgenda(c *gin.Context) {
+
+	date, ok := parseDateQuery(c, "date", c.Query("date"))
+	if !ok {
+		return
+	}
+
+	claims := security.GetClaimsFromContext(c)
+
+	day, items, err := h.taskService.GetAgenda(claims.UserID, date)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	response := dto.GetAgendaResponse{
+		Date:  day.Format(config.DateFormat),
+		Tasks: make([]dto.AgendaTask, 0, len(items)),
+	}
+	for _, item := range items {
+		response.Tasks = append(response.Tasks, toAgendaTask(&item))
+	}
+
+	api.Success(c, response)
+}
+
+func toAgendaTask(item *service.AgendaItem) dto.AgendaTask {
+
+	results := make(map[int64]*service.RequirementResult)
+	var collect func(r *service.RequirementResult)
+	collect = func(r *service.RequirementResult) {
+		results[r.RequirementID] = r
+		for i := range r.Operands {
+			collect(&r.Operands[i])
+		}
+	}
+	collect(&item.Evaluation.Requirement)
+
+	return dto.AgendaTask{
+		ID:          item.Task.ID,
+		Title:       item.Task.Title,
+		Description: item.Task.Description,
+		Recurrence:  item.Task.Recurrence,
+		Tags:        item.Task.Tags,
+		Completed:   item.Evaluation.Passed,
+		State:       item.Evaluation.State,
+		Score:       item.Evaluation.Score,
+		Requirement: toAgendaRequirement(item.Task.Requirement, results),
+	}
+}
+
+// toAgendaRequirement joins the requirement tree with the outcomes of its nodes.
+func toAgendaRequirement(requirement *dto.Requirement, results map[int64]*service.RequirementResult) dto.AgendaRequirement {
+
+	node := dto.AgendaRequirement{
+		ID:          requirement.ID,
+		Title:       requirement.Title,
+		Type:        requirement.Type,
+		DataType:    requirement.DataType,
+		Operator:    requirement.Operator,
+		TargetValue: requirement.TargetValue,
+		Options:     requirement.Options,
+		Unit:        requirement.Unit,
+		Aggregation: requirement.Aggregation,
+		Period:      requirement.Period,
+		PeriodDays:  requirement.PeriodDays,
+		ScoreMode:   requirement.ScoreMode,
+	}
+
+	if result, exists := results[requirement.ID]; exists {
+		node.Value = result.Value
+		node.Passed = result.Passed
+		node.State = result.State
+		node.Score = result.Score
+	}
+
+	for i := range requirement.Operands {
+		node.Operands = append(node.Operands, toAgendaRequirement(&requirement.Operands[i], results))
+	}
+
+	return node
+}
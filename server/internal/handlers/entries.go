@@ -1,28 +1,285 @@
 package handlers
 
-import "github.com/gin-gonic/gin"
+import (
+	"time"
+
+	"github.com/boreymarf/task-fuss/server/internal/api"
+	"github.com/boreymarf/task-fuss/server/internal/config"
+	"github.com/boreymarf/task-fuss/server/internal/dto"
+	"github.com/boreymarf/task-fuss/server/internal/logger"
+	"github.com/boreymarf/task-fuss/server/internal/models"
+	"github.com/boreymarf/task-fuss/server/internal/security"
+	"github.com/boreymarf/task-fuss/server/internal/service"
+	"github.com/boreymarf/task-fuss/server/internal/utils"
+	"github.com/gin-gonic/gin"
+)
 
 type EntriesHandler struct {
+	taskService *service.TaskService
 }
 
-func InitEntriesHandler() (*EntriesHandler, error) {
-	return &EntriesHandler{}, nil
+func InitEntriesHandler(taskService *service.TaskService) (*EntriesHandler, error) {
+	return &EntriesHandler{taskService: taskService}, nil
 }
 
-// CreateRequirementEntry godoc
-// @Summary Create a new entry
+// AddRequirementEntry godoc
+// @Summary Log a value for a requirement
+// @Description Creates a new entry for the requirement and re-evaluates the task for the day of the entry
+// @Tags entries
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param requirement_id path int true "Requirement ID"
+// @Param CreateEntryRequest body dto.CreateEntryRequest true "Entry data"
+// @Success 201 {object} dto.EntryChangeResponse "Entry created"
+// @Failure 400 {object} api.Error "Invalid request or value does not match the data type (code: VALIDATION_FAILED)"
+// @Failure 401 {object} api.Error "Unauthorized"
+// @Failure 403 {object} api.Error "Requirement belongs to another user"
+// @Failure 404 {object} api.Error "Requirement not found"
+// @Failure 500 {object} api.Error "Internal server error"
+// @Router /requirements/{requirement_id}/entries [post]
 func (h *EntriesHandler) AddRequirementEntry(c *gin.Context) {
+
+	requirementID, ok := parseIDParam(c, "requirement_id")
+	if !ok {
+		return
+	}
+
+	var req dto.CreateEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	claims := security.GetClaimsFromContext(c)
+
+	entry, evaluation, err := h.taskService.AddRequirementEntry(requirementID, claims.UserID, &req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	dtoEntry := toEntry(entry)
+	api.Created(c, dto.EntryChangeResponse{
+		Entry:      &dtoEntry,
+		TaskStatus: toTaskStatus(evaluation),
+	})
 }
 
+// GetRequirementEntries godoc
+// @Summary Get entries of a requirement
+// @Description Returns entries of the requirement logged between start and end, dates are days in the user's time zone that end at their day end hour
+// @Tags entries
+// @Security ApiKeyAuth
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param requirement_id path int true "Requirement ID"
+// @Param start query string false "Start of the range, YYYY-MM-DD or RFC 3339 (inclusive)"
+// @Param end query string false "End of the range, YYYY-MM-DD (inclusive) or RFC 3339 (exclusive)"
+// @Success 200 {object} dto.GetEntriesResponse "List of entries"
+// @Failure 400 {object} api.Error "Invalid query parameters"
+// @Failure 401 {object} api.Error "Unauthorized"
+// @Failure 403 {object} api.Error "Requirement belongs to another user"
+// @Failure 404 {object} api.Error "Requirement not found"
+// @Failure 500 {object} api.Error "Internal server error"
+// @Router /requirements/{requirement_id}/entries [get]
+func (h *EntriesHandler) GetRequirementEntries(c *gin.Context) {
+
+	requirementID, ok := parseIDParam(c, "requirement_id")
+	if !ok {
+		return
+	}
+
+	r, ok := parseRangeQuery(c)
+	if !ok {
+		return
+	}
+
+	claims := security.GetClaimsFromContext(c)
+
+	entries, err := h.taskService.GetRequirementEntries(requirementID, claims.UserID, r)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
 
-// This one takes IDs and two dates in query
+	response := dto.GetEntriesResponse{
+		Entries: make([]dto.RequirementEntry, 0, len(entries)),
+	}
+	for i := range entries {
+		response.Entries = append(response.Entries, toEntry(&entries[i]))
+	}
 
-// CreateTask godoc
-// @Summary Create a new entry
-func (h *EntriesHandler) GetEntries(c *gin.Context) {
+	api.Success(c, response)
 }
 
+// GetEntryByID godoc
+// @Summary Get an entry by ID
+// @Tags entries
+// @Security ApiKeyAuth
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param entry_id path int true "Entry ID"
+// @Success 200 {object} dto.EntryResponse "Entry"
+// @Failure 400 {object} api.Error "Invalid entry ID"
+// @Failure 401 {object} api.Error "Unauthorized"
+// @Failure 403 {object} api.Error "Entry belongs to another user"
+// @Failure 404 {object} api.Error "Entry not found"
+// @Failure 500 {object} api.Error "Internal server error"
+// @Router /entries/{entry_id} [get]
+func (h *EntriesHandler) GetEntryByID(c *gin.Context) {
+
+	entryID, ok := parseIDParam(c, "entry_id")
+	if !ok {
+		return
+	}
+
+	claims := security.GetClaimsFromContext(c)
+
+	entry, err := h.taskService.GetRequirementEntryByID(entryID, claims.UserID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	api.Success(c, dto.EntryResponse{
+		Entry: toEntry(entry),
+	})
+}
+
+// UpdateEntry godoc
+// @Summary Update an entry
+// @Description Changes the value or the date of the entry and re-evaluates the task for the affected days
+// @Tags entries
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param entry_id path int true "Entry ID"
+// @Param UpdateEntryRequest body dto.UpdateEntryRequest true "Fields to change"
+// @Success 200 {object} dto.EntryChangeResponse "Entry updated"
+// @Failure 400 {object} api.Error "Invalid request or value does not match the data type (code: VALIDATION_FAILED)"
+// @Failure 401 {object} api.Error "Unauthorized"
+// @Failure 403 {object} api.Error "Entry belongs to another user"
+// @Failure 404 {object} api.Error "Entry not found"
+// @Failure 500 {object} api.Error "Internal server error"
+// @Router /entries/{entry_id} [put]
+func (h *EntriesHandler) UpdateEntry(c *gin.Context) {
+
+	entryID, ok := parseIDParam(c, "entry_id")
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	claims := security.GetClaimsFromContext(c)
 
-//
-//
-func (h *EntriesHandler) 
+	entry, evaluation, err := h.taskService.UpdateRequirementEntry(entryID, claims.UserID, &req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	dtoEntry := toEntry(entry)
+	api.Success(c, dto.EntryChangeResponse{
+		Entry:      &dtoEntry,
+		TaskStatus: toTaskStatus(evaluation),
+	})
+}
+
+// DeleteEntry godoc
+// @Summary Delete an entry
+// @Description Deletes the entry and re-evaluates the task for its day
+// @Tags entries
+// @Security ApiKeyAuth
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param entry_id path int true "Entry ID"
+// @Success 200 {object} dto.EntryChangeResponse "Entry deleted"
+// @Failure 400 {object} api.Error "Invalid entry ID"
+// @Failure 401 {object} api.Error "Unauthorized"
+// @Failure 403 {object} api.Error "Entry belongs to another user"
+// @Failure 404 {object} api.Error "Entry not found"
+// @Failure 500 {object} api.Error "Internal server error"
+// @Router /entries/{entry_id} [delete]
+func (h *EntriesHandler) DeleteEntry(c *gin.Context) {
+
+	entryID, ok := parseIDParam(c, "entry_id")
+	if !ok {
+		return
+	}
+
+	claims := security.GetClaimsFromContext(c)
+
+	evaluation, err := h.taskService.DeleteRequirementEntry(entryID, claims.UserID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	logger.Log.Debug().Int64("entry_id", entryID).Msg("Requirement entry was deleted")
+
+	api.Success(c, dto.EntryChangeResponse{
+		TaskStatus: toTaskStatus(evaluation),
+	})
+}
+
+// CheckIn godoc
+// @Summary Log many values for a day at once
+// @Description Sets values of any number of requirements for one day in a single transaction. A value replaces the latest one logged that day for a requirement without aggregation and is added to the day for sums, counts and extremes, other requirements keep theirs. Returns the re-evaluated status of each affected task
+// @Tags entries
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param CheckInRequest body dto.CheckInRequest true "Date and values by requirement ID"
+// @Success 200 {object} dto.CheckInResponse "Status of the affected tasks"
+// @Failure 400 {object} api.Error "Invalid request or values (code: VALIDATION_FAILED)"
+// @Failure 401 {object} api.Error "Unauthorized"
+// @Failure 403 {object} api.Error "A requirement belongs to another user"
+// @Failure 404 {object} api.Error "A requirement was not found"
+// @Failure 500 {object} api.Error "Internal server error"
+// @Router /checkin [post]
+func (h *EntriesHandler) CheckIn(c *gin.Context) {
+
+	var req dto.CheckInRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	claims := security.GetClaimsFromContext(c)
+
+	date, evaluations, err := h.taskService.CheckIn(claims.UserID, &req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	response := dto.CheckInResponse{
+		Date:  date.Format(config.DateFormat),
+		Tasks: make([]dto.TaskStatus, 0, len(evaluations)),
+	}
+	for _, evaluation := range evaluations {
+		response.Tasks = append(response.Tasks, toTaskStatus(evaluation))
+	}
+
+	api.Success(c, response)
+}
+
+func toEntry(entry *models.RequirementEntry) dto.RequirementEntry {
+	return dto.RequirementEntry{
+		ID:            entry.ID,
+		RequirementID: entry.RequirementID,
+		EntryDate:     entry.EntryDate.In(time.UTC),
+		Value:         entry.Value,
+		Unit:          entry.Unit,
+		Note:          entry.Note,
+	}
+}
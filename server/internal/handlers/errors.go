@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/boreymarf/task-fuss/server/internal/api"
+	"github.com/boreymarf/task-fuss/server/internal/apperrors"
+	"github.com/boreymarf/task-fuss/server/internal/dsl"
+	"github.com/boreymarf/task-fuss/server/internal/dto"
+	"github.com/boreymarf/task-fuss/server/internal/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// handleServiceError sends the response matching an error returned by the service layer.
+func handleServiceError(c *gin.Context, err error) {
+
+	var validationErrs apperrors.ValidationErrors
+	var validationErr *apperrors.ValidationError
+	var exprErr *dsl.Error
+
+	switch {
+	case errors.As(err, &validationErrs):
+		api.ValidationFailed.SendWithDetailsAndAbort(c, toFieldErrors(validationErrs))
+	case errors.As(err, &validationErr):
+		api.ValidationFailed.SendWithDetailsAndAbort(c, toFieldErrors(apperrors.ValidationErrors{validationErr}))
+	case errors.As(err, &exprErr):
+		api.InvalidExpression.SendWithDetailsAndAbort(c, exprErr)
+	case errors.Is(err, apperrors.ErrForbidden):
+		api.Forbidden.SendAndAbort(c)
+	case errors.Is(err, apperrors.ErrNotFound), errors.Is(err, sql.ErrNoRows):
+		api.NotFound.SendAndAbort(c)
+	default:
+		logger.Log.Error().Err(err).Str("path", c.FullPath()).Msg("Unhandled service error")
+		api.InternalServerError.SendAndAbort(c)
+	}
+}
+
+func toFieldErrors(errs apperrors.ValidationErrors) []dto.FieldError {
+	details := make([]dto.FieldError, 0, len(errs))
+	for _, err := range errs {
+		details = append(details, dto.FieldError{
+			Field:   err.Field,
+			Code:    err.Code,
+			Message: err.Message,
+		})
+	}
+	return details
+}
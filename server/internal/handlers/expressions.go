@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/boreymarf/task-fuss/server/internal/api"
+	"github.com/boreymarf/task-fuss/server/internal/dsl"
+	"github.com/boreymarf/task-fuss/server/internal/dto"
+	"github.com/boreymarf/task-fuss/server/internal/logger"
+	"github.com/boreymarf/task-fuss/server/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// ParseExpression godoc
+// @Summary Parse a requirement expression
+// @Description Turns a text expression like "pushups >= 50 and not skipped" into a requirement tree
+// @Tags requirements
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param ParseExpressionRequest body dto.ParseExpressionRequest true "Expression"
+// @Success 200 {object} dto.ParseExpressionResponse "Requirement tree"
+// @Failure 400 {object} api.Error "Syntax error with line and column in details (code: INVALID_EXPRESSION)"
+// @Failure 401 {object} api.Error "Unauthorized"
+// @Router /expressions/parse [post]
+func ParseExpression(c *gin.Context) {
+
+	var req dto.ParseExpressionRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	requirement, err := dsl.Parse(req.Expression)
+	if err != nil {
+		var exprErr *dsl.Error
+		if errors.As(err, &exprErr) {
+			api.InvalidExpression.SendWithDetailsAndAbort(c, exprErr)
+		} else {
+			logger.Log.Err(err).Msg("Failed to parse expression")
+			api.InternalServerError.SendAndAbort(c)
+		}
+		return
+	}
+
+	api.Success(c, dto.ParseExpressionResponse{
+		Requirement: *requirement,
+		Expression:  dsl.Format(requirement),
+	})
+}
+
+// FormatExpression godoc
+// @Summary Format a requirement tree
+// @Description Turns a requirement tree into its canonical text expression
+// @Tags requirements
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param FormatExpressionRequest body dto.FormatExpressionRequest true "Requirement tree"
+// @Success 200 {object} dto.FormatExpressionResponse "Canonical expression"
+// @Failure 400 {object} api.Error "Invalid request format"
+// @Failure 401 {object} api.Error "Unauthorized"
+// @Router /expressions/format [post]
+func FormatExpression(c *gin.Context) {
+
+	var req dto.FormatExpressionRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	api.Success(c, dto.FormatExpressionResponse{
+		Expression: dsl.Format(&req.Requirement),
+	})
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"github.com/boreymarf/task-fuss/server/internal/api"
+	"github.com/boreymarf/task-fuss/server/internal/config"
+	"github.com/boreymarf/task-fuss/server/internal/dto"
+	"github.com/boreymarf/task-fuss/server/internal/security"
+	"github.com/boreymarf/task-fuss/server/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// GetHistory godoc
+// @Summary Get the history of all tasks
+// @Description Returns for every day of the range how many active tasks were due, completed, failed and skipped
+// @Description with their average score, shaped for heatmaps and monthly calendars. Days after today are left out.
+// @Tags history
+// @Security ApiKeyAuth
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param start query string false "Start of the range, YYYY-MM-DD or RFC 3339 (inclusive, default: a year before the end)"
+// @Param end query string false "End of the range, YYYY-MM-DD (inclusive) or RFC 3339 (exclusive), default: today"
+// @Success 200 {object} dto.GetHistoryResponse "Days of the range"
+// @Failure 400 {object} api.Error "Invalid range"
+// @Failure 401 {object} api.Error "Unauthorized"
+// @Failure 500 {object} api.Error "Internal server error"
+// @Router /history [get]
+func (h *TaskHandler) GetHistory(c *gin.Context) {
+
+	r, ok := parseRangeQuery(c)
+	if !ok {
+		return
+	}
+
+	claims := security.GetClaimsFromContext(c)
+
+	days, err := h.taskService.GetHistory(claims.UserID, r)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	api.Success(c, toHistoryResponse(days))
+}
+
+// GetTaskHistory godoc
+// @Summary Get the history of a task
+// @Description Returns the state of the task for every day of the range: completed, failed, skipped, not_due
+// @Description or pending for today, with the score of the day. Days after today are left out.
+// @Tags history
+// @Security ApiKeyAuth
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param task_id path int true "Task ID"
+// @Param start query string false "Start of the range, YYYY-MM-DD or RFC 3339 (inclusive, default: a year before the end)"
+// @Param end query string false "End of the range, YYYY-MM-DD (inclusive) or RFC 3339 (exclusive), default: today"
+// @Success 200 {object} dto.GetHistoryResponse "Days of the range"
+// @Failure 400 {object} api.Error "Invalid task ID or range"
+// @Failure 401 {object} api.Error "Unauthorized"
+// @Failure 403 {object} api.Error "Task belongs to another user"
+// @Failure 404 {object} api.Error "Task not found"
+// @Failure 500 {object} api.Error "Internal server error"
+// @Router /tasks/{task_id}/history [get]
+func (h *TaskHandler) GetTaskHistory(c *gin.Context) {
+
+	taskID, ok := parseIDParam(c, "task_id")
+	if !ok {
+		return
+	}
+
+	r, ok := parseRangeQuery(c)
+	if !ok {
+		return
+	}
+
+	claims := security.GetClaimsFromContext(c)
+
+	days, err := h.taskService.GetTaskHistory(taskID, claims.UserID, r)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	api.Success(c, toHistoryResponse(days))
+}
+
+func toHistoryResponse(days []service.HistoryDay) dto.GetHistoryResponse {
+
+	response := dto.GetHistoryResponse{Days: make([]dto.HistoryDay, 0, len(days))}
+
+	for _, day := range days {
+		response.Days = append(response.Days, dto.HistoryDay{
+			Date:      day.Date.Format(config.DateFormat),
+			State:     day.State,
+			Score:     day.Score,
+			Due:       day.Due,
+			Completed: day.Completed,
+			Failed:    day.Failed,
+			Skipped:   day.Skipped,
+			Pending:   day.Pending,
+		})
+
+		response.Summary.Due += day.Due
+		response.Summary.Completed += day.Completed
+		response.Summary.Failed += day.Failed
+		response.Summary.Skipped += day.Skipped
+	}
+
+	if over := response.Summary.Completed + response.Summary.Failed + response.Summary.Skipped; over > 0 {
+		response.Summary.CompletionRate = float64(response.Summary.Completed) / float64(over)
+	}
+
+	return response
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/boreymarf/task-fuss/server/internal/api"
+	"github.com/boreymarf/task-fuss/server/internal/config"
+	"github.com/boreymarf/task-fuss/server/internal/logger"
+	"github.com/boreymarf/task-fuss/server/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// parseIDParam reads a numeric ID from the path, on failure it sends the error response.
+func parseIDParam(c *gin.Context, name string) (int64, bool) {
+	idParam := c.Param(name)
+
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil || id <= 0 {
+		logger.Log.Warn().Str(name, idParam).Msg("Tried to parse bad id")
+		api.BadRequest.SendWithDetailsAndAbort(c, api.FieldErrorDetail{
+			Field:    name,
+			Expected: "positive integer",
+			Message:  "Field '" + name + "' must be a positive integer",
+		})
+		return 0, false
+	}
+
+	return id, true
+}
+
+// parseIDListQuery reads comma-separated IDs from a query parameter, on failure it sends the error response.
+func parseIDListQuery(c *gin.Context, name string, value string) ([]int64, bool) {
+	if value == "" {
+		return nil, true
+	}
+
+	var ids []int64
+	for _, part := range strings.Split(value, ",") {
+		id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err != nil || id <= 0 {
+			api.InvalidQuery.SendWithDetailsAndAbort(c, api.FieldErrorDetail{
+				Field:    name,
+				Expected: "comma-separated positive integers",
+				Message:  "Field '" + name + "' must be a list of IDs like 1,2,3",
+			})
+			return nil, false
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, true
+}
+
+// parseDateQuery reads an optional date, nil is returned for an empty value. On failure it sends the error response.
+func parseDateQuery(c *gin.Context, name string, value string) (*time.Time, bool) {
+	if value == "" {
+		return nil, true
+	}
+
+	date, err := time.Parse(config.DateFormat, value)
+	if err != nil {
+		api.InvalidQuery.SendWithDetailsAndAbort(c, api.FieldErrorDetail{
+			Field:    name,
+			Expected: config.DateFormat,
+			Message:  "Field '" + name + "' must be a date in YYYY-MM-DD format",
+		})
+		return nil, false
+	}
+
+	return &date, true
+}
+
+// parseRangeQuery reads the "start" and "end" query parameters as a half-open interval.
+// Both accept a date or an RFC 3339 timestamp, a date in "end" includes the whole day.
+// Dates are days of the user's calendar, the service converts them. Missing bounds are left open.
+func parseRangeQuery(c *gin.Context) (service.Range, bool) {
+	r := service.Range{
+		To: time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC),
+	}
+
+	if start := c.Query("start"); start != "" {
+		t, isDate, ok := parseTimeQuery(c, "start", start)
+		if !ok {
+			return r, false
+		}
+		r.From, r.FromDate = t, isDate
+	}
+
+	if end := c.Query("end"); end != "" {
+		t, isDate, ok := parseTimeQuery(c, "end", end)
+		if !ok {
+			return r, false
+		}
+		if isDate {
+			t = t.AddDate(0, 0, 1)
+		}
+		r.To, r.ToDate = t, isDate
+	}
+
+	return r, true
+}
+
+func parseTimeQuery(c *gin.Context, name string, value string) (time.Time, bool, bool) {
+	if t, err := time.Parse(config.DateFormat, value); err == nil {
+		return t, true, true
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), false, true
+	}
+
+	api.InvalidQuery.SendWithDetailsAndAbort(c, api.FieldErrorDetail{
+		Field:    name,
+		Expected: "YYYY-MM-DD or RFC 3339",
+		Message:  "Field '" + name + "' must be a date or a timestamp",
+	})
+	return time.Time{}, false, false
+}
@@ -1,21 +1,27 @@
 package handlers
 
 import (
+	"strings"
+	"time"
+
 	"github.com/boreymarf/task-fuss/server/internal/api"
 	"github.com/boreymarf/task-fuss/server/internal/db"
 	"github.com/boreymarf/task-fuss/server/internal/dto"
 	"github.com/boreymarf/task-fuss/server/internal/logger"
 	"github.com/boreymarf/task-fuss/server/internal/models"
 	"github.com/boreymarf/task-fuss/server/internal/security"
+	"github.com/boreymarf/task-fuss/server/internal/service"
+	"github.com/boreymarf/task-fuss/server/internal/utils"
 	"github.com/gin-gonic/gin"
 )
 
 type ProfileHandler struct {
-	userRepo *db.UserRepository
+	userRepo    *db.UserRepository
+	taskService *service.TaskService
 }
 
-func InitProfileHandler(userRepo *db.UserRepository) (*ProfileHandler, error) {
-	return &ProfileHandler{userRepo: userRepo}, nil
+func InitProfileHandler(userRepo *db.UserRepository, taskService *service.TaskService) (*ProfileHandler, error) {
+	return &ProfileHandler{userRepo: userRepo, taskService: taskService}, nil
 }
 
 // GetProfile godoc
@@ -56,8 +62,53 @@ func (h *ProfileHandler) GetProfile(c *gin.Context) {
 	}
 
 	dtoProfileResponse := dto.ProfileResponse{
-		User: dtoUser,
+		User:     dtoUser,
+		Settings: toUserSettings(&modelsUser),
 	}
 
 	api.Success(c, dtoProfileResponse)
 }
+
+// UpdateSettings godoc
+// @Summary Update user settings
+// @Description Changes the settings of the authenticated user, omitted fields are left as they are.
+// @Description A new time zone or day end hour moves logged entries to other days, their outcomes are evaluated again.
+// @Tags profile
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param UpdateSettingsRequest body dto.UpdateSettingsRequest true "New settings"
+// @Success 200 {object} dto.UpdateSettingsResponse "Settings updated"
+// @Failure 400 {object} api.Error "Invalid request format or time zone (code: VALIDATION_FAILED)"
+// @Failure 401 {object} api.Error "Unauthorized"
+// @Failure 500 {object} api.Error "Internal server error"
+// @Router /profile/settings [put]
+func (h *ProfileHandler) UpdateSettings(c *gin.Context) {
+
+	var req dto.UpdateSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	claims := security.GetClaimsFromContext(c)
+
+	user, err := h.taskService.UpdateSettings(claims.UserID, &req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	api.Success(c, dto.UpdateSettingsResponse{
+		Settings: toUserSettings(&user),
+	})
+}
+
+func toUserSettings(user *models.User) dto.UserSettings {
+	return dto.UserSettings{
+		WeekStart: strings.ToLower(time.Weekday(user.WeekStart).String()),
+		Timezone:  user.Timezone,
+		DayEndsAt: user.DayEndHour,
+	}
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"github.com/boreymarf/task-fuss/server/internal/api"
+	"github.com/boreymarf/task-fuss/server/internal/dto"
+	"github.com/boreymarf/task-fuss/server/internal/security"
+	"github.com/boreymarf/task-fuss/server/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type SearchQuery struct {
+	Query string `form:"q" binding:"required"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// Search godoc
+// @Summary Search tasks
+// @Description Finds tasks by the words in their title or description, requirement titles and notes of entries.
+// @Description Every word has to match, words also match longer words they are the beginning of. The best matches come first.
+// @Tags search
+// @Security ApiKeyAuth
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param q query string true "Words to search for"
+// @Param limit query int false "Maximum number of results (default: 20, max: 100)"
+// @Success 200 {object} dto.SearchResponse "Matches with highlighted snippets"
+// @Failure 400 {object} api.Error "Invalid query parameters"
+// @Failure 401 {object} api.Error "Unauthorized"
+// @Failure 500 {object} api.Error "Internal server error"
+// @Router /search [get]
+func (h *TaskHandler) Search(c *gin.Context) {
+
+	var queryParams SearchQuery
+	if err := c.ShouldBindQuery(&queryParams); err != nil {
+		api.InvalidQuery.SendAndAbort(c)
+		return
+	}
+
+	limit := service.DefaultSearchLimit
+	if queryParams.Limit != 0 {
+		limit = queryParams.Limit
+	}
+
+	claims := security.GetClaimsFromContext(c)
+
+	results, err := h.taskService.Search(claims.UserID, queryParams.Query, limit)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	api.Success(c, dto.SearchResponse{Results: results})
+}
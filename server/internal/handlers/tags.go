@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"github.com/boreymarf/task-fuss/server/internal/api"
+	"github.com/boreymarf/task-fuss/server/internal/dto"
+	"github.com/boreymarf/task-fuss/server/internal/security"
+	"github.com/boreymarf/task-fuss/server/internal/service"
+	"github.com/boreymarf/task-fuss/server/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type TagHandler struct {
+	taskService *service.TaskService
+}
+
+func InitTagHandler(taskService *service.TaskService) (*TagHandler, error) {
+	return &TagHandler{taskService: taskService}, nil
+}
+
+// CreateTag godoc
+// @Summary Create a tag
+// @Description Creates a tag that can be put on tasks, names are unique per user regardless of case
+// @Tags tags
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param CreateTagRequest body dto.CreateTagRequest true "Tag data"
+// @Success 201 {object} dto.TagResponse "Created tag"
+// @Failure 400 {object} api.Error "Invalid request format, name or color (code: VALIDATION_FAILED)"
+// @Failure 401 {object} api.Error "Unauthorized"
+// @Failure 500 {object} api.Error "Internal server error"
+// @Router /tags [post]
+func (h *TagHandler) CreateTag(c *gin.Context) {
+
+	var req dto.CreateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	claims := security.GetClaimsFromContext(c)
+
+	tag, err := h.taskService.CreateTag(claims.UserID, &req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	api.Created(c, dto.TagResponse{Tag: tag})
+}
+
+// GetTags godoc
+// @Summary List tags
+// @Description Returns every tag of the user ordered by name
+// @Tags tags
+// @Security ApiKeyAuth
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {object} dto.GetTagsResponse "Tags"
+// @Failure 401 {object} api.Error "Unauthorized"
+// @Failure 500 {object} api.Error "Internal server error"
+// @Router /tags [get]
+func (h *TagHandler) GetTags(c *gin.Context) {
+
+	claims := security.GetClaimsFromContext(c)
+
+	tags, err := h.taskService.GetTags(claims.UserID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	api.Success(c, dto.GetTagsResponse{Tags: tags})
+}
+
+// UpdateTag godoc
+// @Summary Update a tag
+// @Description Renames or recolors the tag, the tasks keep it
+// @Tags tags
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param tag_id path int true "Tag ID"
+// @Param UpdateTagRequest body dto.UpdateTagRequest true "Fields to change"
+// @Success 200 {object} dto.TagResponse "Updated tag"
+// @Failure 400 {object} api.Error "Invalid request format, name or color (code: VALIDATION_FAILED)"
+// @Failure 401 {object} api.Error "Unauthorized"
+// @Failure 403 {object} api.Error "Tag belongs to another user"
+// @Failure 404 {object} api.Error "Tag not found"
+// @Failure 500 {object} api.Error "Internal server error"
+// @Router /tags/{tag_id} [put]
+func (h *TagHandler) UpdateTag(c *gin.Context) {
+
+	tagID, ok := parseIDParam(c, "tag_id")
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	claims := security.GetClaimsFromContext(c)
+
+	tag, err := h.taskService.UpdateTag(tagID, claims.UserID, &req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	api.Success(c, dto.TagResponse{Tag: tag})
+}
+
+// DeleteTag godoc
+// @Summary Delete a tag
+// @Description Deletes the tag and removes it from every task, the tasks themselves are kept
+// @Tags tags
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Param tag_id path int true "Tag ID"
+// @Success 204 "Tag deleted"
+// @Failure 400 {object} api.Error "Invalid tag ID"
+// @Failure 401 {object} api.Error "Unauthorized"
+// @Failure 403 {object} api.Error "Tag belongs to another user"
+// @Failure 404 {object} api.Error "Tag not found"
+// @Failure 500 {object} api.Error "Internal server error"
+// @Router /tags/{tag_id} [delete]
+func (h *TagHandler) DeleteTag(c *gin.Context) {
+
+	tagID, ok := parseIDParam(c, "tag_id")
+	if !ok {
+		return
+	}
+
+	claims := security.GetClaimsFromContext(c)
+
+	if err := h.taskService.DeleteTag(tagID, claims.UserID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	api.NoContent(c)
+}
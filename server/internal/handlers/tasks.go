@@ -1,11 +1,11 @@
 package handlers
 
 import (
-	"database/sql"
-	"errors"
 	"strconv"
+	"time"
 
 	"github.com/boreymarf/task-fuss/server/internal/api"
+	"github.com/boreymarf/task-fuss/server/internal/config"
 	"github.com/boreymarf/task-fuss/server/internal/db"
 	"github.com/boreymarf/task-fuss/server/internal/dto"
 	"github.com/boreymarf/task-fuss/server/internal/logger"
@@ -52,7 +52,7 @@ func InitTaskHandler(
 // @Param Authorization header string true "Bearer token"
 // @Param CreateTaskRequest body dto.CreateTaskRequest true "Task creation data"
 // @Success 201 {object} dto.CreateTaskResponse "Task successfully created"
-// @Failure 400 {object} api.Error "Invalid request format"
+// @Failure 400 {object} api.Error "Invalid request format, requirement tree (code: VALIDATION_FAILED) or expression (code: INVALID_EXPRESSION)"
 // @Failure 401 {object} api.Error "Unauthorized"
 // @Failure 500 {object} api.Error "Internal server error"
 // @Router /tasks [post]
@@ -69,7 +69,8 @@ func (h *TaskHandler) CreateTask(c *gin.Context) {
 
 	createdTask, err := h.taskService.CreateTask(&req, claims.UserID)
 	if err != nil {
-		api.InternalServerError.SendAndAbort(c)
+		handleServiceError(c, err)
+		return
 	}
 
 	api.Accepted(c, createdTask)
@@ -80,19 +81,48 @@ type GetAllTasksQuery struct {
 	ShowActive    string `form:"active" binding:"omitempty,oneof=true false"`
 	ShowArchived  string `form:"archived" binding:"omitempty,oneof=true false"`
 	ShowCompleted string `form:"completed" binding:"omitempty,oneof=true false"`
+	WithStats     string `form:"stats" binding:"omitempty,oneof=true false"`
+	StatsWindow   int    `form:"window" binding:"omitempty,min=1,max=3650"`
+	Due           string `form:"due" binding:"omitempty"`
+	Tags          string `form:"tags" binding:"omitempty"`
+	TagMatch      string `form:"tag_match" binding:"omitempty,oneof=any all"`
+	ExcludeTags   string `form:"exclude_tags" binding:"omitempty"`
+	StartFrom     string `form:"start_from" binding:"omitempty"`
+	StartTo       string `form:"start_to" binding:"omitempty"`
+	EndFrom       string `form:"end_from" binding:"omitempty"`
+	EndTo         string `form:"end_to" binding:"omitempty"`
+	Sort          string `form:"sort" binding:"omitempty,oneof=title created_at start_date streak"`
+	Order         string `form:"order" binding:"omitempty,oneof=asc desc"`
+	Limit         int    `form:"limit" binding:"omitempty,min=1,max=200"`
+	Cursor        string `form:"cursor" binding:"omitempty"`
 }
 
 // GetAllTasks godoc
 // @Summary Get all tasks with filtering options
-// @Description Retrieves tasks based on filter criteria (active/archived/completed) and detail level
+// @Description Retrieves a page of tasks based on filter criteria (active/archived/completed) and detail level.
+// @Description Pass next_cursor or prev_cursor of the response as cursor with the same sort and order to get the neighbouring page.
 // @Tags tasks
 // @Security ApiKeyAuth
 // @Produce json
 // @Param Authorization header string true "Bearer token"
-// @Param detailLevel query string false "Detail level" Enums(minimal, standard, full)
-// @Param showActive query boolean false "Include active tasks (default: true)"
-// @Param showArchived query boolean false "Include archived tasks (default: false)"
-// @Param showCompleted query boolean false "Include completed tasks (default: true)"
+// @Param detail query string false "Detail level" Enums(minimal, basic, full)
+// @Param active query boolean false "Include active tasks (default: true)"
+// @Param archived query boolean false "Include archived tasks (default: false)"
+// @Param completed query boolean false "Include completed tasks (default: true)"
+// @Param stats query boolean false "Include streaks and completion metrics (default: false)"
+// @Param window query int false "Number of days the completion rate is calculated over (default: 30)"
+// @Param due query string false "Only tasks scheduled for the date in YYYY-MM-DD format"
+// @Param tags query string false "Comma-separated tag IDs, only tasks with these tags"
+// @Param tag_match query string false "Whether tasks need any or all of the tags (default: any)" Enums(any, all)
+// @Param exclude_tags query string false "Comma-separated tag IDs, tasks with any of these tags are left out"
+// @Param start_from query string false "Only tasks starting on or after the date in YYYY-MM-DD format"
+// @Param start_to query string false "Only tasks starting on or before the date in YYYY-MM-DD format"
+// @Param end_from query string false "Only tasks ending on or after the date in YYYY-MM-DD format, tasks without an end date are included"
+// @Param end_to query string false "Only tasks ending on or before the date in YYYY-MM-DD format, tasks without an end date are left out"
+// @Param sort query string false "Sort key, ties are broken by ID (default: created_at). streak is not indexed, every task is read and sorted for each page, like with due" Enums(title, created_at, start_date, streak)
+// @Param order query string false "Sort order (default: asc)" Enums(asc, desc)
+// @Param limit query int false "Page size (default: 50, max: 200)"
+// @Param cursor query string false "next_cursor or prev_cursor of an earlier page"
 // @Success 200 {object} dto.GetAllTasksResponse "List of tasks"
 // @Failure 400 {object} api.Error "Invalid query parameters"
 // @Failure 401 {object} api.Error "Unauthorized"
@@ -103,10 +133,21 @@ func (h *TaskHandler) GetAllTasks(c *gin.Context) {
 	var queryParams GetAllTasksQuery
 	if err := c.ShouldBindQuery(&queryParams); err != nil {
 		api.InvalidQuery.SendAndAbort(c)
+		return
 	}
 
 	opts := service.GetAllTasksOptions{
 		DetailLevel: queryParams.DetailLevel,
+		SortBy:      service.SortByCreatedAt,
+		Desc:        queryParams.Order == "desc",
+		Limit:       service.DefaultPageSize,
+		Cursor:      queryParams.Cursor,
+	}
+	if queryParams.Sort != "" {
+		opts.SortBy = queryParams.Sort
+	}
+	if queryParams.Limit != 0 {
+		opts.Limit = queryParams.Limit
 	}
 
 	opts.ShowActive = true
@@ -123,19 +164,59 @@ func (h *TaskHandler) GetAllTasks(c *gin.Context) {
 		opts.ShowCompleted = queryParams.ShowCompleted == "true"
 	}
 
+	opts.WithStats = queryParams.WithStats == "true"
+	opts.StatsWindow = service.DefaultStatsWindow
+	if queryParams.StatsWindow != 0 {
+		opts.StatsWindow = queryParams.StatsWindow
+	}
+
+	var ok bool
+	dates := []struct {
+		name  string
+		value string
+		dest  **time.Time
+	}{
+		{"due", queryParams.Due, &opts.DueOn},
+		{"start_from", queryParams.StartFrom, &opts.StartFrom},
+		{"start_to", queryParams.StartTo, &opts.StartTo},
+		{"end_from", queryParams.EndFrom, &opts.EndFrom},
+		{"end_to", queryParams.EndTo, &opts.EndTo},
+	}
+	for _, date := range dates {
+		if *date.dest, ok = parseDateQuery(c, date.name, date.value); !ok {
+			return
+		}
+	}
+
+	if opts.IncludeTagIDs, ok = parseIDListQuery(c, "tags", queryParams.Tags); !ok {
+		return
+	}
+	if opts.ExcludeTagIDs, ok = parseIDListQuery(c, "exclude_tags", queryParams.ExcludeTags); !ok {
+		return
+	}
+	opts.MatchAllTags = queryParams.TagMatch == "all"
+
 	claims := security.GetClaimsFromContext(c)
 
-	tasks, err := h.taskService.GetAllTasks(&opts, claims.UserID)
+	page, err := h.taskService.GetAllTasks(&opts, claims.UserID)
 	if err != nil {
-		logger.Log.Err(err).Msg("Failed to get all tasks")
-		api.InternalServerError.SendAndAbort(c)
+		handleServiceError(c, err)
+		return
 	}
 
 	api.Success(c, dto.GetAllTasksResponse{
-		Tasks: tasks,
+		Tasks:      page.Tasks,
+		Total:      page.Total,
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
 	})
 }
 
+type GetTaskByIDQuery struct {
+	WithStats   string `form:"stats" binding:"omitempty,oneof=true false"`
+	StatsWindow int    `form:"window" binding:"omitempty,min=1,max=3650"`
+}
+
 // GetTaskByID godoc
 // @Summary Get a task by ID
 // @Description Retrieves a single task by its unique identifier
@@ -143,32 +224,42 @@ func (h *TaskHandler) GetAllTasks(c *gin.Context) {
 // @Security ApiKeyAuth
 // @Produce json
 // @Param Authorization header string true "Bearer token"
-// @Param task_id path string true "Task ID" Format(uuid)
+// @Param task_id path int true "Task ID"
+// @Param stats query boolean false "Include streaks and completion metrics (default: false)"
+// @Param window query int false "Number of days the completion rate is calculated over (default: 30)"
 // @Success 200 {object} dto.GetTaskByIDResponse "Task details"
 // @Failure 400 {object} api.Error "Invalid task ID format"
 // @Failure 401 {object} api.Error "Unauthorized"
+// @Failure 403 {object} api.Error "Task belongs to another user"
 // @Failure 404 {object} api.Error "Task not found"
 // @Failure 500 {object} api.Error "Internal server error"
 // @Router /tasks/{task_id} [get]
 func (h *TaskHandler) GetTaskByID(c *gin.Context) {
 
-	idParam := c.Param("task_id")
+	taskID, ok := parseIDParam(c, "task_id")
+	if !ok {
+		return
+	}
 
-	taskId, err := strconv.ParseInt(idParam, 10, 64)
-	if err != nil {
-		logger.Log.Warn().Str("task_id", idParam).Msg("Tried to parse bad task id")
-		api.BadRequest.SendAndAbort(c)
+	var queryParams GetTaskByIDQuery
+	if err := c.ShouldBindQuery(&queryParams); err != nil {
+		api.InvalidQuery.SendAndAbort(c)
+		return
+	}
+
+	opts := service.GetTaskOptions{
+		WithStats:   queryParams.WithStats == "true",
+		StatsWindow: service.DefaultStatsWindow,
+	}
+	if queryParams.StatsWindow != 0 {
+		opts.StatsWindow = queryParams.StatsWindow
 	}
 
 	claims := security.GetClaimsFromContext(c)
 
-	dtoTask, err := h.taskService.GetTaskByID(taskId, claims.UserID)
+	dtoTask, err := h.taskService.GetTaskByID(taskID, claims.UserID, &opts)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			api.NotFound.SendWithDetailsAndAbort(c, gin.H{"error": "Task not found"})
-		} else {
-			api.InternalServerError.SendAndAbort(c)
-		}
+		handleServiceError(c, err)
 		return
 	}
 
@@ -179,28 +270,403 @@ func (h *TaskHandler) GetTaskByID(c *gin.Context) {
 	api.Success(c, data)
 }
 
-// func (h *TaskHandler) DeleteTaskByID(c *gin.Context) {
-//
-// 	idParam := c.Param("task_id")
-//
-// 	taskId, err := strconv.ParseInt(idParam, 10, 64)
-// 	if err != nil {
-// 		logger.Log.Warn().Str("task_id", idParam).Msg("Tried to parse bad task id")
-// 		api.BadRequest.SendAndAbort(c)
-// 	}
-//
-// 	claims := security.GetClaimsFromContext(c)
-//
-// 	dtoTask, err := h.taskService.GetTaskByID(taskId, claims.UserID)
-// 	if err != nil {
-// 		if errors.Is(err, sql.ErrNoRows) {
-// 			api.NotFound.SendWithDetailsAndAbort(c, gin.H{"error": "Task not found"})
-// 		} else {
-// 			api.InternalServerError.SendAndAbort(c)
-// 		}
-// 		return
-// 	}
-//
-// 	api.Success(c, d)
-//
-// }
+// UpdateTask godoc
+// @Summary Update a task
+// @Description Changes the fields that are set and replaces the requirement tree if one is given.
+// @Description The new tree is a revision effective from effective_from, days before it keep their outcomes.
+// @Description Requirements that keep their ID are updated in place with their logged values, requirements without one are created and missing ones are removed.
+// @Tags tasks
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param task_id path int true "Task ID"
+// @Param UpdateTaskRequest body dto.UpdateTaskRequest true "Fields to change"
+// @Success 200 {object} dto.UpdateTaskResponse "Updated task"
+// @Failure 400 {object} api.Error "Invalid request format, fields (code: VALIDATION_FAILED) or expression (code: INVALID_EXPRESSION)"
+// @Failure 401 {object} api.Error "Unauthorized"
+// @Failure 403 {object} api.Error "Task belongs to another user"
+// @Failure 404 {object} api.Error "Task not found"
+// @Failure 500 {object} api.Error "Internal server error"
+// @Router /tasks/{task_id} [put]
+func (h *TaskHandler) UpdateTask(c *gin.Context) {
+
+	taskID, ok := parseIDParam(c, "task_id")
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	claims := security.GetClaimsFromContext(c)
+
+	task, err := h.taskService.UpdateTask(taskID, claims.UserID, &req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	api.Success(c, dto.UpdateTaskResponse{Task: task})
+}
+
+type GetTaskStatusQuery struct {
+	Date string `form:"date" binding:"omitempty"`
+}
+
+// GetTaskStatus godoc
+// @Summary Evaluate a task for a day
+// @Description Evaluates the requirement tree of the task against the values logged on the given date
+// @Tags tasks
+// @Security ApiKeyAuth
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param task_id path int true "Task ID"
+// @Param date query string false "Date in YYYY-MM-DD format (default: today in the time zone of the user)"
+// @Success 200 {object} dto.GetTaskStatusResponse "Task status for the day"
+// @Failure 400 {object} api.Error "Invalid task ID or date"
+// @Failure 401 {object} api.Error "Unauthorized"
+// @Failure 403 {object} api.Error "Task belongs to another user"
+// @Failure 404 {object} api.Error "Task not found"
+// @Failure 500 {object} api.Error "Internal server error"
+// @Router /tasks/{task_id}/status [get]
+func (h *TaskHandler) GetTaskStatus(c *gin.Context) {
+
+	idParam := c.Param("task_id")
+
+	taskID, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		logger.Log.Warn().Str("task_id", idParam).Msg("Tried to parse bad task id")
+		api.InvalidTaskID.SendAndAbort(c)
+		return
+	}
+
+	var queryParams GetTaskStatusQuery
+	if err := c.ShouldBindQuery(&queryParams); err != nil {
+		api.InvalidQuery.SendAndAbort(c)
+		return
+	}
+
+	var date *time.Time
+	if queryParams.Date != "" {
+		parsed, err := time.Parse(config.DateFormat, queryParams.Date)
+		if err != nil {
+			api.InvalidQuery.SendWithDetailsAndAbort(c, api.FieldErrorDetail{
+				Field:    "date",
+				Expected: config.DateFormat,
+				Message:  "Field 'date' must be a date in YYYY-MM-DD format",
+			})
+			return
+		}
+		date = &parsed
+	}
+
+	claims := security.GetClaimsFromContext(c)
+
+	evaluation, err := h.taskService.EvaluateTask(taskID, claims.UserID, date)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	api.Success(c, dto.GetTaskStatusResponse{
+		Status: toTaskStatus(evaluation),
+	})
+}
+
+// GetTaskDays godoc
+// @Summary Get daily outcomes of a task
+// @Description Returns whether the task was completed and its partial credit score for every day something was logged on
+// @Tags tasks
+// @Security ApiKeyAuth
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param task_id path int true "Task ID"
+// @Param start query string false "Start of the range, YYYY-MM-DD or RFC 3339 (inclusive)"
+// @Param end query string false "End of the range, YYYY-MM-DD (inclusive) or RFC 3339 (exclusive)"
+// @Success 200 {object} dto.GetTaskDaysResponse "Outcomes ordered by date"
+// @Failure 400 {object} api.Error "Invalid task ID or range"
+// @Failure 401 {object} api.Error "Unauthorized"
+// @Failure 403 {object} api.Error "Task belongs to another user"
+// @Failure 404 {object} api.Error "Task not found"
+// @Failure 500 {object} api.Error "Internal server error"
+// @Router /tasks/{task_id}/days [get]
+func (h *TaskHandler) GetTaskDays(c *gin.Context) {
+
+	taskID, ok := parseIDParam(c, "task_id")
+	if !ok {
+		return
+	}
+
+	r, ok := parseRangeQuery(c)
+	if !ok {
+		return
+	}
+
+	claims := security.GetClaimsFromContext(c)
+
+	entries, err := h.taskService.GetTaskDays(taskID, claims.UserID, r)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	days := make([]dto.TaskDay, 0, len(entries))
+	for _, entry := range entries {
+		days = append(days, dto.TaskDay{
+			Date:      entry.EntryDate.Format(config.DateFormat),
+			Completed: entry.Completed,
+			Score:     entry.Score,
+		})
+	}
+
+	api.Success(c, dto.GetTaskDaysResponse{
+		Days: days,
+	})
+}
+
+// GetTaskRevisions godoc
+// @Summary Get the revision history of a task
+// @Description Returns every version of the requirement tree with the days it is used for, oldest first.
+// @Description Days are always evaluated with the revision that was in effect on them.
+// @Tags tasks
+// @Security ApiKeyAuth
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param task_id path int true "Task ID"
+// @Success 200 {object} dto.GetTaskRevisionsResponse "Revisions ordered by the day they take effect"
+// @Failure 400 {object} api.Error "Invalid task ID"
+// @Failure 401 {object} api.Error "Unauthorized"
+// @Failure 403 {object} api.Error "Task belongs to another user"
+// @Failure 404 {object} api.Error "Task not found"
+// @Failure 500 {object} api.Error "Internal server error"
+// @Router /tasks/{task_id}/revisions [get]
+func (h *TaskHandler) GetTaskRevisions(c *gin.Context) {
+
+	taskID, ok := parseIDParam(c, "task_id")
+	if !ok {
+		return
+	}
+
+	claims := security.GetClaimsFromContext(c)
+
+	revisions, err := h.taskService.GetTaskRevisions(taskID, claims.UserID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	api.Success(c, dto.GetTaskRevisionsResponse{
+		Revisions: revisions,
+	})
+}
+
+func toTaskStatus(evaluation *service.TaskEvaluation) dto.TaskStatus {
+	return dto.TaskStatus{
+		TaskID:      evaluation.TaskID,
+		Date:        evaluation.Date.Format(config.DateFormat),
+		Due:         evaluation.Due,
+		Completed:   evaluation.Passed,
+		State:       evaluation.State,
+		Score:       evaluation.Score,
+		Requirement: toRequirementStatus(&evaluation.Requirement),
+	}
+}
+
+func toRequirementStatus(result *service.RequirementResult) dto.RequirementStatus {
+	status := dto.RequirementStatus{
+		RequirementID: result.RequirementID,
+		Title:         result.Title,
+		Passed:        result.Passed,
+		State:         result.State,
+		Score:         result.Score,
+		Value:         result.Value,
+	}
+
+	for i := range result.Operands {
+		status.Operands = append(status.Operands, toRequirementStatus(&result.Operands[i]))
+	}
+
+	return status
+}
+
+// ArchiveTask godoc
+// @Summary Archive a task
+// @Description Hides an active or completed task from the default task list, archiving an archived task does nothing
+// @Tags tasks
+// @Security ApiKeyAuth
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param task_id path int true "Task ID"
+// @Success 200 {object} dto.UpdateTaskResponse "Archived task"
+// @Failure 400 {object} api.Error "Invalid task ID"
+// @Failure 401 {object} api.Error "Unauthorized"
+// @Failure 403 {object} api.Error "Task belongs to another user"
+// @Failure 404 {object} api.Error "Task not found"
+// @Failure 500 {object} api.Error "Internal server error"
+// @Router /tasks/{task_id}/archive [post]
+func (h *TaskHandler) ArchiveTask(c *gin.Context) {
+	h.changeTaskStatus(c, h.taskService.ArchiveTask)
+}
+
+// UnarchiveTask godoc
+// @Summary Unarchive a task
+// @Description Makes an archived task active again
+// @Tags tasks
+// @Security ApiKeyAuth
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param task_id path int true "Task ID"
+// @Success 200 {object} dto.UpdateTaskResponse "Active task"
+// @Failure 400 {object} api.Error "Invalid task ID or the task is completed (code: VALIDATION_FAILED)"
+// @Failure 401 {object} api.Error "Unauthorized"
+// @Failure 403 {object} api.Error "Task belongs to another user"
+// @Failure 404 {object} api.Error "Task not found"
+// @Failure 500 {object} api.Error "Internal server error"
+// @Router /tasks/{task_id}/unarchive [post]
+func (h *TaskHandler) UnarchiveTask(c *gin.Context) {
+	h.changeTaskStatus(c, h.taskService.UnarchiveTask)
+}
+
+// CompleteTask godoc
+// @Summary Mark a task as completed
+// @Description Marks an active or archived task as done for good, use PUT /tasks/{task_id} with status 'active' to reopen it
+// @Tags tasks
+// @Security ApiKeyAuth
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param task_id path int true "Task ID"
+// @Success 200 {object} dto.UpdateTaskResponse "Completed task"
+// @Failure 400 {object} api.Error "Invalid task ID"
+// @Failure 401 {object} api.Error "Unauthorized"
+// @Failure 403 {object} api.Error "Task belongs to another user"
+// @Failure 404 {object} api.Error "Task not found"
+// @Failure 500 {object} api.Error "Internal server error"
+// @Router /tasks/{task_id}/complete [post]
+func (h *TaskHandler) CompleteTask(c *gin.Context) {
+	h.changeTaskStatus(c, h.taskService.CompleteTask)
+}
+
+func (h *TaskHandler) changeTaskStatus(c *gin.Context, change func(taskID int64, userID int64) (dto.Task, error)) {
+
+	taskID, ok := parseIDParam(c, "task_id")
+	if !ok {
+		return
+	}
+
+	claims := security.GetClaimsFromContext(c)
+
+	task, err := change(taskID, claims.UserID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	api.Success(c, dto.UpdateTaskResponse{Task: task})
+}
+
+// DeleteTask godoc
+// @Summary Delete a task
+// @Description Moves the task to the trash, it can be restored until it is purged 30 days later
+// @Tags tasks
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Param task_id path int true "Task ID"
+// @Success 204 "Task moved to the trash"
+// @Failure 400 {object} api.Error "Invalid task ID"
+// @Failure 401 {object} api.Error "Unauthorized"
+// @Failure 403 {object} api.Error "Task belongs to another user"
+// @Failure 404 {object} api.Error "Task not found"
+// @Failure 500 {object} api.Error "Internal server error"
+// @Router /tasks/{task_id} [delete]
+func (h *TaskHandler) DeleteTask(c *gin.Context) {
+
+	taskID, ok := parseIDParam(c, "task_id")
+	if !ok {
+		return
+	}
+
+	claims := security.GetClaimsFromContext(c)
+
+	if err := h.taskService.DeleteTask(taskID, claims.UserID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	api.NoContent(c)
+}
+
+// GetTrash godoc
+// @Summary List deleted tasks
+// @Description Returns the tasks in the trash, the most recently deleted first
+// @Tags trash
+// @Security ApiKeyAuth
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {object} dto.GetTrashResponse "Deleted tasks"
+// @Failure 401 {object} api.Error "Unauthorized"
+// @Failure 500 {object} api.Error "Internal server error"
+// @Router /trash [get]
+func (h *TaskHandler) GetTrash(c *gin.Context) {
+
+	claims := security.GetClaimsFromContext(c)
+
+	tasks, err := h.taskService.GetTrash(claims.UserID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	api.Success(c, dto.GetTrashResponse{Tasks: tasks})
+}
+
+// RestoreTask godoc
+// @Summary Restore a deleted task
+// @Description Takes the task out of the trash with everything logged for it
+// @Tags trash
+// @Security ApiKeyAuth
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param task_id path int true "Task ID"
+// @Success 200 {object} dto.UpdateTaskResponse "Restored task"
+// @Failure 400 {object} api.Error "Invalid task ID"
+// @Failure 401 {object} api.Error "Unauthorized"
+// @Failure 404 {object} api.Error "Task is not in the trash"
+// @Failure 500 {object} api.Error "Internal server error"
+// @Router /trash/{task_id}/restore [post]
+func (h *TaskHandler) RestoreTask(c *gin.Context) {
+	h.changeTaskStatus(c, h.taskService.RestoreTask)
+}
+
+// PurgeTask godoc
+// @Summary Delete a task for good
+// @Description Deletes a task in the trash with its requirements and everything logged for it without waiting for the retention period
+// @Tags trash
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Param task_id path int true "Task ID"
+// @Success 204 "Task deleted"
+// @Failure 400 {object} api.Error "Invalid task ID"
+// @Failure 401 {object} api.Error "Unauthorized"
+// @Failure 404 {object} api.Error "Task is not in the trash"
+// @Failure 500 {object} api.Error "Internal server error"
+// @Router /trash/{task_id} [delete]
+func (h *TaskHandler) PurgeTask(c *gin.Context) {
+
+	taskID, ok := parseIDParam(c, "task_id")
+	if !ok {
+		return
+	}
+
+	claims := security.GetClaimsFromContext(c)
+
+	if err := h.taskService.PurgeTask(taskID, claims.UserID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	api.NoContent(c)
+}
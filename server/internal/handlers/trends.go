@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"github.com/boreymarf/task-fuss/server/internal/api"
+	"github.com/boreymarf/task-fuss/server/internal/config"
+	"github.com/boreymarf/task-fuss/server/internal/dto"
+	"github.com/boreymarf/task-fuss/server/internal/security"
+	"github.com/boreymarf/task-fuss/server/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type GetTrendQuery struct {
+	Window int `form:"window" binding:"omitempty,min=1,max=365"`
+}
+
+// GetRequirementTrend godoc
+// @Summary Get the trend of a requirement
+// @Description Returns the values logged for an int, float, duration or rating requirement by day, in its unit
+// @Description and durations in seconds, with a moving average, personal bests and week over week changes.
+// @Description Personal bests follow the target, lower is better for "run_time <= 25m". Days after today are left out.
+// @Tags entries
+// @Security ApiKeyAuth
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param requirement_id path int true "Requirement ID"
+// @Param start query string false "Start of the range, YYYY-MM-DD or RFC 3339 (inclusive, default: 90 days before the end)"
+// @Param end query string false "End of the range, YYYY-MM-DD (inclusive) or RFC 3339 (exclusive), default: today"
+// @Param window query int false "Days of the moving average (default: 7, max: 365)"
+// @Success 200 {object} dto.GetTrendResponse "Values by day and week"
+// @Failure 400 {object} api.Error "Invalid query parameters or the requirement is not numeric (code: VALIDATION_FAILED)"
+// @Failure 401 {object} api.Error "Unauthorized"
+// @Failure 403 {object} api.Error "Requirement belongs to another user"
+// @Failure 404 {object} api.Error "Requirement not found"
+// @Failure 500 {object} api.Error "Internal server error"
+// @Router /requirements/{requirement_id}/trend [get]
+func (h *EntriesHandler) GetRequirementTrend(c *gin.Context) {
+
+	requirementID, ok := parseIDParam(c, "requirement_id")
+	if !ok {
+		return
+	}
+
+	var queryParams GetTrendQuery
+	if err := c.ShouldBindQuery(&queryParams); err != nil {
+		api.InvalidQuery.SendAndAbort(c)
+		return
+	}
+
+	r, ok := parseRangeQuery(c)
+	if !ok {
+		return
+	}
+
+	claims := security.GetClaimsFromContext(c)
+
+	trend, err := h.taskService.GetRequirementTrend(requirementID, claims.UserID, r, queryParams.Window)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	api.Success(c, toTrendResponse(&trend))
+}
+
+func toTrendResponse(trend *service.RequirementTrend) dto.GetTrendResponse {
+
+	response := dto.GetTrendResponse{
+		RequirementID: trend.RequirementID,
+		DataType:      trend.DataType,
+		Unit:          trend.Unit,
+		Direction:     trend.Direction,
+		Window:        trend.Window,
+		Days:          make([]dto.TrendDay, 0, len(trend.Days)),
+		Weeks:         make([]dto.TrendWeek, 0, len(trend.Weeks)),
+		Summary: dto.TrendSummary{
+			Count:   trend.Count,
+			Average: trend.Average,
+			Min:     toTrendRecord(trend.Min),
+			Max:     toTrendRecord(trend.Max),
+			Best:    toTrendRecord(trend.Best),
+		},
+	}
+
+	for _, day := range trend.Days {
+		response.Days = append(response.Days, dto.TrendDay{
+			Date:          day.Date.Format(config.DateFormat),
+			Value:         day.Value,
+			MovingAverage: day.MovingAverage,
+			PersonalBest:  day.PersonalBest,
+		})
+	}
+
+	for _, week := range trend.Weeks {
+		response.Weeks = append(response.Weeks, dto.TrendWeek{
+			Start:   week.Start.Format(config.DateFormat),
+			Count:   week.Count,
+			Average: week.Average,
+			Change:  week.Change,
+		})
+	}
+
+	return response
+}
+
+func toTrendRecord(record *service.TrendRecord) *dto.TrendRecord {
+	if record == nil {
+		return nil
+	}
+	return &dto.TrendRecord{
+		Date:  record.Date.Format(config.DateFormat),
+		Value: record.Value,
+	}
+}
@@ -15,6 +15,19 @@ type Task struct {
 	StartDate   sql.NullTime `json:"start_date"`
 	EndDate     sql.NullTime `json:"end_date"`
 	Status      string       `json:"status"`
+	Recurrence  *string      `json:"recurrence"`
+	// DeletedAt is set while the task is in the trash
+	DeletedAt sql.NullTime `json:"deleted_at"`
+	// SortKey is the value the task list was sorted by when the task was read with a sort key
+	SortKey string `json:"-"`
+}
+
+type Tag struct {
+	ID        int64     `json:"id"`
+	OwnerID   int64     `json:"owner_id"`
+	Name      string    `json:"name"`
+	Color     *string   `json:"color"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 type TaskEntry struct {
@@ -22,19 +35,39 @@ type TaskEntry struct {
 	TaskID    int64     `json:"task_id"`
 	EntryDate time.Time `json:"entry_date"`
 	Completed bool      `json:"completed"`
+	Score     float64   `json:"score"`
+}
+
+// RequirementRevision is the requirement tree of a task as it was defined from EffectiveFrom
+// until the next revision.
+type RequirementRevision struct {
+	ID            int64     `json:"id"`
+	TaskID        int64     `json:"task_id"`
+	EffectiveFrom time.Time `json:"effective_from"`
+	// Tree is the requirement tree encoded as JSON
+	Tree      string    `json:"tree"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 type Requirement struct {
-	ID          int64   `json:"id"`
-	TaskID      int64   `json:"task_id"`
-	ParentID    *int64  `json:"parent_id"`
-	Title       string  `json:"title"`
-	Type        string  `json:"type"`
-	DataType    *string `json:"data_type"`
-	Operator    *string `json:"operator"`
-	TargetValue *string `json:"target_value"`
-	Value       *string `json:"value"`
-	SortOrder   int     `json:"sort_order"`
+	ID          int64    `json:"id"`
+	TaskID      int64    `json:"task_id"`
+	ParentID    *int64   `json:"parent_id"`
+	Title       string   `json:"title"`
+	Type        string   `json:"type"`
+	DataType    *string  `json:"data_type"`
+	Operator    *string  `json:"operator"`
+	TargetValue *string  `json:"target_value"`
+	Options     []string `json:"options"`
+	Unit        *string  `json:"unit"`
+	Aggregation *string  `json:"aggregation"`
+	Period      *string  `json:"period"`
+	PeriodDays  *int     `json:"period_days"`
+	ScoreMode   *string  `json:"score_mode"`
+	Value       *string  `json:"value"`
+	SortOrder   int      `json:"sort_order"`
+	// Retired requirements were removed from the current tree but are kept for earlier revisions
+	Retired bool `json:"retired"`
 }
 
 type RequirementEntry struct {
@@ -42,4 +75,20 @@ type RequirementEntry struct {
 	RequirementID int64     `json:"requirement_id"`
 	EntryDate     time.Time `json:"entry_date"`
 	Value         string    `json:"value"`
+	// Unit the value was entered in, nil when the requirement has no unit
+	Unit *string `json:"unit"`
+	Note *string `json:"note"`
+}
+
+// SearchHit is a task, requirement or entry note that matched a search
+type SearchHit struct {
+	Kind          string // task, requirement or entry
+	TaskID        int64
+	TaskTitle     string
+	RequirementID *int64
+	EntryID       *int64
+	EntryDate     sql.NullTime
+	Snippet       string
+	// Rank orders the hits, the lower the better
+	Rank float64
 }
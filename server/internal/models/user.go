@@ -9,4 +9,7 @@ type User struct {
 	PasswordHash string    `json:"passwordHash,omitempty"` // TODO: Hash password later
 	CreatedAt    time.Time `json:"createdAt"`
 	UpdatedAt    time.Time `json:"updatedAt"`
+	WeekStart    int       `json:"weekStart"`  // 0 is Sunday
+	Timezone     string    `json:"timezone"`   // IANA name, "UTC" by default
+	DayEndHour   int       `json:"dayEndHour"` // Entries logged before this hour of the night count for the day before
 }
@@ -18,6 +18,8 @@ func SetupAPIRoutes(
 	authHandler *handlers.AuthHandler,
 	profileHandler *handlers.ProfileHandler,
 	taskHandler *handlers.TaskHandler,
+	entriesHandler *handlers.EntriesHandler,
+	tagHandler *handlers.TagHandler,
 ) {
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	api := router.Group("/api")
@@ -32,18 +34,48 @@ func SetupAPIRoutes(
 		protected.Use(middleware.Auth(userRepo))
 		{
 			protected.GET("/profile", profileHandler.GetProfile)
+			protected.PUT("/profile/settings", profileHandler.UpdateSettings)
 
 			protected.GET("/tasks", taskHandler.GetAllTasks)
-			protected.GET("/tasks/:task_id", taskHandler.GetTaskByID) // Get other info of the task like description
-			protected.PUT("/tasks/:task_id")                          // Update task
-			protected.POST("/tasks", taskHandler.CreateTask)          // Create a task
+			protected.GET("/tasks/:task_id", taskHandler.GetTaskByID)                // Get other info of the task like description
+			protected.GET("/tasks/:task_id/status", taskHandler.GetTaskStatus)       // Evaluate the task for a day, GET /tasks/1/status?date=2024-01-31
+			protected.GET("/tasks/:task_id/days", taskHandler.GetTaskDays)           // Completion and score by day, GET /tasks/1/days?start=2024-01-01&end=2024-01-31
+			protected.GET("/tasks/:task_id/revisions", taskHandler.GetTaskRevisions) // Requirement tree versions and the days they apply to
+			protected.GET("/tasks/:task_id/history", taskHandler.GetTaskHistory)     // State of every day, GET /tasks/1/history?start=2024-01-01&end=2024-12-31
+			protected.PUT("/tasks/:task_id", taskHandler.UpdateTask)                 // Update task
+			protected.DELETE("/tasks/:task_id", taskHandler.DeleteTask)              // Move a task to the trash
+			protected.POST("/tasks/:task_id/archive", taskHandler.ArchiveTask)       // Hide from the default list
+			protected.POST("/tasks/:task_id/unarchive", taskHandler.UnarchiveTask)   // Make an archived task active
+			protected.POST("/tasks/:task_id/complete", taskHandler.CompleteTask)     // Mark as done for good
+			protected.POST("/tasks", taskHandler.CreateTask)                         // Create a task
+
+			protected.GET("/trash", taskHandler.GetTrash)                      // Deleted tasks, purged after 30 days
+			protected.POST("/trash/:task_id/restore", taskHandler.RestoreTask) // Take a task out of the trash
+			protected.DELETE("/trash/:task_id", taskHandler.PurgeTask)         // Delete a task for good
+
+			protected.GET("/today", taskHandler.GetAgenda)  // Tasks due today with the status of every requirement
+			protected.GET("/agenda", taskHandler.GetAgenda) // Same for another day, GET /agenda?date=2024-01-31
+
+			protected.GET("/history", taskHandler.GetHistory) // All tasks by day for heatmaps, GET /history?start=2024-01-01&end=2024-12-31
+
+			protected.GET("/search", taskHandler.Search) // Find tasks, requirements and entry notes, GET /search?q=morning+run
+
+			protected.GET("/tags", tagHandler.GetTags)              // Tags of the user, used to filter GET /tasks?tags=1,2
+			protected.POST("/tags", tagHandler.CreateTag)           // Create a tag
+			protected.PUT("/tags/:tag_id", tagHandler.UpdateTag)    // Rename or recolor a tag
+			protected.DELETE("/tags/:tag_id", tagHandler.DeleteTag) // Delete a tag and remove it from tasks
+
+			protected.POST("/expressions/parse", handlers.ParseExpression)   // Text expression to requirement tree
+			protected.POST("/expressions/format", handlers.FormatExpression) // Requirement tree to text expression
 
 			// protected.GET("/requirements/entries", taskHandler.GetRequirements) // GET /requirements/entries?start=2024-01-01T00:00:00&end=2024-01-31T23:59:59
-			protected.POST("/requirements/:requirement_id/entries") // Create an entry for any requirement
-			protected.GET("/requirements/:requirement_id/entries")  // Get all entries, make it with date start and end
-			protected.GET("/entries/:entry_id")                     // Get specific entry
-			protected.PUT("/entries/:entry_id")                     // Update entry
-			protected.DELETE("/entries/:entry_id")                  //
+			protected.POST("/requirements/:requirement_id/entries", entriesHandler.AddRequirementEntry)  // Create an entry for any requirement
+			protected.GET("/requirements/:requirement_id/entries", entriesHandler.GetRequirementEntries) // Get entries, GET /requirements/1/entries?start=2024-01-01&end=2024-01-31
+			protected.GET("/requirements/:requirement_id/trend", entriesHandler.GetRequirementTrend)     // Values by day with moving averages and personal bests, GET /requirements/1/trend?start=2024-01-01&window=7
+			protected.GET("/entries/:entry_id", entriesHandler.GetEntryByID)                             // Get specific entry
+			protected.PUT("/entries/:entry_id", entriesHandler.UpdateEntry)                              // Update entry
+			protected.DELETE("/entries/:entry_id", entriesHandler.DeleteEntry)                           // Delete entry
+			protected.POST("/checkin", entriesHandler.CheckIn)                                           // Log values of many requirements for one day
 		}
 
 	}
@@ -0,0 +1,316 @@
+// Package schedule decides on which days a task is due.
+//
+// Schedules are written as a subset of RFC 5545 recurrence rules:
+//
+//	FREQ=DAILY;INTERVAL=2                every other day
+//	FREQ=WEEKLY;BYDAY=MO,WE,FR           specific weekdays
+//	FREQ=MONTHLY;BYMONTHDAY=1,-1         first and last day of the month
+//	FREQ=WEEKLY;X-TIMES=3                any three days a week
+//
+// X-TIMES is not a part of the RFC, it turns the period into a quota: every day of
+// the period is allowed and the period is met when the task is done on that many days.
+// Periods are counted from the anchor, which is the start date of the task.
+//
+// WKST is not accepted, weeks start on the WeekStart of the schedule, which is
+// set from the settings of the user so weekly quotas and weekly requirements agree.
+package schedule
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+)
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+var weekdayNames = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+type Schedule struct {
+	Freq       Frequency
+	Interval   int
+	ByDay      []time.Weekday
+	ByMonthDay []int
+	// WeekStart is the first day of weekly periods, it is not a part of the rule
+	WeekStart time.Weekday
+	// Times is the number of days per period the task has to be done, 0 if the days are fixed
+	Times int
+}
+
+// Default is the schedule of tasks without a recurrence rule.
+var Default = &Schedule{Freq: Daily, Interval: 1, WeekStart: time.Monday}
+
+// Parse reads a recurrence rule, the "RRULE:" prefix is optional.
+func Parse(rule string) (*Schedule, error) {
+
+	rule = strings.TrimSpace(rule)
+	rule = strings.TrimPrefix(strings.ToUpper(rule), "RRULE:")
+	if rule == "" {
+		return nil, fmt.Errorf("rule is empty")
+	}
+
+	s := &Schedule{Interval: 1, WeekStart: time.Monday}
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(rule, ";") {
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found || value == "" {
+			return nil, fmt.Errorf("'%s' is not a KEY=VALUE pair", part)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("%s is set more than once", key)
+		}
+		seen[key] = true
+
+		switch key {
+		case "FREQ":
+			switch Frequency(value) {
+			case Daily, Weekly, Monthly:
+				s.Freq = Frequency(value)
+			default:
+				return nil, fmt.Errorf("FREQ must be one of: DAILY, WEEKLY, MONTHLY")
+			}
+
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("INTERVAL must be a positive integer")
+			}
+			s.Interval = n
+
+		case "BYDAY":
+			for _, name := range strings.Split(value, ",") {
+				day, ok := weekdays[name]
+				if !ok {
+					return nil, fmt.Errorf("'%s' is not a weekday, use two letter names like MO, only plain weekdays are supported", name)
+				}
+				if !slices.Contains(s.ByDay, day) {
+					s.ByDay = append(s.ByDay, day)
+				}
+			}
+
+		case "BYMONTHDAY":
+			for _, text := range strings.Split(value, ",") {
+				n, err := strconv.Atoi(text)
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return nil, fmt.Errorf("'%s' is not a day of the month, use 1 to 31 or -1 to -31", text)
+				}
+				if !slices.Contains(s.ByMonthDay, n) {
+					s.ByMonthDay = append(s.ByMonthDay, n)
+				}
+			}
+
+		case "WKST":
+			return nil, fmt.Errorf("WKST is not supported, weeks start on the first day of the week in the user's settings")
+
+		case "X-TIMES":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("X-TIMES must be a positive integer")
+			}
+			s.Times = n
+
+		default:
+			return nil, fmt.Errorf("%s is not supported", key)
+		}
+	}
+
+	if s.Freq == "" {
+		return nil, fmt.Errorf("FREQ is required")
+	}
+	if len(s.ByDay) > 0 && s.Freq != Weekly {
+		return nil, fmt.Errorf("BYDAY can only be used with FREQ=WEEKLY")
+	}
+	if len(s.ByMonthDay) > 0 && s.Freq != Monthly {
+		return nil, fmt.Errorf("BYMONTHDAY can only be used with FREQ=MONTHLY")
+	}
+
+	if s.Times > 0 {
+		if s.Freq == Daily {
+			return nil, fmt.Errorf("X-TIMES can only be used with FREQ=WEEKLY or FREQ=MONTHLY")
+		}
+		if len(s.ByDay) > 0 || len(s.ByMonthDay) > 0 {
+			return nil, fmt.Errorf("X-TIMES cannot be combined with BYDAY or BYMONTHDAY")
+		}
+
+		// Every period has to be able to meet the quota, the shortest month has 28 days
+		limit := 7 * s.Interval
+		if s.Freq == Monthly {
+			limit = 28 * s.Interval
+		}
+		if s.Times > limit {
+			return nil, fmt.Errorf("X-TIMES cannot be greater than %d", limit)
+		}
+	}
+
+	slices.Sort(s.ByDay)
+	slices.Sort(s.ByMonthDay)
+
+	return s, nil
+}
+
+// String returns the rule in its canonical form.
+func (s *Schedule) String() string {
+
+	parts := []string{"FREQ=" + string(s.Freq)}
+
+	if s.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(s.Interval))
+	}
+
+	if len(s.ByDay) > 0 {
+		var names []string
+		for _, day := range s.ByDay {
+			names = append(names, weekdayNames[day])
+		}
+		parts = append(parts, "BYDAY="+strings.Join(names, ","))
+	}
+
+	if len(s.ByMonthDay) > 0 {
+		var days []string
+		for _, day := range s.ByMonthDay {
+			days = append(days, strconv.Itoa(day))
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+
+	if s.Times > 0 {
+		parts = append(parts, "X-TIMES="+strconv.Itoa(s.Times))
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// WithWeekStart returns a copy of the schedule whose weeks start on day.
+func (s *Schedule) WithWeekStart(day time.Weekday) *Schedule {
+	copied := *s
+	copied.WeekStart = day
+	return &copied
+}
+
+// IsQuota reports whether the task may be done on any day of the period as long as it is done Times times.
+func (s *Schedule) IsQuota() bool {
+	return s.Times > 0
+}
+
+// IsDue reports whether the task has to be done on day. Both anchor and day are
+// expected to be midnights in the same location.
+func (s *Schedule) IsDue(anchor time.Time, day time.Time) bool {
+
+	if day.Before(anchor) {
+		return false
+	}
+
+	// Any day of a quota period counts towards the quota
+	if s.IsQuota() {
+		return true
+	}
+
+	if s.unitIndex(anchor, day)%s.Interval != 0 {
+		return false
+	}
+
+	switch s.Freq {
+	case Weekly:
+		if len(s.ByDay) == 0 {
+			return day.Weekday() == anchor.Weekday()
+		}
+		return slices.Contains(s.ByDay, day.Weekday())
+
+	case Monthly:
+		if len(s.ByMonthDay) == 0 {
+			// Months without that day are skipped like RFC 5545 does
+			return day.Day() == anchor.Day()
+		}
+		last := daysInMonth(day)
+		for _, n := range s.ByMonthDay {
+			if n == day.Day() || (n < 0 && last+n+1 == day.Day()) {
+				return true
+			}
+		}
+		return false
+
+	default:
+		return true
+	}
+}
+
+// Period returns the half-open interval [start, end) of the period containing day.
+// A period spans Interval units, so "FREQ=WEEKLY;INTERVAL=2" has two week periods.
+func (s *Schedule) Period(anchor time.Time, day time.Time) (time.Time, time.Time) {
+
+	index := s.unitIndex(anchor, day)
+
+	// Floor division, days before the anchor belong to earlier periods
+	periodIndex := index / s.Interval
+	if index < 0 && index%s.Interval != 0 {
+		periodIndex--
+	}
+
+	start := s.addUnits(s.unitStart(anchor), periodIndex*s.Interval)
+	return start, s.addUnits(start, s.Interval)
+}
+
+// unitStart returns the first day of the day, week or month containing day.
+func (s *Schedule) unitStart(day time.Time) time.Time {
+	switch s.Freq {
+	case Weekly:
+		offset := (int(day.Weekday()) - int(s.WeekStart) + 7) % 7
+		return day.AddDate(0, 0, -offset)
+	case Monthly:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+	default:
+		return day
+	}
+}
+
+func (s *Schedule) addUnits(start time.Time, n int) time.Time {
+	switch s.Freq {
+	case Weekly:
+		return start.AddDate(0, 0, 7*n)
+	case Monthly:
+		return start.AddDate(0, n, 0)
+	default:
+		return start.AddDate(0, 0, n)
+	}
+}
+
+// unitIndex returns the number of days, weeks or months between the units of anchor and day.
+func (s *Schedule) unitIndex(anchor time.Time, day time.Time) int {
+	switch s.Freq {
+	case Monthly:
+		return (day.Year()-anchor.Year())*12 + int(day.Month()) - int(anchor.Month())
+	case Weekly:
+		return daysBetween(s.unitStart(anchor), s.unitStart(day)) / 7
+	default:
+		return daysBetween(anchor, day)
+	}
+}
+
+// daysBetween counts calendar days, so it is not affected by DST changes.
+func daysBetween(from time.Time, to time.Time) int {
+	a := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	b := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(b.Sub(a).Hours() / 24)
+}
+
+func daysInMonth(day time.Time) int {
+	return time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
@@ -0,0 +1,258 @@
+package schedule
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestParseCanonical(t *testing.T) {
+	tests := []struct {
+		rule string
+		want string
+	}{
+		{"FREQ=DAILY", "FREQ=DAILY"},
+		{"RRULE:FREQ=DAILY;INTERVAL=1", "FREQ=DAILY"},
+		{"rrule:freq=weekly;byday=fr,mo,mo", "FREQ=WEEKLY;BYDAY=MO,FR"},
+		{" FREQ=WEEKLY ; BYDAY=SU,SA ", "FREQ=WEEKLY;BYDAY=SU,SA"},
+		{"FREQ=MONTHLY;BYMONTHDAY=1,-1", "FREQ=MONTHLY;BYMONTHDAY=-1,1"},
+		{"FREQ=WEEKLY;X-TIMES=3;INTERVAL=2", "FREQ=WEEKLY;INTERVAL=2;X-TIMES=3"},
+		{"FREQ=WEEKLY;INTERVAL=2;X-TIMES=14", "FREQ=WEEKLY;INTERVAL=2;X-TIMES=14"},
+		{"FREQ=MONTHLY;X-TIMES=28", "FREQ=MONTHLY;X-TIMES=28"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			s, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %v", tt.rule, err)
+			}
+			if got := s.String(); got != tt.want {
+				t.Errorf("Parse(%q).String() = %q, want %q", tt.rule, got, tt.want)
+			}
+			if s.WeekStart != time.Monday {
+				t.Errorf("WeekStart = %v, want Monday until the user's setting is applied", s.WeekStart)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"RRULE:",
+		"FREQ",
+		"INTERVAL=2",
+		"FREQ=YEARLY",
+		"FREQ=DAILY;FREQ=DAILY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;INTERVAL=x",
+		"FREQ=DAILY;COUNT=3",
+		"FREQ=DAILY;BYDAY=MO",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=MONTHLY;BYMONTHDAY=0",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=WEEKLY;WKST=SU",
+		"FREQ=DAILY;X-TIMES=2",
+		"FREQ=WEEKLY;X-TIMES=0",
+		"FREQ=WEEKLY;X-TIMES=8",
+		"FREQ=WEEKLY;INTERVAL=2;X-TIMES=15",
+		"FREQ=MONTHLY;X-TIMES=29",
+		"FREQ=WEEKLY;BYDAY=MO;X-TIMES=2",
+		"FREQ=MONTHLY;BYMONTHDAY=1;X-TIMES=2",
+	}
+
+	for _, rule := range tests {
+		t.Run(rule, func(t *testing.T) {
+			if s, err := Parse(rule); err == nil {
+				t.Errorf("Parse(%q) = %q, want an error", rule, s)
+			}
+		})
+	}
+}
+
+// dueDays expands the schedule over the days of [from, to].
+func dueDays(s *Schedule, anchor time.Time, from time.Time, to time.Time) []string {
+	var days []string
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if s.IsDue(anchor, day) {
+			days = append(days, day.Format("2006-01-02"))
+		}
+	}
+	return days
+}
+
+func TestIsDue(t *testing.T) {
+	tests := []struct {
+		name string
+		rule string
+		// weekStart only matters to weekly rules with an interval, Sunday is the zero value
+		weekStart time.Weekday
+		anchor    string
+		from, to  string
+		want      []string
+	}{
+		{
+			name: "every day", rule: "FREQ=DAILY",
+			anchor: "2025-01-01", from: "2024-12-30", to: "2025-01-03",
+			want: []string{"2025-01-01", "2025-01-02", "2025-01-03"},
+		},
+		{
+			name: "every other day", rule: "FREQ=DAILY;INTERVAL=2",
+			anchor: "2025-01-01", from: "2025-01-01", to: "2025-01-07",
+			want: []string{"2025-01-01", "2025-01-03", "2025-01-05", "2025-01-07"},
+		},
+		{
+			name: "weekly on the weekday of the anchor", rule: "FREQ=WEEKLY",
+			anchor: "2025-01-01", from: "2025-01-01", to: "2025-01-21",
+			want: []string{"2025-01-01", "2025-01-08", "2025-01-15"},
+		},
+		{
+			name: "weekdays", rule: "FREQ=WEEKLY;BYDAY=MO,FR",
+			anchor: "2025-01-01", from: "2025-01-01", to: "2025-01-14",
+			want: []string{"2025-01-03", "2025-01-06", "2025-01-10", "2025-01-13"},
+		},
+		{
+			name: "every other week, weeks start on Monday", rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=SU", weekStart: time.Monday,
+			anchor: "2025-01-01", from: "2025-01-01", to: "2025-01-31",
+			want: []string{"2025-01-05", "2025-01-19"},
+		},
+		{
+			name: "every other week, weeks start on Sunday", rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=SU", weekStart: time.Sunday,
+			anchor: "2025-01-01", from: "2025-01-01", to: "2025-01-31",
+			want: []string{"2025-01-12", "2025-01-26"},
+		},
+		{
+			name: "monthly on a day some months do not have", rule: "FREQ=MONTHLY",
+			anchor: "2025-01-31", from: "2025-01-01", to: "2025-05-31",
+			want: []string{"2025-01-31", "2025-03-31", "2025-05-31"},
+		},
+		{
+			name: "first and last day of the month", rule: "FREQ=MONTHLY;BYMONTHDAY=1,-1",
+			anchor: "2025-01-01", from: "2025-01-01", to: "2025-03-01",
+			want: []string{"2025-01-01", "2025-01-31", "2025-02-01", "2025-02-28", "2025-03-01"},
+		},
+		{
+			name: "last day of February in a leap year", rule: "FREQ=MONTHLY;BYMONTHDAY=-1",
+			anchor: "2024-01-15", from: "2024-02-01", to: "2024-03-01",
+			want: []string{"2024-02-29"},
+		},
+		{
+			name: "every other month", rule: "FREQ=MONTHLY;INTERVAL=2;BYMONTHDAY=15",
+			anchor: "2025-01-20", from: "2025-01-01", to: "2025-06-30",
+			want: []string{"2025-03-15", "2025-05-15"},
+		},
+		{
+			name: "any day of a quota period", rule: "FREQ=WEEKLY;X-TIMES=3",
+			anchor: "2025-01-03", from: "2025-01-01", to: "2025-01-06",
+			want: []string{"2025-01-03", "2025-01-04", "2025-01-05", "2025-01-06"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %v", tt.rule, err)
+			}
+			s = s.WithWeekStart(tt.weekStart)
+
+			got := dueDays(s, date(tt.anchor), date(tt.from), date(tt.to))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("due days = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPeriod(t *testing.T) {
+	tests := []struct {
+		name      string
+		rule      string
+		weekStart time.Weekday
+		anchor    string
+		day       string
+		start     string
+		end       string
+	}{
+		{
+			name: "week starting on Monday", rule: "FREQ=WEEKLY;X-TIMES=3", weekStart: time.Monday,
+			anchor: "2025-01-01", day: "2025-01-05", start: "2024-12-30", end: "2025-01-06",
+		},
+		{
+			name: "week starting on Sunday", rule: "FREQ=WEEKLY;X-TIMES=3", weekStart: time.Sunday,
+			anchor: "2025-01-01", day: "2025-01-05", start: "2025-01-05", end: "2025-01-12",
+		},
+		{
+			name: "Saturday in a week starting on Sunday", rule: "FREQ=WEEKLY;X-TIMES=3", weekStart: time.Sunday,
+			anchor: "2025-01-01", day: "2025-01-04", start: "2024-12-29", end: "2025-01-05",
+		},
+		{
+			name: "week starting on Saturday", rule: "FREQ=WEEKLY;X-TIMES=2", weekStart: time.Saturday,
+			anchor: "2025-01-01", day: "2025-01-03", start: "2024-12-28", end: "2025-01-04",
+		},
+		{
+			name: "two week period", rule: "FREQ=WEEKLY;INTERVAL=2;X-TIMES=3", weekStart: time.Monday,
+			anchor: "2025-01-01", day: "2025-01-10", start: "2024-12-30", end: "2025-01-13",
+		},
+		{
+			name: "second two week period", rule: "FREQ=WEEKLY;INTERVAL=2;X-TIMES=3", weekStart: time.Monday,
+			anchor: "2025-01-01", day: "2025-01-13", start: "2025-01-13", end: "2025-01-27",
+		},
+		{
+			name: "two week period before the anchor", rule: "FREQ=WEEKLY;INTERVAL=2;X-TIMES=3", weekStart: time.Monday,
+			anchor: "2025-01-01", day: "2024-12-25", start: "2024-12-16", end: "2024-12-30",
+		},
+		{
+			name: "month", rule: "FREQ=MONTHLY;X-TIMES=10", weekStart: time.Monday,
+			anchor: "2025-01-20", day: "2025-02-14", start: "2025-02-01", end: "2025-03-01",
+		},
+		{
+			name: "quarter counted from the anchor", rule: "FREQ=MONTHLY;INTERVAL=3;X-TIMES=10", weekStart: time.Monday,
+			anchor: "2025-01-20", day: "2025-05-02", start: "2025-04-01", end: "2025-07-01",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %v", tt.rule, err)
+			}
+			s = s.WithWeekStart(tt.weekStart)
+
+			start, end := s.Period(date(tt.anchor), date(tt.day))
+			if !start.Equal(date(tt.start)) || !end.Equal(date(tt.end)) {
+				t.Errorf("Period(%s) = [%s, %s), want [%s, %s)", tt.day,
+					start.Format("2006-01-02"), end.Format("2006-01-02"), tt.start, tt.end)
+			}
+		})
+	}
+}
+
+func TestWithWeekStart(t *testing.T) {
+	s, err := Parse("FREQ=WEEKLY;X-TIMES=3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sunday := s.WithWeekStart(time.Sunday)
+	if sunday.WeekStart != time.Sunday {
+		t.Errorf("WeekStart = %v, want Sunday", sunday.WeekStart)
+	}
+	if s.WeekStart != time.Monday {
+		t.Errorf("WithWeekStart changed the original schedule to %v", s.WeekStart)
+	}
+	if Default.WithWeekStart(time.Sunday); Default.WeekStart != time.Monday {
+		t.Errorf("WithWeekStart changed the default schedule to %v", Default.WeekStart)
+	}
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"time"
+
+	"github.com/boreymarf/task-fuss/server/internal/db"
+	"github.com/boreymarf/task-fuss/server/internal/dto"
+)
+
+// AgendaItem is a task due on the day of the agenda with its requirement tree in effect that day
+// and the outcome of the entries logged so far.
+type AgendaItem struct {
+	Task       dto.Task
+	Evaluation *TaskEvaluation
+}
+
+// GetAgenda evaluates every active task due on the day of date, today of the user when it is nil, ordered by task ID.
+// Tasks outside of their start and end dates or not scheduled for the day are left out. Returns the day of the agenda.
+func (s *TaskService) GetAgenda(userID int64, date *time.Time) (time.Time, []AgendaItem, error) {
+
+	calendar, err := s.calendarFor(userID)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+
+	day := calendar.today()
+	if date != nil {
+		day, _ = dayBounds(*date)
+	}
+
+	modelTasks, err := s.taskRepo.GetAllTasks(&db.GetAllTasksOptions{
+		DetailLevel: "full",
+		ShowActive:  true,
+		UserID:      userID,
+	})
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+
+	var contexts []*taskContext
+	var taskIDs []int64
+	for i := range modelTasks {
+		ts, err := newTaskSchedule(&modelTasks[i], calendar)
+		if err != nil {
+			return time.Time{}, nil, err
+		}
+		if !ts.isDue(day) {
+			continue
+		}
+
+		tc, err := s.loadTaskContext(modelTasks[i].ID)
+		if err != nil {
+			return time.Time{}, nil, err
+		}
+		contexts = append(contexts, tc)
+		taskIDs = append(taskIDs, modelTasks[i].ID)
+	}
+
+	tagsByTask, err := s.tagRepo.GetTagsByTaskIDs(taskIDs)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+
+	items := make([]AgendaItem, 0, len(contexts))
+	for _, tc := range contexts {
+		evaluation, err := s.evaluateTaskDay(tc, day)
+		if err != nil {
+			return time.Time{}, nil, err
+		}
+
+		items = append(items, AgendaItem{
+			Task: dto.Task{
+				ID:          tc.task.ID,
+				Title:       tc.task.Title,
+				Description: tc.task.Description,
+				Status:      tc.task.Status,
+				Recurrence:  tc.task.Recurrence,
+				Requirement: tc.rootOn(day),
+				Tags:        toDtoTags(tagsByTask[tc.task.ID]),
+			},
+			Evaluation: evaluation,
+		})
+	}
+
+	return day, items, nil
+}
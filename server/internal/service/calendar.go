@@ -0,0 +1,164 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/boreymarf/task-fuss/server/internal/models"
+)
+
+// Calendar splits time into the days, weeks and months of a user.
+// A day is identified by midnight UTC of its date whatever the time zone of the user is,
+// moments like entry dates are put into days with dayOf.
+type Calendar struct {
+	WeekStart time.Weekday
+	// Location is the time zone of the user, nil is UTC
+	Location *time.Location
+	// DayEndHour is the hour of the night the day ends at, moments before it belong to the day before
+	DayEndHour int
+}
+
+// calendarFor returns the calendar of the user.
+func (s *TaskService) calendarFor(userID int64) (Calendar, error) {
+
+	var user models.User
+	if err := s.userRepo.GetUserByID(userID, &user); err != nil {
+		return Calendar{}, err
+	}
+
+	return userCalendar(&user)
+}
+
+func userCalendar(user *models.User) (Calendar, error) {
+
+	location, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		return Calendar{}, fmt.Errorf("user %d has invalid timezone '%s': %w", user.ID, user.Timezone, err)
+	}
+
+	return Calendar{
+		WeekStart:  time.Weekday(user.WeekStart),
+		Location:   location,
+		DayEndHour: user.DayEndHour,
+	}, nil
+}
+
+func (c Calendar) location() *time.Location {
+	if c.Location == nil {
+		return time.UTC
+	}
+	return c.Location
+}
+
+// dayOf returns the day the moment belongs to.
+func (c Calendar) dayOf(moment time.Time) time.Time {
+
+	local := moment.In(c.location())
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+
+	// The wall clock is compared, so days keep ending at the same hour when clocks change
+	if local.Hour() < c.DayEndHour {
+		day = day.AddDate(0, 0, -1)
+	}
+
+	return day
+}
+
+// dayStart returns the first moment of the day, days are 23 or 25 hours long when clocks change.
+func (c Calendar) dayStart(day time.Time) time.Time {
+
+	start := time.Date(day.Year(), day.Month(), day.Day(), c.DayEndHour, 0, 0, 0, c.location())
+
+	zoneStart, zoneEnd := start.ZoneBounds()
+	if c.dayOf(start).Before(day) && !zoneEnd.IsZero() {
+		// The hour was skipped when clocks were put forward, the day starts right after the gap
+		start = zoneEnd
+	} else if !zoneStart.IsZero() {
+		// The hour happened twice when clocks were put back, the day starts with the first one
+		_, before := zoneStart.Add(-time.Second).Zone()
+		_, current := start.Zone()
+		if earlier := start.Add(-time.Duration(before-current) * time.Second); before > current && earlier.Before(zoneStart) {
+			start = earlier
+		}
+	}
+
+	return start.UTC()
+}
+
+// dayMoments returns the half-open interval [start, end) of the moments that belong to the day.
+func (c Calendar) dayMoments(day time.Time) (time.Time, time.Time) {
+	return c.dayStart(day), c.dayStart(day.AddDate(0, 0, 1))
+}
+
+// Range is a half-open interval [From, To) taken from a query. Bounds written as dates are days of the
+// user's calendar and bounds written as timestamps are moments, either is converted once the calendar is known.
+type Range struct {
+	From time.Time
+	To   time.Time
+	// FromDate and ToDate tell that the bound is a day, To is then the day after the last day of the range
+	FromDate bool
+	ToDate   bool
+}
+
+// moments returns the range as moments, dates start at the end hour of the day in the user's time zone.
+// A zero From is left open.
+func (c Calendar) moments(r Range) (time.Time, time.Time) {
+
+	from, to := r.From, r.To
+	if r.FromDate {
+		from = c.dayStart(from)
+	}
+	if r.ToDate {
+		to = c.dayStart(to)
+	}
+
+	return from, to
+}
+
+// days returns the range as days, a moment in the middle of a day includes the whole day.
+// A zero From is left open.
+func (c Calendar) days(r Range) (time.Time, time.Time) {
+
+	from, to := r.From, r.To
+	if !r.FromDate && !from.IsZero() {
+		from = c.dayOf(from)
+	}
+	if !r.ToDate {
+		day := c.dayOf(to)
+		if !c.dayStart(day).Equal(to) {
+			day = day.AddDate(0, 0, 1)
+		}
+		to = day
+	}
+
+	return from, to
+}
+
+// today returns the current day of the user.
+func (c Calendar) today() time.Time {
+	return c.dayOf(time.Now())
+}
+
+// periodBounds returns the half-open interval [start, end) of the days of the requirement period containing date.
+// Rolling periods end with the day of date and span periodDays days.
+func (c Calendar) periodBounds(period string, periodDays int, date time.Time) (time.Time, time.Time) {
+
+	day, next := dayBounds(date)
+
+	switch period {
+	case "week":
+		offset := (int(day.Weekday()) - int(c.WeekStart) + 7) % 7
+		start := day.AddDate(0, 0, -offset)
+		return start, start.AddDate(0, 0, 7)
+
+	case "month":
+		start := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+		return start, start.AddDate(0, 1, 0)
+
+	case "rolling":
+		return day.AddDate(0, 0, -(max(periodDays, 1) - 1)), next
+
+	default:
+		return day, next
+	}
+}
@@ -0,0 +1,237 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func mustLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("failed to load %s: %v", name, err)
+	}
+	return location
+}
+
+func day(s string) time.Time {
+	d, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func moment(s string) time.Time {
+	m, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func TestDayMoments(t *testing.T) {
+	tests := []struct {
+		name       string
+		zone       string
+		dayEndHour int
+		day        string
+		start      string
+		end        string
+		length     time.Duration
+	}{
+		{
+			name: "UTC", zone: "UTC",
+			day: "2025-03-09", start: "2025-03-09T00:00:00Z", end: "2025-03-10T00:00:00Z", length: 24 * time.Hour,
+		},
+		{
+			name: "spring forward", zone: "America/New_York",
+			day: "2025-03-09", start: "2025-03-09T05:00:00Z", end: "2025-03-10T04:00:00Z", length: 23 * time.Hour,
+		},
+		{
+			name: "fall back", zone: "America/New_York",
+			day: "2025-11-02", start: "2025-11-02T04:00:00Z", end: "2025-11-03T05:00:00Z", length: 25 * time.Hour,
+		},
+		{
+			// 02:00 does not exist on the day, it starts when clocks reach 03:00
+			name: "spring forward skips the end hour", zone: "America/New_York", dayEndHour: 2,
+			day: "2025-03-09", start: "2025-03-09T07:00:00Z", end: "2025-03-10T06:00:00Z", length: 23 * time.Hour,
+		},
+		{
+			name: "day before spring forward with an end hour", zone: "America/New_York", dayEndHour: 2,
+			day: "2025-03-08", start: "2025-03-08T07:00:00Z", end: "2025-03-09T07:00:00Z", length: 24 * time.Hour,
+		},
+		{
+			// 01:00 happens twice, the day starts with the first one
+			name: "fall back repeats the end hour", zone: "America/New_York", dayEndHour: 1,
+			day: "2025-11-02", start: "2025-11-02T05:00:00Z", end: "2025-11-03T06:00:00Z", length: 25 * time.Hour,
+		},
+		{
+			name: "Auckland", zone: "Pacific/Auckland", dayEndHour: 4,
+			day: "2025-07-01", start: "2025-06-30T16:00:00Z", end: "2025-07-01T16:00:00Z", length: 24 * time.Hour,
+		},
+		{
+			// Clocks go from 02:00 to 03:00 in the night before 28 September, which belongs to the 27th
+			name: "Auckland spring forward", zone: "Pacific/Auckland", dayEndHour: 4,
+			day: "2025-09-27", start: "2025-09-26T16:00:00Z", end: "2025-09-27T15:00:00Z", length: 23 * time.Hour,
+		},
+		{
+			name: "Auckland spring forward skips the end hour", zone: "Pacific/Auckland", dayEndHour: 2,
+			day: "2025-09-28", start: "2025-09-27T14:00:00Z", end: "2025-09-28T13:00:00Z", length: 23 * time.Hour,
+		},
+		{
+			// Clocks go from 03:00 back to 02:00 on 5 April, so 02:00 happens twice
+			name: "Auckland fall back repeats the end hour", zone: "Pacific/Auckland", dayEndHour: 2,
+			day: "2026-04-05", start: "2026-04-04T13:00:00Z", end: "2026-04-05T14:00:00Z", length: 25 * time.Hour,
+		},
+		{
+			name: "Auckland day before fall back", zone: "Pacific/Auckland", dayEndHour: 4,
+			day: "2026-04-04", start: "2026-04-03T15:00:00Z", end: "2026-04-04T16:00:00Z", length: 25 * time.Hour,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Calendar{Location: mustLocation(t, tt.zone), DayEndHour: tt.dayEndHour}
+
+			start, end := c.dayMoments(day(tt.day))
+			if !start.Equal(moment(tt.start)) || !end.Equal(moment(tt.end)) {
+				t.Errorf("dayMoments(%s) = [%s, %s), want [%s, %s)", tt.day,
+					start.Format(time.RFC3339), end.Format(time.RFC3339), tt.start, tt.end)
+			}
+			if length := end.Sub(start); length != tt.length {
+				t.Errorf("day is %v long, want %v", length, tt.length)
+			}
+		})
+	}
+}
+
+func TestDayOf(t *testing.T) {
+	tests := []struct {
+		name       string
+		zone       string
+		dayEndHour int
+		moment     string
+		want       string
+	}{
+		{name: "UTC", zone: "UTC", moment: "2025-03-09T23:59:59Z", want: "2025-03-09"},
+		{name: "behind UTC", zone: "America/New_York", moment: "2025-03-10T03:59:59Z", want: "2025-03-09"},
+		{name: "ahead of UTC", zone: "Pacific/Auckland", moment: "2025-06-30T12:00:00Z", want: "2025-07-01"},
+		{name: "before the end hour", zone: "America/New_York", dayEndHour: 4, moment: "2025-03-10T07:59:59Z", want: "2025-03-09"},
+		{name: "at the end hour", zone: "America/New_York", dayEndHour: 4, moment: "2025-03-10T08:00:00Z", want: "2025-03-10"},
+		{name: "right after the spring forward gap", zone: "America/New_York", dayEndHour: 2, moment: "2025-03-09T07:00:00Z", want: "2025-03-09"},
+		{name: "right before the spring forward gap", zone: "America/New_York", dayEndHour: 2, moment: "2025-03-09T06:59:59Z", want: "2025-03-08"},
+		{name: "first of the repeated hours", zone: "America/New_York", dayEndHour: 1, moment: "2025-11-02T05:30:00Z", want: "2025-11-02"},
+		{name: "second of the repeated hours", zone: "America/New_York", dayEndHour: 1, moment: "2025-11-02T06:30:00Z", want: "2025-11-02"},
+		{name: "Auckland before the end hour", zone: "Pacific/Auckland", dayEndHour: 4, moment: "2025-09-27T14:59:59Z", want: "2025-09-27"},
+		{name: "Auckland at the end hour", zone: "Pacific/Auckland", dayEndHour: 4, moment: "2025-09-27T15:00:00Z", want: "2025-09-28"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Calendar{Location: mustLocation(t, tt.zone), DayEndHour: tt.dayEndHour}
+
+			if got := c.dayOf(moment(tt.moment)); !got.Equal(day(tt.want)) {
+				t.Errorf("dayOf(%s) = %s, want %s", tt.moment, got.Format("2006-01-02"), tt.want)
+			}
+		})
+	}
+}
+
+// Every moment of a day has to belong to it, also on the days clocks change.
+func TestDayStartBelongsToDay(t *testing.T) {
+	zones := []string{"UTC", "America/New_York", "Europe/London", "Pacific/Auckland", "Australia/Lord_Howe"}
+
+	for _, zone := range zones {
+		for dayEndHour := 0; dayEndHour <= 6; dayEndHour++ {
+			c := Calendar{Location: mustLocation(t, zone), DayEndHour: dayEndHour}
+
+			for d := day("2025-01-01"); d.Before(day("2026-12-31")); d = d.AddDate(0, 0, 1) {
+				start, end := c.dayMoments(d)
+				if got := c.dayOf(start); !got.Equal(d) {
+					t.Fatalf("%s, end hour %d: start of %s belongs to %s", zone, dayEndHour, d.Format("2006-01-02"), got.Format("2006-01-02"))
+				}
+				if got := c.dayOf(end.Add(-time.Second)); !got.Equal(d) {
+					t.Fatalf("%s, end hour %d: end of %s belongs to %s", zone, dayEndHour, d.Format("2006-01-02"), got.Format("2006-01-02"))
+				}
+				if length := end.Sub(start); length < 22*time.Hour || length > 26*time.Hour {
+					t.Fatalf("%s, end hour %d: %s is %v long", zone, dayEndHour, d.Format("2006-01-02"), length)
+				}
+			}
+		}
+	}
+}
+
+func TestRange(t *testing.T) {
+	c := Calendar{Location: mustLocation(t, "Pacific/Auckland"), DayEndHour: 4}
+
+	tests := []struct {
+		name     string
+		r        Range
+		from, to string // moments
+		fromDay  string
+		toDay    string
+	}{
+		{
+			name: "dates", r: Range{From: day("2025-09-27"), To: day("2025-09-29"), FromDate: true, ToDate: true},
+			from: "2025-09-26T16:00:00Z", to: "2025-09-28T15:00:00Z", fromDay: "2025-09-27", toDay: "2025-09-29",
+		},
+		{
+			name: "moments in the middle of days", r: Range{From: moment("2025-09-27T00:00:00Z"), To: moment("2025-09-28T00:00:00Z")},
+			from: "2025-09-27T00:00:00Z", to: "2025-09-28T00:00:00Z", fromDay: "2025-09-27", toDay: "2025-09-29",
+		},
+		{
+			name: "moment at the start of a day", r: Range{From: moment("2025-09-26T16:00:00Z"), To: moment("2025-09-27T15:00:00Z")},
+			from: "2025-09-26T16:00:00Z", to: "2025-09-27T15:00:00Z", fromDay: "2025-09-27", toDay: "2025-09-28",
+		},
+		{
+			name: "open start", r: Range{To: day("2025-09-28"), ToDate: true},
+			from: "0001-01-01T00:00:00Z", to: "2025-09-27T15:00:00Z", fromDay: "0001-01-01", toDay: "2025-09-28",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to := c.moments(tt.r)
+			if !from.Equal(moment(tt.from)) || !to.Equal(moment(tt.to)) {
+				t.Errorf("moments = [%s, %s), want [%s, %s)", from.Format(time.RFC3339), to.Format(time.RFC3339), tt.from, tt.to)
+			}
+
+			fromDay, toDay := c.days(tt.r)
+			if !fromDay.Equal(day(tt.fromDay)) || !toDay.Equal(day(tt.toDay)) {
+				t.Errorf("days = [%s, %s), want [%s, %s)", fromDay.Format("2006-01-02"), toDay.Format("2006-01-02"), tt.fromDay, tt.toDay)
+			}
+		})
+	}
+}
+
+func TestPeriodBounds(t *testing.T) {
+	tests := []struct {
+		name       string
+		weekStart  time.Weekday
+		period     string
+		periodDays int
+		date       string
+		start      string
+		end        string
+	}{
+		{name: "day", period: "day", date: "2025-01-05", start: "2025-01-05", end: "2025-01-06"},
+		{name: "week starting on Monday", weekStart: time.Monday, period: "week", date: "2025-01-05", start: "2024-12-30", end: "2025-01-06"},
+		{name: "week starting on Sunday", weekStart: time.Sunday, period: "week", date: "2025-01-05", start: "2025-01-05", end: "2025-01-12"},
+		{name: "week starting on Saturday", weekStart: time.Saturday, period: "week", date: "2025-01-03", start: "2024-12-28", end: "2025-01-04"},
+		{name: "month", period: "month", date: "2024-02-29", start: "2024-02-01", end: "2024-03-01"},
+		{name: "rolling", period: "rolling", periodDays: 7, date: "2025-01-05", start: "2024-12-30", end: "2025-01-06"},
+		{name: "rolling single day", period: "rolling", periodDays: 0, date: "2025-01-05", start: "2025-01-05", end: "2025-01-06"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Calendar{WeekStart: tt.weekStart}
+
+			start, end := c.periodBounds(tt.period, tt.periodDays, day(tt.date))
+			if !start.Equal(day(tt.start)) || !end.Equal(day(tt.end)) {
+				t.Errorf("periodBounds = [%s, %s), want [%s, %s)", start.Format("2006-01-02"), end.Format("2006-01-02"), tt.start, tt.end)
+			}
+		})
+	}
+}
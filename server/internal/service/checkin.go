@@ -0,0 +1,161 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/boreymarf/task-fuss/server/internal/apperrors"
+	"github.com/boreymarf/task-fuss/server/internal/config"
+	"github.com/boreymarf/task-fuss/server/internal/dto"
+	"github.com/boreymarf/task-fuss/server/internal/logger"
+	"github.com/boreymarf/task-fuss/server/internal/models"
+)
+
+// CheckIn sets the values of many requirements for one day in a single transaction.
+// Only the latest entry of the day counts for a requirement without aggregation, so the value replaces
+// the one of that entry and its note is kept. Values of sums, counts and extremes are added
+// to the entries of the day, as replacing them would lose the values they are made of.
+// Returns the re-evaluated status of every affected task ordered by task ID.
+func (s *TaskService) CheckIn(userID int64, req *dto.CheckInRequest) (time.Time, []*TaskEvaluation, error) {
+
+	calendar, err := s.calendarFor(userID)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+
+	day := calendar.today()
+	if req.Date != "" {
+		parsed, err := time.Parse(config.DateFormat, req.Date)
+		if err != nil {
+			return time.Time{}, nil, apperrors.NewValidationError("INVALID_DATE", "date", "Field 'date' must be a date in YYYY-MM-DD format")
+		}
+		day = parsed
+	}
+
+	if len(req.Values) == 0 {
+		return time.Time{}, nil, apperrors.NewValidationError("EMPTY_FIELD", "values", "Field 'values' cannot be empty")
+	}
+
+	start, end := calendar.dayMoments(day)
+
+	// Entries of past days are put at the start of the day, today's at the current time
+	entryDate := start
+	if day.Equal(calendar.today()) {
+		entryDate = time.Now().UTC()
+	}
+
+	var errs apperrors.ValidationErrors
+	var entries []models.RequirementEntry
+	// replaced tells which requirements have their latest entry of the day replaced instead of a new one added
+	replaced := make(map[int64]bool)
+	taskIDs := make(map[int64]bool)
+	seen := make(map[int64]bool)
+
+	// Keys are read in order, so the same request always reports the same key as repeated
+	for _, key := range slices.Sorted(maps.Keys(req.Values)) {
+		value := req.Values[key]
+		path := fmt.Sprintf("values.%s", key)
+
+		requirementID, err := strconv.ParseInt(key, 10, 64)
+		if err != nil {
+			errs = append(errs, apperrors.NewValidationError("INVALID_ID", path, "Key must be a requirement ID"))
+			continue
+		}
+		// Keys like "5" and "05" name the same requirement, the second one would replace the first
+		if seen[requirementID] {
+			errs = append(errs, apperrors.NewValidationError("DUPLICATE_REQUIREMENT", path, "Requirement is given more than once"))
+			continue
+		}
+		seen[requirementID] = true
+
+		requirement, err := s.getOwnedRequirement(requirementID, userID)
+		if err != nil {
+			return time.Time{}, nil, err
+		}
+
+		err = checkLoggable(&requirement)
+		var parsed string
+		var unit *string
+		if err == nil {
+			parsed, unit, err = parseEntryValue(&requirement, value)
+		}
+		var validationErr *apperrors.ValidationError
+		if errors.As(err, &validationErr) {
+			validationErr.Field = path
+			errs = append(errs, validationErr)
+			continue
+		} else if err != nil {
+			return time.Time{}, nil, err
+		}
+
+		entries = append(entries, models.RequirementEntry{
+			RequirementID: requirementID,
+			EntryDate:     entryDate,
+			Value:         parsed,
+			Unit:          unit,
+		})
+		replaced[requirementID] = requirement.Aggregation == nil || *requirement.Aggregation == "last"
+		taskIDs[requirement.TaskID] = true
+	}
+
+	if len(errs) > 0 {
+		sort.Slice(errs, func(i, j int) bool {
+			return errs[i].Field < errs[j].Field
+		})
+		return time.Time{}, nil, errs
+	}
+
+	var sortedTaskIDs []int64
+	for taskID := range taskIDs {
+		sortedTaskIDs = append(sortedTaskIDs, taskID)
+	}
+	sort.Slice(sortedTaskIDs, func(i, j int) bool {
+		return sortedTaskIDs[i] < sortedTaskIDs[j]
+	})
+
+	var evaluations []*TaskEvaluation
+
+	err = s.withTx(func(tx *TaskService) error {
+		for i := range entries {
+			if replaced[entries[i].RequirementID] {
+				logged, err := tx.requirementEntryRepo.GetEntriesByRequirementIDs([]int64{entries[i].RequirementID}, start, end)
+				if err != nil {
+					return err
+				}
+				if len(logged) > 0 {
+					latest := logged[len(logged)-1]
+					latest.Value = entries[i].Value
+					latest.Unit = entries[i].Unit
+					if err := tx.requirementEntryRepo.UpdateEntry(&latest); err != nil {
+						return err
+					}
+					continue
+				}
+			}
+			if err := tx.requirementEntryRepo.CreateEntry(&entries[i]); err != nil {
+				return err
+			}
+		}
+
+		for _, taskID := range sortedTaskIDs {
+			evaluation, err := tx.RefreshTaskEntry(taskID, entryDate)
+			if err != nil {
+				return err
+			}
+			evaluations = append(evaluations, evaluation)
+		}
+
+		return nil
+	})
+	if err != nil {
+		logger.Log.Error().Err(err).Int64("userID", userID).Msg("Failed to check in")
+		return time.Time{}, nil, err
+	}
+
+	return day, evaluations, nil
+}
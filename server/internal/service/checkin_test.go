@@ -0,0 +1,156 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/boreymarf/task-fuss/server/internal/apperrors"
+	"github.com/boreymarf/task-fuss/server/internal/dto"
+)
+
+// loggedValues returns the values logged for the requirement on the day in the order they count.
+func loggedValues(t *testing.T, s *TaskService, userID int64, requirementID int64, date time.Time) []string {
+	t.Helper()
+
+	entries, err := s.GetRequirementEntries(requirementID, userID, Range{From: date, To: date.AddDate(0, 0, 1), FromDate: true, ToDate: true})
+	if err != nil {
+		t.Fatalf("failed to read entries: %v", err)
+	}
+
+	var values []string
+	for _, entry := range entries {
+		values = append(values, entry.Value)
+	}
+	return values
+}
+
+func TestCheckIn(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		// logged are the values logged before the check-in
+		logged []string
+		value  string
+		want   []string
+	}{
+		{name: "first value", expression: "pushups >= 50", value: "60", want: []string{"60"}},
+		{name: "replaces the value", expression: "pushups >= 50", logged: []string{"30"}, value: "60", want: []string{"60"}},
+		{name: "replaces the latest value only", expression: "pushups >= 50", logged: []string{"10", "30"}, value: "60", want: []string{"10", "60"}},
+		{name: "adds to a sum", expression: "sum(pushups) >= 50", logged: []string{"30"}, value: "20", want: []string{"30", "20"}},
+		{name: "adds to a count", expression: "count(coffee) <= 2", logged: []string{"true"}, value: "true", want: []string{"true", "true"}},
+		{name: "adds to a maximum", expression: "max(pushups) >= 50", logged: []string{"30", "40"}, value: "60", want: []string{"30", "40", "60"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, userID := newTestService(t)
+			task := createTestTask(t, s, userID, "task", tt.expression)
+			today := day(time.Now().UTC().Format("2006-01-02"))
+
+			var requirementID int64
+			for _, id := range atomIDs(t, s, task.ID) {
+				requirementID = id
+			}
+
+			// Entries are a minute apart from the start of the day, so their order is clear
+			for i, value := range tt.logged {
+				entryDate := today.Add(time.Duration(i) * time.Minute)
+				if _, _, err := s.AddRequirementEntry(requirementID, userID, &dto.CreateEntryRequest{Value: value, EntryDate: &entryDate}); err != nil {
+					t.Fatalf("failed to log %s: %v", value, err)
+				}
+			}
+
+			_, evaluations, err := s.CheckIn(userID, &dto.CheckInRequest{
+				Values: map[string]string{strconv.FormatInt(requirementID, 10): tt.value},
+			})
+			if err != nil {
+				t.Fatalf("CheckIn returned error: %v", err)
+			}
+			if len(evaluations) != 1 || evaluations[0].TaskID != task.ID {
+				t.Fatalf("CheckIn evaluated %d tasks, want task %d", len(evaluations), task.ID)
+			}
+
+			if got := loggedValues(t, s, userID, requirementID, today); !slices.Equal(got, tt.want) {
+				t.Errorf("values = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckInKeepsNotes(t *testing.T) {
+	s, userID := newTestService(t)
+	task := createTestTask(t, s, userID, "task", "pushups >= 50")
+	requirementID := atomIDs(t, s, task.ID)["pushups"]
+	today := day(time.Now().UTC().Format("2006-01-02"))
+
+	note := "sore arms"
+	if _, _, err := s.AddRequirementEntry(requirementID, userID, &dto.CreateEntryRequest{Value: "30", EntryDate: &today, Note: &note}); err != nil {
+		t.Fatalf("failed to log: %v", err)
+	}
+
+	if _, _, err := s.CheckIn(userID, &dto.CheckInRequest{Values: map[string]string{strconv.FormatInt(requirementID, 10): "60"}}); err != nil {
+		t.Fatalf("CheckIn returned error: %v", err)
+	}
+
+	entries, err := s.GetRequirementEntries(requirementID, userID, Range{From: today, To: today.AddDate(0, 0, 1), FromDate: true, ToDate: true})
+	if err != nil {
+		t.Fatalf("failed to read entries: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Value != "60" {
+		t.Fatalf("entries = %+v, want a single entry of 60", entries)
+	}
+	if entries[0].Note == nil || *entries[0].Note != note {
+		t.Errorf("note = %v, want %q", entries[0].Note, note)
+	}
+}
+
+func TestCheckInErrors(t *testing.T) {
+	s, userID := newTestService(t)
+	task := createTestTask(t, s, userID, "task", "pushups >= 50 and sum(water) >= 2000")
+	ids := atomIDs(t, s, task.ID)
+	pushups, water := strconv.FormatInt(ids["pushups"], 10), strconv.FormatInt(ids["water"], 10)
+	today := day(time.Now().UTC().Format("2006-01-02"))
+
+	logValue(t, s, userID, ids["pushups"], today, "30")
+	logValue(t, s, userID, ids["water"], today, "500")
+
+	tests := []struct {
+		name   string
+		values map[string]string
+		code   string
+		field  string
+	}{
+		{name: "same requirement twice", values: map[string]string{pushups: "60", "0" + pushups: "70"}, code: "DUPLICATE_REQUIREMENT", field: "values." + pushups},
+		{name: "invalid value", values: map[string]string{pushups: "60", water: "abc"}, code: "INVALID_VALUE", field: "values." + water},
+		{name: "invalid key", values: map[string]string{pushups: "60", "water": "1000"}, code: "INVALID_ID", field: "values.water"},
+		{name: "no values", values: map[string]string{}, code: "EMPTY_FIELD", field: "values"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := s.CheckIn(userID, &dto.CheckInRequest{Values: tt.values})
+
+			var validationErr *apperrors.ValidationError
+			var validationErrs apperrors.ValidationErrors
+			if errors.As(err, &validationErrs) && len(validationErrs) == 1 {
+				validationErr = validationErrs[0]
+			} else if !errors.As(err, &validationErr) {
+				t.Fatalf("CheckIn error = %v, want a single validation error", err)
+			}
+			if validationErr.Code != tt.code || validationErr.Field != tt.field {
+				t.Errorf("error = %s at %q, want %s at %q", validationErr.Code, validationErr.Field, tt.code, tt.field)
+			}
+
+			// Nothing is stored when any value is rejected
+			if got := loggedValues(t, s, userID, ids["pushups"], today); !slices.Equal(got, []string{"30"}) {
+				t.Errorf("pushups = %v, want [30]", got)
+			}
+			if got := loggedValues(t, s, userID, ids["water"], today); !slices.Equal(got, []string{"500"}) {
+				t.Errorf("water = %v, want [500]", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,275 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/boreymarf/task-fuss/server/internal/apperrors"
+	"github.com/boreymarf/task-fuss/server/internal/dto"
+	"github.com/boreymarf/task-fuss/server/internal/duration"
+	"github.com/boreymarf/task-fuss/server/internal/logger"
+	"github.com/boreymarf/task-fuss/server/internal/models"
+	"github.com/boreymarf/task-fuss/server/internal/units"
+)
+
+// getOwnedRequirement returns the requirement if the task it belongs to is owned by the user.
+func (s *TaskService) getOwnedRequirement(requirementID int64, userID int64) (models.Requirement, error) {
+
+	requirement, err := s.requirementRepo.GetRequirementByID(requirementID)
+	if err != nil {
+		return models.Requirement{}, err
+	}
+
+	task, err := s.taskRepo.GetTaskByID(requirement.TaskID)
+	if err != nil {
+		return models.Requirement{}, err
+	}
+
+	if task.OwnerID != userID {
+		return models.Requirement{}, apperrors.ErrForbidden
+	}
+
+	return requirement, nil
+}
+
+// getOwnedEntry returns the entry together with its requirement if the user owns them.
+func (s *TaskService) getOwnedEntry(entryID int64, userID int64) (models.RequirementEntry, models.Requirement, error) {
+
+	entry, err := s.requirementEntryRepo.GetEntryByID(entryID)
+	if err != nil {
+		return models.RequirementEntry{}, models.Requirement{}, err
+	}
+
+	requirement, err := s.getOwnedRequirement(entry.RequirementID, userID)
+	if err != nil {
+		return models.RequirementEntry{}, models.Requirement{}, err
+	}
+
+	return entry, requirement, nil
+}
+
+// checkLoggable rejects new entries for requirements that are no longer part of the current tree.
+// Entries logged before the requirement was retired can still be changed.
+func checkLoggable(requirement *models.Requirement) error {
+	if requirement.Retired {
+		return apperrors.NewValidationError("RETIRED_REQUIREMENT", "requirement_id", fmt.Sprintf("Requirement %d was removed from the task, values cannot be logged for it anymore", requirement.ID))
+	}
+	return nil
+}
+
+// normalizeNote trims the note, an empty note is stored as none.
+func normalizeNote(note *string) *string {
+	if note == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*note)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+// parseEntryValue checks that the value can be logged for the requirement and returns it the way
+// it is stored, durations as seconds, together with the unit it was entered in. Values of requirements with a unit may be followed
+// by any unit of the same dimension, "3.1 mi" for a requirement in km. Values without one are
+// in the unit of the requirement.
+func parseEntryValue(requirement *models.Requirement, value string) (string, *string, error) {
+
+	if requirement.Type != "atom" {
+		return "", nil, apperrors.NewValidationError("INVALID_REQUIREMENT", "requirement_id", "Values can only be logged for atoms, not for conditions")
+	}
+
+	dataType := "none"
+	if requirement.DataType != nil {
+		dataType = *requirement.DataType
+	}
+
+	if requirement.Unit == nil {
+		if err := checkValue(dataType, requirement.Options, value); err != nil {
+			return "", nil, apperrors.NewValidationError("INVALID_VALUE", "value", "Value "+err.Error())
+		}
+
+		// Durations are stored as seconds whatever format they were entered in
+		if dataType == "duration" {
+			d, _ := duration.Parse(value)
+			return duration.Canonical(d), nil, nil
+		}
+
+		return value, nil, nil
+	}
+
+	target, ok := units.Lookup(*requirement.Unit)
+	if !ok {
+		return "", nil, fmt.Errorf("requirement %d has unknown unit '%s'", requirement.ID, *requirement.Unit)
+	}
+
+	number, name := units.Split(value)
+
+	unit := target
+	if name != "" {
+		unit, ok = units.Lookup(name)
+		if !ok {
+			return "", nil, apperrors.NewValidationError("INVALID_UNIT", "value", fmt.Sprintf("Unit '%s' is not known, use one of: %s", name, strings.Join(units.Symbols(target.Dimension), ", ")))
+		}
+		if unit.Dimension != target.Dimension {
+			return "", nil, apperrors.NewValidationError("INVALID_UNIT", "value", fmt.Sprintf("Unit '%s' cannot be converted to '%s', use one of: %s", name, target.Symbol, strings.Join(units.Symbols(target.Dimension), ", ")))
+		}
+	}
+
+	// Converted values are rarely whole, so any number is accepted
+	if err := checkValue("float", nil, number); err != nil {
+		return "", nil, apperrors.NewValidationError("INVALID_VALUE", "value", "Value "+err.Error())
+	}
+
+	return number, &unit.Symbol, nil
+}
+
+func (s *TaskService) AddRequirementEntry(requirementID int64, userID int64, req *dto.CreateEntryRequest) (*models.RequirementEntry, *TaskEvaluation, error) {
+
+	requirement, err := s.getOwnedRequirement(requirementID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := checkLoggable(&requirement); err != nil {
+		return nil, nil, err
+	}
+
+	value, unit, err := parseEntryValue(&requirement, req.Value)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entry := models.RequirementEntry{
+		RequirementID: requirementID,
+		EntryDate:     time.Now().UTC(),
+		Value:         value,
+		Unit:          unit,
+		Note:          normalizeNote(req.Note),
+	}
+	if req.EntryDate != nil {
+		entry.EntryDate = req.EntryDate.UTC()
+	}
+
+	var evaluation *TaskEvaluation
+
+	err = s.withTx(func(tx *TaskService) error {
+		if err := tx.requirementEntryRepo.CreateEntry(&entry); err != nil {
+			return err
+		}
+
+		evaluation, err = tx.RefreshTaskEntry(requirement.TaskID, entry.EntryDate)
+		return err
+	})
+	if err != nil {
+		logger.Log.Error().Err(err).Int64("requirementID", requirementID).Msg("Failed to add requirement entry")
+		return nil, nil, err
+	}
+
+	return &entry, evaluation, nil
+}
+
+func (s *TaskService) GetRequirementEntries(requirementID int64, userID int64, r Range) ([]models.RequirementEntry, error) {
+
+	if _, err := s.getOwnedRequirement(requirementID, userID); err != nil {
+		return nil, err
+	}
+
+	calendar, err := s.calendarFor(userID)
+	if err != nil {
+		return nil, err
+	}
+	from, to := calendar.moments(r)
+
+	return s.requirementEntryRepo.GetEntriesByRequirementIDs([]int64{requirementID}, from, to)
+}
+
+func (s *TaskService) GetRequirementEntryByID(entryID int64, userID int64) (*models.RequirementEntry, error) {
+
+	entry, _, err := s.getOwnedEntry(entryID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+func (s *TaskService) UpdateRequirementEntry(entryID int64, userID int64, req *dto.UpdateEntryRequest) (*models.RequirementEntry, *TaskEvaluation, error) {
+
+	entry, requirement, err := s.getOwnedEntry(entryID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	calendar, err := s.calendarFor(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	previousDate := entry.EntryDate
+
+	if req.Value != nil {
+		value, unit, err := parseEntryValue(&requirement, *req.Value)
+		if err != nil {
+			return nil, nil, err
+		}
+		entry.Value = value
+		entry.Unit = unit
+	}
+	if req.EntryDate != nil {
+		entry.EntryDate = req.EntryDate.UTC()
+	}
+	if req.Note != nil {
+		entry.Note = normalizeNote(req.Note)
+	}
+
+	var evaluation *TaskEvaluation
+
+	err = s.withTx(func(tx *TaskService) error {
+		if err := tx.requirementEntryRepo.UpdateEntry(&entry); err != nil {
+			return err
+		}
+
+		// The entry may have been moved to another day, which has to be refreshed too
+		if !calendar.dayOf(previousDate).Equal(calendar.dayOf(entry.EntryDate)) {
+			if _, err := tx.RefreshTaskEntry(requirement.TaskID, previousDate); err != nil {
+				return err
+			}
+		}
+
+		evaluation, err = tx.RefreshTaskEntry(requirement.TaskID, entry.EntryDate)
+		return err
+	})
+	if err != nil {
+		logger.Log.Error().Err(err).Int64("entryID", entryID).Msg("Failed to update requirement entry")
+		return nil, nil, err
+	}
+
+	return &entry, evaluation, nil
+}
+
+func (s *TaskService) DeleteRequirementEntry(entryID int64, userID int64) (*TaskEvaluation, error) {
+
+	entry, requirement, err := s.getOwnedEntry(entryID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var evaluation *TaskEvaluation
+
+	err = s.withTx(func(tx *TaskService) error {
+		if err := tx.requirementEntryRepo.DeleteEntry(entry.ID); err != nil {
+			return err
+		}
+
+		evaluation, err = tx.RefreshTaskEntry(requirement.TaskID, entry.EntryDate)
+		return err
+	})
+	if err != nil {
+		logger.Log.Error().Err(err).Int64("entryID", entryID).Msg("Failed to delete requirement entry")
+		return nil, err
+	}
+
+	return evaluation, nil
+}
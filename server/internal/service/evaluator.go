@@ -0,0 +1,672 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/boreymarf/task-fuss/server/internal/dto"
+	"github.com/boreymarf/task-fuss/server/internal/duration"
+	"github.com/boreymarf/task-fuss/server/internal/models"
+	"github.com/boreymarf/task-fuss/server/internal/timeofday"
+	"github.com/boreymarf/task-fuss/server/internal/units"
+)
+
+// States of a requirement, they tell whether the outcome can still change.
+const (
+	StateMet     = "met"
+	StatePending = "pending"
+	StateFailed  = "failed"
+)
+
+// RequirementResult is the outcome of evaluating a single requirement node.
+type RequirementResult struct {
+	RequirementID int64
+	Title         string
+	Passed        bool
+	// State is StatePending while the period of the requirement is not over and it can still be met
+	State string
+	// Score is partial credit from 0 to 1, it is 1 when the requirement is passed
+	Score float64
+	// Value is the logged value the atom was compared with, nil if nothing was logged
+	Value    *string
+	Operands []RequirementResult
+}
+
+// TaskEvaluation is the outcome of evaluating the whole requirement tree of a task for one day.
+type TaskEvaluation struct {
+	TaskID int64
+	Date   time.Time
+	// Due is false when the schedule of the task does not include the day
+	Due         bool
+	Passed      bool
+	State       string
+	Score       float64
+	Requirement RequirementResult
+}
+
+// Results returns pass/fail of every node of the tree by requirement ID.
+func (e *TaskEvaluation) Results() map[int64]bool {
+	results := make(map[int64]bool)
+
+	var walk func(r *RequirementResult)
+	walk = func(r *RequirementResult) {
+		results[r.RequirementID] = r.Passed
+		for i := range r.Operands {
+			walk(&r.Operands[i])
+		}
+	}
+	walk(&e.Requirement)
+
+	return results
+}
+
+type evaluator struct {
+	calendar Calendar
+	day      time.Time
+	// dayEnd is the first moment after the evaluated day
+	dayEnd  time.Time
+	now     time.Time
+	entries map[int64][]models.RequirementEntry
+}
+
+// EvaluateRequirement evaluates the requirement tree for the day, which is split from the others by the calendar.
+// Entries of an atom are taken from its period, which is the day itself unless the atom
+// says otherwise, and combined according to its aggregation, by default the latest one is used.
+// Entries must cover the longest period of the tree, entries logged after the day are ignored.
+func EvaluateRequirement(root *dto.Requirement, entries []models.RequirementEntry, calendar Calendar, day time.Time) (RequirementResult, error) {
+
+	e := &evaluator{
+		calendar: calendar,
+		now:      time.Now(),
+		entries:  make(map[int64][]models.RequirementEntry),
+	}
+	e.day, _ = dayBounds(day)
+	_, e.dayEnd = calendar.dayMoments(e.day)
+
+	for _, entry := range entries {
+		e.entries[entry.RequirementID] = append(e.entries[entry.RequirementID], entry)
+	}
+
+	for id := range e.entries {
+		sort.SliceStable(e.entries[id], func(i, j int) bool {
+			return e.entries[id][i].EntryDate.Before(e.entries[id][j].EntryDate)
+		})
+	}
+
+	return e.evaluateNode(root)
+}
+
+func (e *evaluator) evaluateNode(r *dto.Requirement) (RequirementResult, error) {
+
+	result := RequirementResult{
+		RequirementID: r.ID,
+		Title:         r.Title,
+	}
+
+	switch r.Type {
+	case "atom":
+		period, periodDays := atomPeriod(r)
+		firstDay, nextDay := e.calendar.periodBounds(period, periodDays, e.day)
+		periodStart, periodEnd := e.calendar.dayStart(firstDay), e.calendar.dayStart(nextDay)
+
+		var entries []models.RequirementEntry
+		for _, entry := range e.entries[r.ID] {
+			if !entry.EntryDate.Before(periodStart) && entry.EntryDate.Before(e.dayEnd) {
+				entries = append(entries, entry)
+			}
+		}
+
+		entries, err := convertEntries(r, entries)
+		if err != nil {
+			return result, err
+		}
+
+		value, exists, err := aggregateEntries(r, entries)
+		if err != nil {
+			return result, err
+		}
+
+		if exists {
+			result.Value = &value
+		}
+
+		// A count of nothing is still compared, no coffee at all passes "count(coffee) <= 2"
+		if exists || isCount(r) {
+			result.Passed, err = evaluateAtom(r, value)
+			if err != nil {
+				return result, err
+			}
+		}
+
+		result.State = atomState(r, result.Passed, e.now.Before(periodEnd))
+		result.Score = atomScore(r, value, exists, result.Passed)
+
+	case "condition":
+		if r.Operator == nil {
+			return result, fmt.Errorf("condition %d has no operator", r.ID)
+		}
+		if len(r.Operands) == 0 {
+			return result, fmt.Errorf("condition %d has no operands", r.ID)
+		}
+
+		for i := range r.Operands {
+			operand, err := e.evaluateNode(&r.Operands[i])
+			if err != nil {
+				return result, err
+			}
+			result.Operands = append(result.Operands, operand)
+		}
+
+		met, failed := 0, 0
+		for _, operand := range result.Operands {
+			switch operand.State {
+			case StateMet:
+				met++
+			case StateFailed:
+				failed++
+			}
+		}
+
+		result.State = StatePending
+
+		switch *r.Operator {
+		case "and":
+			result.Passed = true
+			for _, operand := range result.Operands {
+				result.Passed = result.Passed && operand.Passed
+			}
+			if failed > 0 {
+				result.State = StateFailed
+			} else if met == len(result.Operands) {
+				result.State = StateMet
+			}
+			result.Score = andScore(r, result.Operands)
+		case "or":
+			for _, operand := range result.Operands {
+				result.Passed = result.Passed || operand.Passed
+				result.Score = max(result.Score, operand.Score)
+			}
+			if met > 0 {
+				result.State = StateMet
+			} else if failed == len(result.Operands) {
+				result.State = StateFailed
+			}
+		case "not":
+			if len(result.Operands) != 1 {
+				return result, fmt.Errorf("condition %d: 'not' takes exactly one operand", r.ID)
+			}
+			result.Passed = !result.Operands[0].Passed
+			// Partial credit of the operand tells how close it is to passing, not how close the negation is,
+			// so the negation scores 1 when it passes and 0 otherwise
+			result.Score = 0
+			if result.Passed {
+				result.Score = 1
+			}
+			if failed > 0 {
+				result.State = StateMet
+			} else if met > 0 {
+				result.State = StateFailed
+			}
+		default:
+			return result, fmt.Errorf("condition %d has unknown operator '%s'", r.ID, *r.Operator)
+		}
+
+	default:
+		return result, fmt.Errorf("requirement %d has unknown type '%s'", r.ID, r.Type)
+	}
+
+	return result, nil
+}
+
+// atomPeriod returns the period of the atom and the length of a rolling one.
+func atomPeriod(r *dto.Requirement) (string, int) {
+	period := "day"
+	if r.Period != nil {
+		period = *r.Period
+	}
+
+	periodDays := 0
+	if r.PeriodDays != nil {
+		periodDays = *r.PeriodDays
+	}
+
+	return period, periodDays
+}
+
+// atomState tells whether an atom that has not passed yet can still pass before its period is over.
+// Counts and sums only grow, so once they are above a "<=" or "<" limit it is too late.
+func atomState(r *dto.Requirement, passed bool, periodOpen bool) string {
+
+	if passed {
+		return StateMet
+	}
+	if !periodOpen {
+		return StateFailed
+	}
+
+	if r.Aggregation != nil && (*r.Aggregation == "count" || *r.Aggregation == "sum") &&
+		r.Operator != nil && (*r.Operator == "<=" || *r.Operator == "<") {
+		return StateFailed
+	}
+
+	return StatePending
+}
+
+// andScore averages the scores of the operands, or takes the lowest one when the score mode is "min".
+func andScore(r *dto.Requirement, operands []RequirementResult) float64 {
+
+	if r.ScoreMode != nil && *r.ScoreMode == "min" {
+		score := 1.0
+		for _, operand := range operands {
+			score = min(score, operand.Score)
+		}
+		return score
+	}
+
+	sum := 0.0
+	for _, operand := range operands {
+		sum += operand.Score
+	}
+	return sum / float64(len(operands))
+}
+
+// atomScore shows how close a failed numeric atom came to its target, 45 of 50 pushups is 0.9.
+// Atoms that are not numeric either pass or score 0.
+func atomScore(r *dto.Requirement, value string, exists bool, passed bool) float64 {
+
+	if passed {
+		return 1
+	}
+	if !exists || r.TargetValue == nil {
+		return 0
+	}
+
+	dataType := atomDataType(r)
+
+	v, err := numericValue(dataType, value)
+	if err != nil {
+		return 0
+	}
+	t, err := numericValue(dataType, *r.TargetValue)
+	if err != nil {
+		return 0
+	}
+
+	operator := "=="
+	if r.Operator != nil {
+		operator = *r.Operator
+	}
+
+	var score float64
+	switch operator {
+	case ">=", ">":
+		if t > 0 {
+			score = v / t
+		}
+	case "<=", "<":
+		if v > 0 {
+			score = t / v
+		}
+	case "==":
+		if v > 0 && t > 0 {
+			score = min(v, t) / max(v, t)
+		}
+	}
+
+	// A value right at the limit of a strict comparison has not passed, so it does not get full credit
+	return min(max(score, 0), 0.99)
+}
+
+// numericValue parses int, float, rating and duration values as a number, durations are in seconds.
+// Times of day are not numeric, waking up at 07:30 is not 93% of waking up at 07:00.
+func numericValue(dataType string, value string) (float64, error) {
+	switch dataType {
+	case "int", "float", "rating":
+		return strconv.ParseFloat(strings.TrimSpace(value), 64)
+	case "duration":
+		d, err := duration.Parse(value)
+		return d.Seconds(), err
+	default:
+		return 0, fmt.Errorf("data type '%s' is not numeric", dataType)
+	}
+}
+
+func isCount(r *dto.Requirement) bool {
+	return r.Aggregation != nil && *r.Aggregation == "count"
+}
+
+// atomDataType returns the data type values of the atom are compared as.
+func atomDataType(r *dto.Requirement) string {
+
+	// Counts are numbers of entries whatever the data type of the atom is
+	if isCount(r) {
+		return "int"
+	}
+
+	if r.DataType == nil {
+		return "none"
+	}
+
+	// Values converted from other units are rarely whole
+	if *r.DataType == "int" && r.Unit != nil {
+		return "float"
+	}
+
+	return *r.DataType
+}
+
+// convertEntries returns copies of the entries with values in the unit of the atom.
+// Entries without a unit are already in it.
+func convertEntries(r *dto.Requirement, entries []models.RequirementEntry) ([]models.RequirementEntry, error) {
+
+	if r.Unit == nil {
+		return entries, nil
+	}
+
+	target, ok := units.Lookup(*r.Unit)
+	if !ok {
+		return nil, fmt.Errorf("requirement %d has unknown unit '%s'", r.ID, *r.Unit)
+	}
+
+	converted := make([]models.RequirementEntry, len(entries))
+	for i, entry := range entries {
+		converted[i] = entry
+		if entry.Unit == nil || *entry.Unit == target.Symbol {
+			continue
+		}
+
+		unit, ok := units.Lookup(*entry.Unit)
+		if !ok {
+			return nil, fmt.Errorf("entry %d has unknown unit '%s'", entry.ID, *entry.Unit)
+		}
+
+		v, err := strconv.ParseFloat(strings.TrimSpace(entry.Value), 64)
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: invalid value '%s'", entry.ID, entry.Value)
+		}
+
+		v, err = units.Convert(v, unit, target)
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: %w", entry.ID, err)
+		}
+
+		converted[i].Value = strconv.FormatFloat(v, 'f', -1, 64)
+		converted[i].Unit = &target.Symbol
+	}
+
+	return converted, nil
+}
+
+// aggregateEntries combines entries of the atom, which are sorted by date, into a single value.
+// The second return value is false when nothing was logged, a count is zero then.
+func aggregateEntries(r *dto.Requirement, entries []models.RequirementEntry) (string, bool, error) {
+
+	aggregation := "last"
+	if r.Aggregation != nil {
+		aggregation = *r.Aggregation
+	}
+
+	if aggregation == "count" {
+		return strconv.Itoa(len(entries)), len(entries) > 0, nil
+	}
+
+	if len(entries) == 0 {
+		return "", false, nil
+	}
+
+	if aggregation == "last" {
+		return entries[len(entries)-1].Value, true, nil
+	}
+
+	dataType := atomDataType(r)
+
+	switch dataType {
+	case "int", "rating":
+		var values []int64
+		for _, entry := range entries {
+			v, err := strconv.ParseInt(strings.TrimSpace(entry.Value), 10, 64)
+			if err != nil {
+				return "", false, fmt.Errorf("requirement %d: invalid %s value '%s'", r.ID, dataType, entry.Value)
+			}
+			values = append(values, v)
+		}
+		v, err := aggregateValues(aggregation, values)
+		if err != nil {
+			return "", false, fmt.Errorf("requirement %d: %w", r.ID, err)
+		}
+		return strconv.FormatInt(v, 10), true, nil
+
+	case "float":
+		var values []float64
+		for _, entry := range entries {
+			v, err := strconv.ParseFloat(strings.TrimSpace(entry.Value), 64)
+			if err != nil {
+				return "", false, fmt.Errorf("requirement %d: invalid float value '%s'", r.ID, entry.Value)
+			}
+			values = append(values, v)
+		}
+		v, err := aggregateValues(aggregation, values)
+		if err != nil {
+			return "", false, fmt.Errorf("requirement %d: %w", r.ID, err)
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64), true, nil
+
+	case "duration":
+		var values []time.Duration
+		for _, entry := range entries {
+			v, err := duration.Parse(entry.Value)
+			if err != nil {
+				return "", false, fmt.Errorf("requirement %d: invalid duration value '%s'", r.ID, entry.Value)
+			}
+			values = append(values, v)
+		}
+		v, err := aggregateValues(aggregation, values)
+		if err != nil {
+			return "", false, fmt.Errorf("requirement %d: %w", r.ID, err)
+		}
+		return strconv.FormatInt(int64(v/time.Second), 10), true, nil
+
+	case "time":
+		// Adding times of day up means nothing, only the earliest and the latest one do
+		if aggregation == "sum" {
+			break
+		}
+		var values []time.Duration
+		for _, entry := range entries {
+			v, err := timeofday.Parse(entry.Value)
+			if err != nil {
+				return "", false, fmt.Errorf("requirement %d: invalid time value '%s'", r.ID, entry.Value)
+			}
+			values = append(values, v)
+		}
+		v, err := aggregateValues(aggregation, values)
+		if err != nil {
+			return "", false, fmt.Errorf("requirement %d: %w", r.ID, err)
+		}
+		return timeofday.Format(v), true, nil
+	}
+
+	return "", false, fmt.Errorf("requirement %d: aggregation '%s' is not applicable to data type '%s'", r.ID, aggregation, dataType)
+}
+
+func aggregateValues[T int64 | float64 | time.Duration](aggregation string, values []T) (T, error) {
+	result := values[0]
+
+	switch aggregation {
+	case "sum":
+		for _, v := range values[1:] {
+			result += v
+		}
+	case "max":
+		for _, v := range values[1:] {
+			result = max(result, v)
+		}
+	case "min":
+		for _, v := range values[1:] {
+			result = min(result, v)
+		}
+	default:
+		return result, fmt.Errorf("unknown aggregation '%s'", aggregation)
+	}
+
+	return result, nil
+}
+
+func evaluateAtom(r *dto.Requirement, value string) (bool, error) {
+
+	dataType := atomDataType(r)
+
+	// Atoms without data are satisfied by the fact that something was logged
+	if dataType == "none" {
+		return true, nil
+	}
+
+	if dataType == "text" {
+		return strings.TrimSpace(value) != "", nil
+	}
+
+	operator := "=="
+	if r.Operator != nil {
+		operator = *r.Operator
+	}
+
+	target := ""
+	if r.TargetValue != nil {
+		target = *r.TargetValue
+	} else if dataType == "bool" {
+		target = "true"
+	} else {
+		return false, fmt.Errorf("requirement %d has no target value", r.ID)
+	}
+
+	cmp, err := compareValues(dataType, value, target)
+	if err != nil {
+		return false, fmt.Errorf("requirement %d: %w", r.ID, err)
+	}
+
+	if (dataType == "bool" || dataType == "choice") && operator != "==" && operator != "!=" {
+		return false, fmt.Errorf("requirement %d: operator '%s' is not applicable to %s", r.ID, operator, dataType)
+	}
+
+	switch operator {
+	case "==":
+		return cmp == 0, nil
+	case "!=":
+		return cmp != 0, nil
+	case ">=":
+		return cmp >= 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	case "<":
+		return cmp < 0, nil
+	default:
+		return false, fmt.Errorf("requirement %d has unknown operator '%s'", r.ID, operator)
+	}
+}
+
+// compareValues parses both values as dataType and returns -1, 0 or 1
+// depending on whether value is less than, equal to or greater than target.
+func compareValues(dataType string, value string, target string) (int, error) {
+
+	switch dataType {
+	case "bool":
+		v, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return 0, fmt.Errorf("invalid bool value '%s'", value)
+		}
+		t, err := strconv.ParseBool(strings.TrimSpace(target))
+		if err != nil {
+			return 0, fmt.Errorf("invalid bool target '%s'", target)
+		}
+		if v == t {
+			return 0, nil
+		}
+		return 1, nil
+
+	case "choice":
+		// Options are not ordered, so only equality makes sense
+		if strings.TrimSpace(value) == strings.TrimSpace(target) {
+			return 0, nil
+		}
+		return 1, nil
+
+	case "int", "rating":
+		v, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s value '%s'", dataType, value)
+		}
+		t, err := strconv.ParseInt(strings.TrimSpace(target), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s target '%s'", dataType, target)
+		}
+		return compareOrdered(v, t), nil
+
+	case "float":
+		v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid float value '%s'", value)
+		}
+		t, err := strconv.ParseFloat(strings.TrimSpace(target), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid float target '%s'", target)
+		}
+		return compareOrdered(v, t), nil
+
+	case "duration":
+		v, err := duration.Parse(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration value '%s'", value)
+		}
+		t, err := duration.Parse(target)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration target '%s'", target)
+		}
+		return compareOrdered(v, t), nil
+
+	case "time":
+		v, err := timeofday.Parse(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid time value '%s'", value)
+		}
+		t, err := timeofday.Parse(target)
+		if err != nil {
+			return 0, fmt.Errorf("invalid time target '%s'", target)
+		}
+		return compareOrdered(v, t), nil
+
+	default:
+		return 0, fmt.Errorf("unknown data type '%s'", dataType)
+	}
+}
+
+func compareOrdered[T int64 | float64 | time.Duration](a T, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// dayBounds returns the day with the date of date in UTC and the day after it. Days given as dates
+// are normalized with it, moments logged by a user are put into days by their Calendar.
+func dayBounds(date time.Time) (time.Time, time.Time) {
+	date = date.UTC()
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 0, 1)
+}
+
+func collectRequirementIDs(r *dto.Requirement) []int64 {
+	ids := []int64{r.ID}
+	for i := range r.Operands {
+		ids = append(ids, collectRequirementIDs(&r.Operands[i])...)
+	}
+	return ids
+}
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/boreymarf/task-fuss/server/internal/dto"
+	"github.com/boreymarf/task-fuss/server/internal/models"
+)
+
+// atom returns an atom with the given ID, empty strings leave fields unset.
+func atom(id int64, dataType string, aggregation string, operator string, target string) dto.Requirement {
+	r := dto.Requirement{ID: id, Title: "atom", Type: "atom"}
+	if dataType != "" {
+		r.DataType = &dataType
+	}
+	if aggregation != "" {
+		r.Aggregation = &aggregation
+	}
+	if operator != "" {
+		r.Operator = &operator
+	}
+	if target != "" {
+		r.TargetValue = &target
+	}
+	return r
+}
+
+func condition(id int64, operator string, operands ...dto.Requirement) dto.Requirement {
+	return dto.Requirement{ID: id, Title: "condition", Type: "condition", Operator: &operator, Operands: operands}
+}
+
+// entriesOn returns an entry at noon of the day for every value of every requirement.
+func entriesOn(date time.Time, values map[int64][]string) []models.RequirementEntry {
+	var entries []models.RequirementEntry
+	for id, list := range values {
+		for i, value := range list {
+			entries = append(entries, models.RequirementEntry{
+				RequirementID: id,
+				EntryDate:     date.Add(12*time.Hour + time.Duration(i)*time.Minute),
+				Value:         value,
+			})
+		}
+	}
+	return entries
+}
+
+func TestEvaluateAtom(t *testing.T) {
+	tests := []struct {
+		dataType string
+		operator string
+		target   string
+		value    string
+		passed   bool
+	}{
+		{"int", ">=", "50", "50", true},
+		{"int", ">=", "50", "49", false},
+		{"int", ">", "50", "50", false},
+		{"int", ">", "50", "51", true},
+		{"int", "<=", "10", "10", true},
+		{"int", "<=", "10", "11", false},
+		{"int", "<", "10", "10", false},
+		{"int", "<", "10", "-3", true},
+		{"int", "==", "5", " 5 ", true},
+		{"int", "==", "5", "6", false},
+		{"int", "!=", "5", "6", true},
+		{"int", "!=", "5", "5", false},
+		{"float", "<=", "80.5", "80.5", true},
+		{"float", "<=", "80.5", "80.6", false},
+		{"float", ">", "0.1", "0.2", true},
+		{"float", "==", "2", "2.0", true},
+		{"duration", ">=", "1800s", "30m", true},
+		{"duration", ">=", "1800s", "29:59", false},
+		{"duration", ">=", "1800s", "PT1H", true},
+		{"duration", ">=", "1800s", "25", false},
+		{"duration", "<", "5400s", "1:29:59", true},
+		{"duration", "==", "5400s", "90", true},
+		{"bool", "", "", "true", true},
+		{"bool", "", "", "false", false},
+		{"bool", "==", "false", "false", true},
+		{"bool", "!=", "true", "false", true},
+		{"bool", "!=", "true", "TRUE", false},
+		{"time", "<=", "07:00", "06:59", true},
+		{"time", "<=", "07:00", "07:00:01", false},
+		{"time", ">", "22:00", "22:30", true},
+		{"choice", "==", "good", "good", true},
+		{"choice", "==", "good", "bad", false},
+		{"choice", "!=", "good", "bad", true},
+		{"text", "", "", "slept well", true},
+		{"text", "", "", "  ", false},
+		{"none", "", "", "", true},
+	}
+
+	date := day("2025-01-15")
+	for _, tt := range tests {
+		t.Run(tt.dataType+" "+tt.operator+" "+tt.target+" with "+tt.value, func(t *testing.T) {
+			root := atom(1, tt.dataType, "", tt.operator, tt.target)
+
+			result, err := EvaluateRequirement(&root, entriesOn(date, map[int64][]string{1: {tt.value}}), Calendar{}, date)
+			if err != nil {
+				t.Fatalf("EvaluateRequirement returned error: %v", err)
+			}
+			if result.Passed != tt.passed {
+				t.Errorf("passed = %v, want %v", result.Passed, tt.passed)
+			}
+
+			// The day is over, so the atom is either met or failed
+			wantState := StateFailed
+			if tt.passed {
+				wantState = StateMet
+			}
+			if result.State != wantState {
+				t.Errorf("state = %s, want %s", result.State, wantState)
+			}
+			if result.Value == nil || *result.Value != tt.value {
+				t.Errorf("value = %v, want %q", result.Value, tt.value)
+			}
+		})
+	}
+}
+
+func TestEvaluateRequirementStates(t *testing.T) {
+	pushups := atom(1, "int", "", ">=", "50")
+	meditated := atom(2, "bool", "", "", "")
+	coffee := atom(3, "none", "count", "<=", "2")
+	water := atom(4, "int", "sum", "<=", "1000")
+	gym := atom(5, "none", "count", ">=", "3")
+
+	minScore := condition(10, "and", pushups, meditated)
+	minScore.ScoreMode = ptr("min")
+
+	past := day("2025-01-15")
+	today := day(time.Now().UTC().Format("2006-01-02"))
+
+	tests := []struct {
+		name   string
+		root   dto.Requirement
+		date   time.Time
+		values map[int64][]string
+		passed bool
+		state  string
+		score  float64
+	}{
+		{name: "and met", root: condition(10, "and", pushups, meditated), date: past, values: map[int64][]string{1: {"50"}, 2: {"true"}}, passed: true, state: StateMet, score: 1},
+		{name: "and failed", root: condition(10, "and", pushups, meditated), date: past, values: map[int64][]string{1: {"45"}, 2: {"true"}}, state: StateFailed, score: 0.95},
+		{name: "and failed by a missing value", root: condition(10, "and", pushups, meditated), date: past, values: map[int64][]string{2: {"true"}}, state: StateFailed, score: 0.5},
+		{name: "and pending", root: condition(10, "and", pushups, meditated), date: today, values: map[int64][]string{2: {"true"}}, state: StatePending, score: 0.5},
+		{name: "and with the lowest score", root: minScore, date: past, values: map[int64][]string{1: {"45"}, 2: {"true"}}, state: StateFailed, score: 0.9},
+		{name: "or met", root: condition(10, "or", pushups, meditated), date: today, values: map[int64][]string{2: {"true"}}, passed: true, state: StateMet, score: 1},
+		{name: "or pending", root: condition(10, "or", pushups, meditated), date: today, values: map[int64][]string{1: {"25"}}, state: StatePending, score: 0.5},
+		{name: "or failed", root: condition(10, "or", pushups, meditated), date: past, values: map[int64][]string{1: {"25"}, 2: {"false"}}, state: StateFailed, score: 0.5},
+		{name: "not met", root: condition(10, "not", meditated), date: past, values: map[int64][]string{2: {"false"}}, passed: true, state: StateMet, score: 1},
+		{name: "not failed", root: condition(10, "not", meditated), date: today, values: map[int64][]string{2: {"true"}}, state: StateFailed},
+		{name: "not pending", root: condition(10, "not", meditated), date: today, passed: true, state: StatePending, score: 1},
+		{name: "count within the limit", root: coffee, date: today, values: map[int64][]string{3: {"", ""}}, passed: true, state: StateMet, score: 1},
+		{name: "count of nothing within the limit", root: coffee, date: today, passed: true, state: StateMet, score: 1},
+		// Counts and sums only grow, going over a limit cannot be undone before the day is over
+		{name: "count over the limit", root: coffee, date: today, values: map[int64][]string{3: {"", "", ""}}, state: StateFailed, score: 2.0 / 3},
+		{name: "sum over the limit", root: water, date: today, values: map[int64][]string{4: {"600", "600"}}, state: StateFailed, score: 1000.0 / 1200},
+		{name: "count below a minimum", root: gym, date: today, values: map[int64][]string{5: {""}}, state: StatePending, score: 1.0 / 3},
+		{name: "nested", root: condition(10, "and", condition(11, "or", pushups, meditated), condition(12, "not", coffee)), date: past, values: map[int64][]string{2: {"true"}, 3: {"", "", ""}}, passed: true, state: StateMet, score: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := EvaluateRequirement(&tt.root, entriesOn(tt.date, tt.values), Calendar{WeekStart: time.Monday}, tt.date)
+			if err != nil {
+				t.Fatalf("EvaluateRequirement returned error: %v", err)
+			}
+			if result.Passed != tt.passed {
+				t.Errorf("passed = %v, want %v", result.Passed, tt.passed)
+			}
+			if result.State != tt.state {
+				t.Errorf("state = %s, want %s", result.State, tt.state)
+			}
+			if math.Abs(result.Score-tt.score) > 1e-9 {
+				t.Errorf("score = %v, want %v", result.Score, tt.score)
+			}
+		})
+	}
+}
+
+func TestAtomState(t *testing.T) {
+	tests := []struct {
+		name        string
+		aggregation string
+		operator    string
+		passed      bool
+		periodOpen  bool
+		want        string
+	}{
+		{name: "passed", operator: ">=", passed: true, periodOpen: true, want: StateMet},
+		{name: "passed after the period", operator: ">=", passed: true, want: StateMet},
+		{name: "not passed yet", operator: ">=", periodOpen: true, want: StatePending},
+		{name: "not passed in time", operator: ">=", want: StateFailed},
+		{name: "count below a minimum", aggregation: "count", operator: ">=", periodOpen: true, want: StatePending},
+		{name: "count over a limit", aggregation: "count", operator: "<=", periodOpen: true, want: StateFailed},
+		{name: "count over a strict limit", aggregation: "count", operator: "<", periodOpen: true, want: StateFailed},
+		{name: "sum over a limit", aggregation: "sum", operator: "<=", periodOpen: true, want: StateFailed},
+		{name: "maximum over a limit", aggregation: "max", operator: "<=", periodOpen: true, want: StatePending},
+		{name: "latest value over a limit", operator: "<=", periodOpen: true, want: StatePending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := atom(1, "int", tt.aggregation, tt.operator, "10")
+			if got := atomState(&r, tt.passed, tt.periodOpen); got != tt.want {
+				t.Errorf("atomState = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAtomScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		dataType string
+		operator string
+		target   string
+		value    string
+		exists   bool
+		passed   bool
+		want     float64
+	}{
+		{name: "passed", dataType: "int", operator: ">=", target: "50", value: "60", exists: true, passed: true, want: 1},
+		{name: "nothing logged", dataType: "int", operator: ">=", target: "50", want: 0},
+		{name: "below a minimum", dataType: "int", operator: ">=", target: "50", value: "45", exists: true, want: 0.9},
+		{name: "above a limit", dataType: "float", operator: "<=", target: "2000", value: "2500", exists: true, want: 0.8},
+		{name: "away from a target", dataType: "int", operator: "==", target: "10", value: "8", exists: true, want: 0.8},
+		{name: "above a target", dataType: "int", operator: "==", target: "10", value: "12.5", exists: true, want: 0.8},
+		{name: "at a strict minimum", dataType: "int", operator: ">", target: "50", value: "50", exists: true, want: 0.99},
+		{name: "duration", dataType: "duration", operator: ">=", target: "1800s", value: "15m", exists: true, want: 0.5},
+		{name: "time of day", dataType: "time", operator: "<=", target: "07:00", value: "07:30", exists: true, want: 0},
+		{name: "bool", dataType: "bool", operator: "==", target: "true", value: "false", exists: true, want: 0},
+		{name: "negative value", dataType: "int", operator: ">=", target: "50", value: "-5", exists: true, want: 0},
+		{name: "malformed value", dataType: "int", operator: ">=", target: "50", value: "abc", exists: true, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := atom(1, tt.dataType, "", tt.operator, tt.target)
+			if got := atomScore(&r, tt.value, tt.exists, tt.passed); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("atomScore = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateRequirementErrors(t *testing.T) {
+	unknown := dto.Requirement{ID: 1, Type: "group"}
+	noOperator := dto.Requirement{ID: 10, Type: "condition", Operands: []dto.Requirement{atom(1, "bool", "", "", "")}}
+
+	tests := []struct {
+		name   string
+		root   dto.Requirement
+		values map[int64][]string
+	}{
+		{name: "int value", root: atom(1, "int", "", ">=", "50"), values: map[int64][]string{1: {"abc"}}},
+		{name: "fractional int value", root: atom(1, "int", "", ">=", "50"), values: map[int64][]string{1: {"50.5"}}},
+		{name: "float value", root: atom(1, "float", "", "<=", "80.5"), values: map[int64][]string{1: {"80,5"}}},
+		{name: "duration value", root: atom(1, "duration", "", ">=", "1800s"), values: map[int64][]string{1: {"half an hour"}}},
+		{name: "bool value", root: atom(1, "bool", "", "", ""), values: map[int64][]string{1: {"maybe"}}},
+		{name: "time value", root: atom(1, "time", "", "<=", "07:00"), values: map[int64][]string{1: {"25:00"}}},
+		{name: "summed value", root: atom(1, "int", "sum", ">=", "50"), values: map[int64][]string{1: {"20", "abc"}}},
+		{name: "target", root: atom(1, "int", "", ">=", "fifty"), values: map[int64][]string{1: {"50"}}},
+		{name: "no target", root: atom(1, "int", "", ">=", ""), values: map[int64][]string{1: {"50"}}},
+		{name: "ordered bool", root: atom(1, "bool", "", ">=", "true"), values: map[int64][]string{1: {"true"}}},
+		{name: "unknown operator", root: atom(1, "int", "", "=>", "50"), values: map[int64][]string{1: {"50"}}},
+		{name: "summed time", root: atom(1, "time", "sum", "<=", "07:00"), values: map[int64][]string{1: {"06:00"}}},
+		{name: "not with two operands", root: condition(10, "not", atom(1, "bool", "", "", ""), atom(2, "bool", "", "", "")), values: map[int64][]string{1: {"true"}}},
+		{name: "condition without operands", root: condition(10, "and"), values: nil},
+		{name: "condition without operator", root: noOperator, values: map[int64][]string{1: {"true"}}},
+		{name: "unknown condition", root: condition(10, "xor", atom(1, "bool", "", "", "")), values: map[int64][]string{1: {"true"}}},
+		{name: "unknown type", root: unknown, values: nil},
+	}
+
+	date := day("2025-01-15")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := EvaluateRequirement(&tt.root, entriesOn(date, tt.values), Calendar{}, date)
+			if err == nil {
+				t.Errorf("EvaluateRequirement returned no error, passed = %v", result.Passed)
+			}
+		})
+	}
+}
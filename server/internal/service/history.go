@@ -0,0 +1,190 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/boreymarf/task-fuss/server/internal/apperrors"
+	"github.com/boreymarf/task-fuss/server/internal/db"
+	"github.com/boreymarf/task-fuss/server/internal/models"
+)
+
+// States of a day in the history of a task
+const (
+	DayCompleted = "completed"
+	DayFailed    = "failed"  // Something was logged but the task was not completed
+	DaySkipped   = "skipped" // Nothing was logged
+	DayNotDue    = "not_due"
+	DayPending   = "pending" // Today, while it can still be completed
+)
+
+// MaxHistoryDays limits the range of a history request
+const MaxHistoryDays = 3 * 366
+
+// DefaultHistoryDays is the length of a history without a start, a year for a heatmap
+const DefaultHistoryDays = 365
+
+// HistoryDay is the outcome of one day for a single task or summed up over many.
+type HistoryDay struct {
+	Date time.Time
+	// State is set for the history of a single task only
+	State string
+	// Score is the average score of the tasks due on the day, days that were skipped score 0
+	Score     float64
+	Due       int
+	Completed int
+	Failed    int
+	Skipped   int
+	Pending   int
+}
+
+// GetTaskHistory returns the day by day outcome of the task for the days of the range,
+// a zero From means the DefaultHistoryDays days up to To.
+func (s *TaskService) GetTaskHistory(taskID int64, userID int64, r Range) ([]HistoryDay, error) {
+
+	modelTask, err := s.taskRepo.GetTaskByID(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if modelTask.OwnerID != userID {
+		return nil, apperrors.ErrForbidden
+	}
+
+	days, err := s.history(userID, []models.Task{modelTask}, r)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range days {
+		day := &days[i]
+		switch {
+		case day.Completed > 0:
+			day.State = DayCompleted
+		case day.Failed > 0:
+			day.State = DayFailed
+		case day.Skipped > 0:
+			day.State = DaySkipped
+		case day.Pending > 0:
+			day.State = DayPending
+		default:
+			day.State = DayNotDue
+		}
+	}
+
+	return days, nil
+}
+
+// GetHistory returns the outcomes of all active tasks of the user summed up by day for the days of the range,
+// a zero From means the DefaultHistoryDays days up to To.
+func (s *TaskService) GetHistory(userID int64, r Range) ([]HistoryDay, error) {
+
+	modelTasks, err := s.taskRepo.GetAllTasks(&db.GetAllTasksOptions{
+		DetailLevel: "full",
+		ShowActive:  true,
+		UserID:      userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.history(userID, modelTasks, r)
+}
+
+// history classifies every day of the range up to today with the outcomes stored in task_entries,
+// so a year of many tasks takes a single query. Days after today are left out.
+func (s *TaskService) history(userID int64, modelTasks []models.Task, r Range) ([]HistoryDay, error) {
+
+	calendar, err := s.calendarFor(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	from, to, err := pastDays(calendar, r, DefaultHistoryDays)
+	if err != nil {
+		return nil, err
+	}
+	if !from.Before(to) {
+		return []HistoryDay{}, nil
+	}
+	today := calendar.today()
+
+	schedules := make([]*taskSchedule, len(modelTasks))
+	taskIDs := make([]int64, len(modelTasks))
+	for i := range modelTasks {
+		ts, err := newTaskSchedule(&modelTasks[i], calendar)
+		if err != nil {
+			return nil, err
+		}
+		schedules[i] = ts
+		taskIDs[i] = modelTasks[i].ID
+	}
+
+	entries, err := s.taskEntryRepo.GetTaskEntriesByTaskIDs(taskIDs, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	type taskDay struct {
+		taskID int64
+		day    time.Time
+	}
+	outcomes := make(map[taskDay]models.TaskEntry, len(entries))
+	for _, entry := range entries {
+		day, _ := dayBounds(entry.EntryDate)
+		outcomes[taskDay{entry.TaskID, day}] = entry
+	}
+
+	var days []HistoryDay
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		historyDay := HistoryDay{Date: day}
+		score := 0.0
+
+		for i, ts := range schedules {
+			if !ts.isDue(day) {
+				continue
+			}
+			historyDay.Due++
+
+			outcome, logged := outcomes[taskDay{taskIDs[i], day}]
+			score += outcome.Score
+			switch {
+			case outcome.Completed:
+				historyDay.Completed++
+			case day.Equal(today):
+				historyDay.Pending++
+			case logged:
+				historyDay.Failed++
+			default:
+				historyDay.Skipped++
+			}
+		}
+
+		if historyDay.Due > 0 {
+			historyDay.Score = score / float64(historyDay.Due)
+		}
+		days = append(days, historyDay)
+	}
+
+	return days, nil
+}
+
+// pastDays turns the range into the days of the calendar and cuts off the days after today.
+// A zero From means the given number of days up to To.
+func pastDays(calendar Calendar, r Range, days int) (time.Time, time.Time, error) {
+
+	from, to := calendar.days(r)
+	if tomorrow := calendar.today().AddDate(0, 0, 1); to.After(tomorrow) {
+		to = tomorrow
+	}
+
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -days)
+	}
+
+	if to.Sub(from) > MaxHistoryDays*24*time.Hour {
+		return from, to, apperrors.NewValidationError("INVALID_RANGE", "start", fmt.Sprintf("Range cannot be longer than %d days", MaxHistoryDays))
+	}
+
+	return from, to, nil
+}
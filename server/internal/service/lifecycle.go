@@ -0,0 +1,195 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/boreymarf/task-fuss/server/internal/apperrors"
+	"github.com/boreymarf/task-fuss/server/internal/dto"
+	"github.com/boreymarf/task-fuss/server/internal/logger"
+)
+
+// Statuses of a task
+const (
+	StatusActive    = "active"
+	StatusArchived  = "archived"
+	StatusCompleted = "completed"
+)
+
+// TrashRetention is how long deleted tasks stay in the trash before they are purged
+const TrashRetention = 30 * 24 * time.Hour
+
+// ArchiveTask hides an active or completed task from the default task list.
+func (s *TaskService) ArchiveTask(taskID int64, userID int64) (dto.Task, error) {
+	return s.changeTaskStatus(taskID, userID, StatusArchived, StatusActive, StatusCompleted)
+}
+
+// UnarchiveTask makes an archived task active again.
+func (s *TaskService) UnarchiveTask(taskID int64, userID int64) (dto.Task, error) {
+	return s.changeTaskStatus(taskID, userID, StatusActive, StatusArchived)
+}
+
+// CompleteTask marks the task as done for good, unlike the daily outcomes it is not evaluated.
+func (s *TaskService) CompleteTask(taskID int64, userID int64) (dto.Task, error) {
+	return s.changeTaskStatus(taskID, userID, StatusCompleted, StatusActive, StatusArchived)
+}
+
+// changeTaskStatus moves the task to status if it currently has one of from.
+// A task that already has the status is returned unchanged.
+func (s *TaskService) changeTaskStatus(taskID int64, userID int64, status string, from ...string) (dto.Task, error) {
+
+	task, err := s.taskRepo.GetTaskByID(taskID)
+	if err != nil {
+		return dto.Task{}, err
+	}
+
+	if task.OwnerID != userID {
+		return dto.Task{}, apperrors.ErrForbidden
+	}
+
+	if task.Status != status {
+		if !slices.Contains(from, task.Status) {
+			return dto.Task{}, apperrors.NewValidationError("INVALID_STATUS", "status",
+				fmt.Sprintf("Task is %s, only %s tasks can become %s", task.Status, strings.Join(from, " or "), status))
+		}
+
+		task.Status = status
+		if err := s.taskRepo.UpdateTask(&task); err != nil {
+			logger.Log.Error().Err(err).Int64("taskID", taskID).Msg("Failed to change task status")
+			return dto.Task{}, err
+		}
+	}
+
+	return s.GetTaskByID(taskID, userID, &GetTaskOptions{})
+}
+
+// DeleteTask moves the task to the trash, it can be restored until it is purged after TrashRetention.
+func (s *TaskService) DeleteTask(taskID int64, userID int64) error {
+
+	task, err := s.taskRepo.GetTaskByID(taskID)
+	if err != nil {
+		return err
+	}
+
+	if task.OwnerID != userID {
+		return apperrors.ErrForbidden
+	}
+
+	return s.taskRepo.TrashTask(taskID, userID)
+}
+
+// FIXME: Service shouldn't return dto, I'll fix it later
+func (s *TaskService) GetTrash(userID int64) ([]dto.TrashedTask, error) {
+
+	modelTasks, err := s.taskRepo.GetDeletedTasks(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.TrashedTask, 0, len(modelTasks))
+	for _, modelTask := range modelTasks {
+		result = append(result, dto.TrashedTask{
+			ID:        modelTask.ID,
+			Title:     modelTask.Title,
+			Status:    modelTask.Status,
+			DeletedAt: modelTask.DeletedAt.Time,
+			PurgeAt:   modelTask.DeletedAt.Time.Add(TrashRetention),
+		})
+	}
+
+	return result, nil
+}
+
+// RestoreTask takes the task out of the trash with everything that was logged for it.
+// Tasks of other users are reported as not found, like tasks that are not in the trash.
+func (s *TaskService) RestoreTask(taskID int64, userID int64) (dto.Task, error) {
+
+	if err := s.taskRepo.RestoreTask(taskID, userID); err != nil {
+		return dto.Task{}, err
+	}
+
+	return s.GetTaskByID(taskID, userID, &GetTaskOptions{})
+}
+
+// PurgeTask deletes a task in the trash for good without waiting for TrashRetention to pass.
+func (s *TaskService) PurgeTask(taskID int64, userID int64) error {
+
+	modelTasks, err := s.taskRepo.GetDeletedTasks(userID)
+	if err != nil {
+		return err
+	}
+
+	for _, modelTask := range modelTasks {
+		if modelTask.ID == taskID {
+			return s.purgeTasks([]int64{taskID})
+		}
+	}
+
+	return fmt.Errorf("task %d is not in the trash: %w", taskID, apperrors.ErrNotFound)
+}
+
+// PurgeTrash deletes for good every task that has been in the trash for longer than TrashRetention
+// and returns how many were deleted.
+func (s *TaskService) PurgeTrash(now time.Time) (int, error) {
+
+	modelTasks, err := s.taskRepo.GetTasksDeletedBefore(now.Add(-TrashRetention))
+	if err != nil {
+		return 0, err
+	}
+
+	var taskIDs []int64
+	for _, modelTask := range modelTasks {
+		taskIDs = append(taskIDs, modelTask.ID)
+	}
+
+	if err := s.purgeTasks(taskIDs); err != nil {
+		return 0, err
+	}
+
+	return len(taskIDs), nil
+}
+
+// PurgeTrashEvery runs PurgeTrash now and then once per interval, it never returns.
+func (s *TaskService) PurgeTrashEvery(interval time.Duration) {
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := s.PurgeTrash(time.Now())
+		if err != nil {
+			logger.Log.Error().Err(err).Msg("Failed to purge the trash")
+		} else if purged > 0 {
+			logger.Log.Info().Int("tasks", purged).Msg("Purged tasks from the trash")
+		}
+
+		<-ticker.C
+	}
+}
+
+// purgeTasks deletes the tasks with their requirements, revisions and everything logged for them.
+func (s *TaskService) purgeTasks(taskIDs []int64) error {
+
+	if len(taskIDs) == 0 {
+		return nil
+	}
+
+	// Foreign keys are not enforced, so nothing is removed by ON DELETE CASCADE
+	return s.withTx(func(tx *TaskService) error {
+		if err := tx.requirementEntryRepo.DeleteEntriesByTaskIDs(taskIDs); err != nil {
+			return err
+		}
+		if err := tx.taskEntryRepo.DeleteTaskEntriesByTaskIDs(taskIDs); err != nil {
+			return err
+		}
+		if err := tx.tagRepo.DeleteTaskTagsByTaskIDs(taskIDs); err != nil {
+			return err
+		}
+		if err := tx.requirementRepo.DeleteRequirementsByTaskIDs(taskIDs); err != nil {
+			return err
+		}
+		return tx.taskRepo.DeleteTasks(taskIDs)
+	})
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/boreymarf/task-fuss/server/internal/apperrors"
+	"github.com/boreymarf/task-fuss/server/internal/dto"
+	"github.com/boreymarf/task-fuss/server/internal/models"
+)
+
+// Keys the task list can be sorted by. Streaks are not stored, so sorting by them reads every task
+// of the user and sorts them in memory, like filtering by the due day does. The other keys are indexed
+// and only the tasks of the page are read.
+const (
+	SortByTitle     = "title"
+	SortByCreatedAt = "created_at"
+	SortByStartDate = "start_date"
+	SortByStreak    = "streak"
+)
+
+// DefaultPageSize is the number of tasks on a page when no limit is given
+const DefaultPageSize = 50
+
+// TaskPage is one page of the task list with the cursors of its neighbours.
+type TaskPage struct {
+	Tasks []dto.Task
+	// Total counts the tasks that match the filters on every page
+	Total      int
+	NextCursor *string
+	PrevCursor *string
+}
+
+// taskCursor points between two tasks of the sorted list. It keeps the sort key of the task
+// next to it instead of an offset, so pages do not shift when tasks are added or deleted.
+type taskCursor struct {
+	Sort   string `json:"s"`
+	Desc   bool   `json:"d,omitempty"`
+	Value  string `json:"v"`
+	ID     int64  `json:"id"`
+	Before bool   `json:"b,omitempty"` // Points at the page before the task instead of after it
+}
+
+// taskSortKey is the position of a task in the sorted list. value is the SortKey the repository
+// returned for the task, which compares byte by byte like SQLite does.
+type taskSortKey struct {
+	id     int64
+	value  string
+	streak int
+}
+
+func newTaskSortKey(sort string, task *models.Task, streak int) taskSortKey {
+	if sort == SortByStreak {
+		return taskSortKey{id: task.ID, streak: streak}
+	}
+	return taskSortKey{id: task.ID, value: task.SortKey}
+}
+
+// compareTaskKeys orders by the sort key, tasks with equal keys are ordered by ID.
+func compareTaskKeys(sort string, desc bool, a, b taskSortKey) int {
+	var result int
+	if sort == SortByStreak {
+		result = cmp.Compare(a.streak, b.streak)
+	} else {
+		result = strings.Compare(a.value, b.value)
+	}
+	if result == 0 {
+		result = cmp.Compare(a.id, b.id)
+	}
+	if desc {
+		return -result
+	}
+	return result
+}
+
+// pageBounds returns the part of the sorted keys the cursor points at, for lists sorted in memory.
+func pageBounds(keys []taskSortKey, sort string, desc bool, cursor *taskCursor, cursorKey taskSortKey, limit int) (int, int) {
+	if cursor == nil {
+		return 0, min(limit, len(keys))
+	}
+
+	if cursor.Before {
+		end := len(keys)
+		for i, key := range keys {
+			if compareTaskKeys(sort, desc, key, cursorKey) >= 0 {
+				end = i
+				break
+			}
+		}
+		return max(0, end-limit), end
+	}
+
+	start := len(keys)
+	for i, key := range keys {
+		if compareTaskKeys(sort, desc, key, cursorKey) > 0 {
+			start = i
+			break
+		}
+	}
+	return start, min(start+limit, len(keys))
+}
+
+func encodeTaskCursor(sort string, desc bool, key taskSortKey, before bool) *string {
+	cursor := taskCursor{Sort: sort, Desc: desc, ID: key.id, Value: key.value, Before: before}
+	if sort == SortByStreak {
+		cursor.Value = strconv.Itoa(key.streak)
+	}
+
+	data, _ := json.Marshal(cursor)
+	encoded := base64.RawURLEncoding.EncodeToString(data)
+	return &encoded
+}
+
+// decodeTaskCursor reads a cursor returned with an earlier page, it has to be for the same sort.
+func decodeTaskCursor(encoded string, sort string, desc bool) (*taskCursor, taskSortKey, error) {
+	invalid := apperrors.NewValidationError("INVALID_CURSOR", "cursor", "Cursor is malformed or was returned for another sort order")
+
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, taskSortKey{}, invalid
+	}
+
+	var cursor taskCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.Sort != sort || cursor.Desc != desc {
+		return nil, taskSortKey{}, invalid
+	}
+
+	key := taskSortKey{id: cursor.ID, value: cursor.Value}
+	if sort == SortByStreak {
+		key.value = ""
+		if key.streak, err = strconv.Atoi(cursor.Value); err != nil {
+			return nil, taskSortKey{}, invalid
+		}
+	}
+
+	return &cursor, key, nil
+}
@@ -0,0 +1,323 @@
+package service
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/boreymarf/task-fuss/server/internal/apperrors"
+	"github.com/boreymarf/task-fuss/server/internal/dto"
+)
+
+func TestPageBounds(t *testing.T) {
+	// IDs 1 to 5 sorted by title a to e
+	keys := []taskSortKey{
+		{id: 1, value: "a"},
+		{id: 2, value: "b"},
+		{id: 3, value: "c"},
+		{id: 4, value: "d"},
+		{id: 5, value: "e"},
+	}
+
+	tests := []struct {
+		name        string
+		cursor      *taskCursor
+		cursorKey   taskSortKey
+		limit       int
+		first, last int
+	}{
+		{name: "first page", limit: 2, first: 0, last: 2},
+		{name: "limit above the list", limit: 10, first: 0, last: 5},
+		{name: "after a task", cursor: &taskCursor{}, cursorKey: keys[1], limit: 2, first: 2, last: 4},
+		{name: "after the second last task", cursor: &taskCursor{}, cursorKey: keys[3], limit: 2, first: 4, last: 5},
+		{name: "after the last task", cursor: &taskCursor{}, cursorKey: keys[4], limit: 2, first: 5, last: 5},
+		{name: "before a task", cursor: &taskCursor{Before: true}, cursorKey: keys[3], limit: 2, first: 1, last: 3},
+		{name: "before the second task", cursor: &taskCursor{Before: true}, cursorKey: keys[1], limit: 2, first: 0, last: 1},
+		{name: "before the first task", cursor: &taskCursor{Before: true}, cursorKey: keys[0], limit: 2, first: 0, last: 0},
+		// The cursor keeps working when its task was deleted
+		{name: "after a deleted task", cursor: &taskCursor{}, cursorKey: taskSortKey{id: 9, value: "bb"}, limit: 2, first: 2, last: 4},
+		{name: "before a deleted task", cursor: &taskCursor{Before: true}, cursorKey: taskSortKey{id: 9, value: "bb"}, limit: 5, first: 0, last: 2},
+		// Equal keys are ordered by ID
+		{name: "after an equal key with a lower ID", cursor: &taskCursor{}, cursorKey: taskSortKey{id: 0, value: "c"}, limit: 1, first: 2, last: 3},
+		{name: "after an equal key with a higher ID", cursor: &taskCursor{}, cursorKey: taskSortKey{id: 9, value: "c"}, limit: 1, first: 3, last: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, last := pageBounds(keys, SortByTitle, false, tt.cursor, tt.cursorKey, tt.limit)
+			if first != tt.first || last != tt.last {
+				t.Errorf("pageBounds = [%d, %d), want [%d, %d)", first, last, tt.first, tt.last)
+			}
+		})
+	}
+}
+
+func TestPageBoundsDescending(t *testing.T) {
+	// Streaks 3, 2, 2, 0 sorted from the longest, equal streaks by descending ID
+	keys := []taskSortKey{
+		{id: 1, streak: 3},
+		{id: 4, streak: 2},
+		{id: 2, streak: 2},
+		{id: 3, streak: 0},
+	}
+
+	first, last := pageBounds(keys, SortByStreak, true, &taskCursor{}, keys[1], 2)
+	if first != 2 || last != 4 {
+		t.Errorf("after %v: [%d, %d), want [2, 4)", keys[1], first, last)
+	}
+
+	first, last = pageBounds(keys, SortByStreak, true, &taskCursor{Before: true}, keys[2], 2)
+	if first != 0 || last != 2 {
+		t.Errorf("before %v: [%d, %d), want [0, 2)", keys[2], first, last)
+	}
+}
+
+func TestTaskCursorRoundTrip(t *testing.T) {
+	tests := []struct {
+		sort   string
+		desc   bool
+		key    taskSortKey
+		before bool
+	}{
+		{sort: SortByTitle, key: taskSortKey{id: 7, value: "groceries"}},
+		{sort: SortByCreatedAt, desc: true, key: taskSortKey{id: 3, value: "2025-01-31 10:00:00.000"}, before: true},
+		{sort: SortByStartDate, key: taskSortKey{id: 1, value: ""}},
+		{sort: SortByStreak, desc: true, key: taskSortKey{id: 12, streak: 40}, before: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			encoded := encodeTaskCursor(tt.sort, tt.desc, tt.key, tt.before)
+
+			cursor, key, err := decodeTaskCursor(*encoded, tt.sort, tt.desc)
+			if err != nil {
+				t.Fatalf("decodeTaskCursor returned error: %v", err)
+			}
+			if key != tt.key {
+				t.Errorf("key = %+v, want %+v", key, tt.key)
+			}
+			if cursor.Before != tt.before {
+				t.Errorf("before = %v, want %v", cursor.Before, tt.before)
+			}
+		})
+	}
+}
+
+func TestDecodeTaskCursorErrors(t *testing.T) {
+	title := *encodeTaskCursor(SortByTitle, false, taskSortKey{id: 1, value: "a"}, false)
+	streak := *encodeTaskCursor(SortByStreak, false, taskSortKey{id: 1, streak: 2}, false)
+
+	tests := []struct {
+		name    string
+		encoded string
+		sort    string
+		desc    bool
+	}{
+		{name: "not base64", encoded: "%%%", sort: SortByTitle},
+		{name: "not JSON", encoded: "bm90IGpzb24", sort: SortByTitle},
+		{name: "another sort", encoded: title, sort: SortByCreatedAt},
+		{name: "another order", encoded: title, sort: SortByTitle, desc: true},
+		{name: "streak cursor for titles", encoded: streak, sort: SortByTitle},
+		{name: "streak that is not a number", encoded: rawCursor(`{"s":"streak","v":"many","id":1}`), sort: SortByStreak},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := decodeTaskCursor(tt.encoded, tt.sort, tt.desc)
+
+			var validationErr *apperrors.ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Code != "INVALID_CURSOR" || validationErr.Field != "cursor" {
+				t.Errorf("error = %v, want INVALID_CURSOR on cursor", err)
+			}
+		})
+	}
+}
+
+// rawCursor encodes the JSON as a cursor, for cursors encodeTaskCursor cannot produce.
+func rawCursor(data string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(data))
+}
+
+// walkPages reads the whole list page by page forwards, then backwards from the last page,
+// and checks the cursors of every page on the way.
+func walkPages(t *testing.T, s *TaskService, userID int64, opts GetAllTasksOptions) []int64 {
+	t.Helper()
+
+	var forward []int64
+	var pages []TaskPage
+	for {
+		page, err := s.GetAllTasks(&opts, userID)
+		if err != nil {
+			t.Fatalf("GetAllTasks returned error: %v", err)
+		}
+
+		first := len(pages) == 0
+		if (page.PrevCursor != nil) == first {
+			t.Errorf("page %d: has a previous page = %v, want %v", len(pages)+1, page.PrevCursor != nil, !first)
+		}
+		if len(page.Tasks) > opts.Limit {
+			t.Errorf("page %d has %d tasks, limit is %d", len(pages)+1, len(page.Tasks), opts.Limit)
+		}
+
+		pages = append(pages, page)
+		for _, task := range page.Tasks {
+			forward = append(forward, task.ID)
+		}
+
+		if page.NextCursor == nil {
+			break
+		}
+		if len(page.Tasks) != opts.Limit {
+			t.Errorf("page %d has %d tasks and a next page", len(pages), len(page.Tasks))
+		}
+		opts.Cursor = *page.NextCursor
+	}
+
+	for i, page := range pages {
+		if page.Total != len(forward) {
+			t.Errorf("page %d: total = %d, want %d", i+1, page.Total, len(forward))
+		}
+	}
+
+	backward := pageIDs(pages[len(pages)-1].Tasks)
+	cursor := pages[len(pages)-1].PrevCursor
+	for cursor != nil {
+		opts.Cursor = *cursor
+		page, err := s.GetAllTasks(&opts, userID)
+		if err != nil {
+			t.Fatalf("GetAllTasks returned error: %v", err)
+		}
+		if page.NextCursor == nil {
+			t.Errorf("a page read backwards has no next page")
+		}
+		backward = append(pageIDs(page.Tasks), backward...)
+		cursor = page.PrevCursor
+	}
+
+	if !slices.Equal(backward, forward) {
+		t.Errorf("pages read backwards = %v, forwards = %v", backward, forward)
+	}
+
+	return forward
+}
+
+func pageIDs(tasks []dto.Task) []int64 {
+	var ids []int64
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	return ids
+}
+
+func TestGetAllTasksPages(t *testing.T) {
+	s, userID := newTestService(t)
+
+	titles := []string{"banana", "apple", "Cherry", "date", "Apple", "elderberry", "fig"}
+	ids := make(map[string]int64)
+	for _, title := range titles {
+		ids[title] = createTestTask(t, s, userID, title, "done").ID
+	}
+
+	// Another user's tasks are never listed or counted
+	other := newTestUser(t, s, "other")
+	createTestTask(t, s, other, "aardvark", "done")
+
+	// Titles are compared without case, equal titles by ID
+	byTitle := []int64{ids["apple"], ids["Apple"], ids["banana"], ids["Cherry"], ids["date"], ids["elderberry"], ids["fig"]}
+	byID := []int64{ids["banana"], ids["apple"], ids["Cherry"], ids["date"], ids["Apple"], ids["elderberry"], ids["fig"]}
+
+	tests := []struct {
+		name string
+		sort string
+		desc bool
+		want []int64
+	}{
+		{name: "title", sort: SortByTitle, want: byTitle},
+		{name: "title descending", sort: SortByTitle, desc: true, want: reversed(byTitle)},
+		{name: "created_at", sort: SortByCreatedAt, want: byID},
+		{name: "start_date descending", sort: SortByStartDate, desc: true, want: reversed(byID)},
+	}
+
+	for _, tt := range tests {
+		for _, limit := range []int{1, 3, 7, 10} {
+			t.Run(fmt.Sprintf("%s limit %d", tt.name, limit), func(t *testing.T) {
+				got := walkPages(t, s, userID, GetAllTasksOptions{ShowActive: true, SortBy: tt.sort, Desc: tt.desc, Limit: limit})
+				if !slices.Equal(got, tt.want) {
+					t.Errorf("got %v, want %v", got, tt.want)
+				}
+			})
+		}
+	}
+}
+
+func TestGetAllTasksByStreak(t *testing.T) {
+	s, userID := newTestService(t)
+
+	var ids []int64
+	for _, title := range []string{"a", "b", "c", "d", "e"} {
+		ids = append(ids, createTestTask(t, s, userID, title, "done").ID)
+	}
+
+	// Tasks done today have a streak of one
+	today := day(time.Now().UTC().Format("2006-01-02"))
+	for _, i := range []int{1, 3} {
+		logValue(t, s, userID, atomIDs(t, s, ids[i])["done"], today, "true")
+	}
+
+	got := walkPages(t, s, userID, GetAllTasksOptions{ShowActive: true, SortBy: SortByStreak, Desc: true, Limit: 2})
+	want := []int64{ids[3], ids[1], ids[4], ids[2], ids[0]}
+	if !slices.Equal(got, want) {
+		t.Errorf("by streak = %v, want %v", got, want)
+	}
+
+	got = walkPages(t, s, userID, GetAllTasksOptions{ShowActive: true, SortBy: SortByStreak, Limit: 2})
+	want = []int64{ids[0], ids[2], ids[4], ids[1], ids[3]}
+	if !slices.Equal(got, want) {
+		t.Errorf("by streak ascending = %v, want %v", got, want)
+	}
+}
+
+func TestGetAllTasksDueOn(t *testing.T) {
+	s, userID := newTestService(t)
+
+	// A week from today falls on the same weekday, so only the weekly task on that weekday is due besides the daily ones
+	dueOn := day(time.Now().UTC().Format("2006-01-02")).AddDate(0, 0, 7)
+	weekday := []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+	rules := []*string{
+		nil,
+		ptr("FREQ=WEEKLY;BYDAY=" + weekday[dueOn.Weekday()]),
+		ptr("FREQ=WEEKLY;BYDAY=" + weekday[(dueOn.Weekday()+1)%7]),
+		nil,
+		ptr("FREQ=DAILY;INTERVAL=2"),
+		ptr("FREQ=DAILY;INTERVAL=7"),
+	}
+
+	var ids []int64
+	for i, rule := range rules {
+		task, err := s.CreateTask(&dto.CreateTaskRequest{Task: dto.Task{
+			Title:      string(rune('a' + i)),
+			Expression: ptr("done"),
+			Recurrence: rule,
+		}}, userID)
+		if err != nil {
+			t.Fatalf("failed to create task: %v", err)
+		}
+		ids = append(ids, task.ID)
+	}
+
+	got := walkPages(t, s, userID, GetAllTasksOptions{ShowActive: true, SortBy: SortByTitle, DueOn: &dueOn, Limit: 2})
+	want := []int64{ids[0], ids[1], ids[3], ids[5]}
+	if !slices.Equal(got, want) {
+		t.Errorf("due on %s = %v, want %v", dueOn.Format("2006-01-02"), got, want)
+	}
+}
+
+func reversed(ids []int64) []int64 {
+	result := slices.Clone(ids)
+	slices.Reverse(result)
+	return result
+}
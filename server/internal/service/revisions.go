@@ -0,0 +1,208 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/boreymarf/task-fuss/server/internal/apperrors"
+	"github.com/boreymarf/task-fuss/server/internal/config"
+	"github.com/boreymarf/task-fuss/server/internal/dsl"
+	"github.com/boreymarf/task-fuss/server/internal/dto"
+	"github.com/boreymarf/task-fuss/server/internal/models"
+)
+
+// taskRevision is a decoded requirement revision, it is used for days from effectiveFrom
+// until the next revision takes effect. The first revision of a task has a zero effectiveFrom,
+// it covers every day before the second one, including days before the task starts.
+type taskRevision struct {
+	effectiveFrom time.Time
+	root          *dto.Requirement
+}
+
+// taskStartDay returns the first day of the task, the first revision is listed as taking effect on it.
+func taskStartDay(task *models.Task) time.Time {
+	date := task.CreatedAt.Time
+	if task.StartDate.Valid {
+		date = task.StartDate.Time
+	}
+
+	start, _ := dayBounds(date)
+	return start
+}
+
+// currentRevision returns the current requirement tree of the task as a revision effective from the day.
+func (s *TaskService) currentRevision(task *models.Task, effectiveFrom time.Time) (models.RequirementRevision, error) {
+
+	modelRequirements, err := s.requirementRepo.GetRequirementsByTaskIDs([]int64{task.ID})
+	if err != nil {
+		return models.RequirementRevision{}, err
+	}
+
+	root, err := buildTree(modelRequirements, task.ID)
+	if err != nil {
+		return models.RequirementRevision{}, err
+	}
+
+	tree, err := json.Marshal(root)
+	if err != nil {
+		return models.RequirementRevision{}, err
+	}
+
+	return models.RequirementRevision{
+		TaskID:        task.ID,
+		EffectiveFrom: effectiveFrom,
+		Tree:          string(tree),
+	}, nil
+}
+
+// storeRevision saves the current requirement tree of the task as the revision effective from the day,
+// replacing the revision that already takes effect on that day.
+func (s *TaskService) storeRevision(task *models.Task, effectiveFrom time.Time) error {
+
+	revision, err := s.currentRevision(task, effectiveFrom)
+	if err != nil {
+		return err
+	}
+
+	return s.requirementRepo.UpsertRevision(&revision)
+}
+
+// loadRevisions returns the revisions of the task ordered by the day they take effect.
+// Tasks whose requirements have not changed since revisions were introduced have none stored,
+// their current tree is returned as the only revision, without an ID.
+func (s *TaskService) loadRevisions(task *models.Task) ([]models.RequirementRevision, error) {
+
+	revisions, err := s.requirementRepo.GetRevisionsByTaskID(task.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(revisions) > 0 {
+		return revisions, nil
+	}
+
+	revision, err := s.currentRevision(task, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	return []models.RequirementRevision{revision}, nil
+}
+
+func decodeRevision(revision models.RequirementRevision) (taskRevision, error) {
+
+	var root dto.Requirement
+	if err := json.Unmarshal([]byte(revision.Tree), &root); err != nil {
+		return taskRevision{}, fmt.Errorf("requirement revision %d has an invalid tree: %w", revision.ID, err)
+	}
+
+	effectiveFrom, _ := dayBounds(revision.EffectiveFrom)
+
+	return taskRevision{effectiveFrom: effectiveFrom, root: &root}, nil
+}
+
+// rootOn returns the requirement tree in effect on the day.
+func (tc *taskContext) rootOn(day time.Time) *dto.Requirement {
+
+	root := tc.revisions[0].root
+	for _, revision := range tc.revisions[1:] {
+		if revision.effectiveFrom.After(day) {
+			break
+		}
+		root = revision.root
+	}
+
+	return root
+}
+
+// GetTaskRevisions returns the revision history of the requirement tree of the task, oldest first.
+func (s *TaskService) GetTaskRevisions(taskID int64, userID int64) ([]dto.RequirementRevision, error) {
+
+	modelTask, err := s.taskRepo.GetTaskByID(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if modelTask.OwnerID != userID {
+		return nil, apperrors.ErrForbidden
+	}
+
+	modelRevisions, err := s.loadRevisions(&modelTask)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.RequirementRevision, 0, len(modelRevisions))
+	for i, modelRevision := range modelRevisions {
+		revision, err := decodeRevision(modelRevision)
+		if err != nil {
+			return nil, err
+		}
+
+		var until *time.Time
+		if i+1 < len(modelRevisions) {
+			next, _ := dayBounds(modelRevisions[i+1].EffectiveFrom)
+			last := next.AddDate(0, 0, -1)
+			until = &last
+		}
+
+		// The first revision is listed from the start of the task, or from its last day
+		// if the next revision took effect before the task started
+		effectiveFrom := revision.effectiveFrom
+		if effectiveFrom.IsZero() {
+			effectiveFrom = taskStartDay(&modelTask)
+			if until != nil && until.Before(effectiveFrom) {
+				effectiveFrom = *until
+			}
+		}
+
+		dtoRevision := dto.RequirementRevision{
+			ID:            modelRevision.ID,
+			EffectiveFrom: effectiveFrom.Format(config.DateFormat),
+			Requirement:   *revision.root,
+			Expression:    dsl.Format(revision.root),
+		}
+
+		if until != nil {
+			formatted := until.Format(config.DateFormat)
+			dtoRevision.EffectiveUntil = &formatted
+		}
+
+		if !modelRevision.CreatedAt.IsZero() {
+			dtoRevision.CreatedAt = &modelRevision.CreatedAt
+		}
+
+		result = append(result, dtoRevision)
+	}
+
+	return result, nil
+}
+
+// refreshTaskEntriesSince re-evaluates the stored outcomes of the task from the day containing
+// from up to today, after the requirement tree used for those days has changed. Today is always refreshed.
+func (s *TaskService) refreshTaskEntriesSince(taskID int64, from time.Time) error {
+
+	tc, err := s.loadTaskContext(taskID)
+	if err != nil {
+		return err
+	}
+
+	start, _ := dayBounds(from)
+	today := tc.calendar.today()
+
+	entries, err := s.taskEntryRepo.GetTaskEntriesByTaskIDs([]int64{taskID}, start, today)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if _, err := s.storeTaskEntry(tc, entry.EntryDate); err != nil {
+			return err
+		}
+	}
+
+	// Today is left out of the query above, it is stored when something counts for it and cleared otherwise
+	_, err = s.storeTaskEntry(tc, today)
+	return err
+}
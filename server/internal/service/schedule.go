@@ -0,0 +1,81 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/boreymarf/task-fuss/server/internal/apperrors"
+	"github.com/boreymarf/task-fuss/server/internal/models"
+	"github.com/boreymarf/task-fuss/server/internal/schedule"
+)
+
+// taskSchedule is the recurrence rule of a task bound to the days the task exists on.
+type taskSchedule struct {
+	*schedule.Schedule
+	// anchor is the first day of the task, periods of the rule are counted from it
+	anchor time.Time
+	// end is the last day of the task, nil if the task never ends
+	end *time.Time
+}
+
+// newTaskSchedule returns the schedule of the task, weeks start on the first day of the week of the calendar.
+func newTaskSchedule(task *models.Task, calendar Calendar) (*taskSchedule, error) {
+
+	ts := &taskSchedule{Schedule: schedule.Default.WithWeekStart(calendar.WeekStart)}
+
+	if task.Recurrence != nil {
+		rule, err := schedule.Parse(*task.Recurrence)
+		if err != nil {
+			return nil, fmt.Errorf("task %d has invalid recurrence: %w", task.ID, err)
+		}
+		ts.Schedule = rule.WithWeekStart(calendar.WeekStart)
+	}
+
+	switch {
+	case task.StartDate.Valid:
+		ts.anchor, _ = dayBounds(task.StartDate.Time)
+	case task.CreatedAt.Valid:
+		ts.anchor, _ = dayBounds(task.CreatedAt.Time)
+	default:
+		ts.anchor, _ = dayBounds(time.Now())
+	}
+
+	if task.EndDate.Valid {
+		end, _ := dayBounds(task.EndDate.Time)
+		ts.end = &end
+	}
+
+	return ts, nil
+}
+
+// isDue reports whether the task has to be done on the day starting at day.
+func (ts *taskSchedule) isDue(day time.Time) bool {
+	if ts.end != nil && day.After(*ts.end) {
+		return false
+	}
+	return ts.IsDue(ts.anchor, day)
+}
+
+// lastDay returns the last day up to today the task exists on.
+func (ts *taskSchedule) lastDay(today time.Time) time.Time {
+	if ts.end != nil && ts.end.Before(today) {
+		return *ts.end
+	}
+	return today
+}
+
+// normalizeRecurrence validates the rule and returns it in the canonical form.
+func normalizeRecurrence(rule *string) (*string, *apperrors.ValidationError) {
+
+	if rule == nil || *rule == "" {
+		return nil, nil
+	}
+
+	parsed, err := schedule.Parse(*rule)
+	if err != nil {
+		return nil, apperrors.NewValidationError("INVALID_RECURRENCE", "recurrence", "Recurrence rule is invalid: "+err.Error())
+	}
+
+	canonical := parsed.String()
+	return &canonical, nil
+}
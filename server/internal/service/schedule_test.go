@@ -0,0 +1,62 @@
+package service
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/boreymarf/task-fuss/server/internal/models"
+)
+
+// Weekly quotas have to use the same weeks as weekly requirements, the ones of the user's settings.
+func TestNewTaskScheduleWeekStart(t *testing.T) {
+	rule := "FREQ=WEEKLY;X-TIMES=3"
+	task := &models.Task{
+		ID:         1,
+		Recurrence: &rule,
+		StartDate:  sql.NullTime{Time: day("2025-01-01"), Valid: true},
+	}
+
+	for _, weekStart := range []time.Weekday{time.Sunday, time.Monday, time.Saturday} {
+		c := Calendar{WeekStart: weekStart}
+
+		ts, err := newTaskSchedule(task, c)
+		if err != nil {
+			t.Fatalf("newTaskSchedule returned error: %v", err)
+		}
+
+		date := day("2025-01-08")
+		start, end := ts.Period(ts.anchor, date)
+		wantStart, wantEnd := c.periodBounds("week", 0, date)
+		if !start.Equal(wantStart) || !end.Equal(wantEnd) {
+			t.Errorf("week start %v: quota period [%s, %s), requirement week [%s, %s)", weekStart,
+				start.Format("2006-01-02"), end.Format("2006-01-02"), wantStart.Format("2006-01-02"), wantEnd.Format("2006-01-02"))
+		}
+	}
+}
+
+func TestNewTaskScheduleDefault(t *testing.T) {
+	task := &models.Task{ID: 1, StartDate: sql.NullTime{Time: moment("2025-01-01T15:30:00Z"), Valid: true}}
+
+	ts, err := newTaskSchedule(task, Calendar{WeekStart: time.Sunday})
+	if err != nil {
+		t.Fatalf("newTaskSchedule returned error: %v", err)
+	}
+
+	if !ts.anchor.Equal(day("2025-01-01")) {
+		t.Errorf("anchor = %s, want 2025-01-01", ts.anchor)
+	}
+	if ts.WeekStart != time.Sunday {
+		t.Errorf("WeekStart = %v, want Sunday", ts.WeekStart)
+	}
+	if ts.isDue(day("2024-12-31")) || !ts.isDue(day("2025-01-01")) || !ts.isDue(day("2025-01-02")) {
+		t.Errorf("tasks without a rule have to be due every day from their start")
+	}
+}
+
+func TestNewTaskScheduleInvalidRule(t *testing.T) {
+	rule := "FREQ=WEEKLY;WKST=SU"
+	if _, err := newTaskSchedule(&models.Task{ID: 1, Recurrence: &rule}, Calendar{}); err == nil {
+		t.Errorf("newTaskSchedule accepted %q", rule)
+	}
+}
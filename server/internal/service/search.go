@@ -0,0 +1,49 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/boreymarf/task-fuss/server/internal/apperrors"
+	"github.com/boreymarf/task-fuss/server/internal/dto"
+)
+
+// DefaultSearchLimit is the number of results returned when no limit is given
+const DefaultSearchLimit = 20
+
+// maxSearchTerms keeps the generated queries small
+const maxSearchTerms = 10
+
+// FIXME: Service shouldn't return dto, I'll fix it later
+func (s *TaskService) Search(userID int64, query string, limit int) ([]dto.SearchResult, error) {
+
+	terms := strings.Fields(query)
+	if len(terms) == 0 {
+		return nil, apperrors.NewValidationError("EMPTY_FIELD", "q", "Field 'q' cannot be empty")
+	}
+	if len(terms) > maxSearchTerms {
+		terms = terms[:maxSearchTerms]
+	}
+
+	hits, err := s.searchRepo.Search(userID, terms, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]dto.SearchResult, 0, len(hits))
+	for _, hit := range hits {
+		result := dto.SearchResult{
+			Kind:          hit.Kind,
+			TaskID:        hit.TaskID,
+			TaskTitle:     hit.TaskTitle,
+			RequirementID: hit.RequirementID,
+			EntryID:       hit.EntryID,
+			Snippet:       hit.Snippet,
+		}
+		if hit.EntryDate.Valid {
+			result.EntryDate = &hit.EntryDate.Time
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
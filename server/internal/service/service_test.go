@@ -0,0 +1,111 @@
+package service
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/boreymarf/task-fuss/server/internal/db"
+	"github.com/boreymarf/task-fuss/server/internal/dto"
+	"github.com/boreymarf/task-fuss/server/internal/models"
+)
+
+// newTestService returns a service on a fresh database with one user in UTC whose weeks start on Monday.
+func newTestService(t *testing.T) (*TaskService, int64) {
+	t.Helper()
+
+	database, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("failed to initialize repositories: %v", err)
+		}
+	}
+
+	userRepo, err := db.InitUserRepository(database)
+	must(err)
+	taskRepo, err := db.InitTaskRepository(database)
+	must(err)
+	taskEntryRepo, err := db.InitTaskEntryRepository(database)
+	must(err)
+	requirementRepo, err := db.InitRequirementRepository(database)
+	must(err)
+	requirementEntryRepo, err := db.InitRequirementEntryRepository(database)
+	must(err)
+	tagRepo, err := db.InitTagRepository(database)
+	must(err)
+	searchRepo, err := db.InitSearchRepository(database)
+	must(err)
+	uow, err := db.InitUnitOfWork(database)
+	must(err)
+
+	s, err := InitTaskService(uow, userRepo, taskRepo, taskEntryRepo, requirementRepo, requirementEntryRepo, tagRepo, searchRepo)
+	must(err)
+
+	return s, newTestUser(t, s, "user")
+}
+
+func newTestUser(t *testing.T, s *TaskService, name string) int64 {
+	t.Helper()
+
+	user := models.User{Username: name, Email: name + "@example.com", PasswordHash: "hash"}
+	if err := s.userRepo.CreateUser(&user); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	return user.ID
+}
+
+// createTestTask creates a task from the expression, it starts today.
+func createTestTask(t *testing.T, s *TaskService, userID int64, title string, expression string) *models.Task {
+	t.Helper()
+
+	task, err := s.CreateTask(&dto.CreateTaskRequest{Task: dto.Task{
+		Title:      title,
+		Expression: &expression,
+	}}, userID)
+	if err != nil {
+		t.Fatalf("failed to create task %q: %v", title, err)
+	}
+
+	return task
+}
+
+// atomIDs maps the titles of the atoms of the task to their IDs.
+func atomIDs(t *testing.T, s *TaskService, taskID int64) map[string]int64 {
+	t.Helper()
+
+	requirements, err := s.requirementRepo.GetRequirementsByTaskIDs([]int64{taskID})
+	if err != nil {
+		t.Fatalf("failed to read requirements: %v", err)
+	}
+
+	ids := make(map[string]int64)
+	for _, requirement := range requirements {
+		if requirement.Type == "atom" && !requirement.Retired {
+			ids[requirement.Title] = requirement.ID
+		}
+	}
+	return ids
+}
+
+// logValue adds an entry for the requirement at noon UTC of the day.
+func logValue(t *testing.T, s *TaskService, userID int64, requirementID int64, date time.Time, value string) {
+	t.Helper()
+
+	entryDate := date.Add(12 * time.Hour)
+	_, _, err := s.AddRequirementEntry(requirementID, userID, &dto.CreateEntryRequest{Value: value, EntryDate: &entryDate})
+	if err != nil {
+		t.Fatalf("failed to log %s for requirement %d on %s: %v", value, requirementID, date.Format("2006-01-02"), err)
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
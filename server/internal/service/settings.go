@@ -0,0 +1,173 @@
+package service
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/boreymarf/task-fuss/server/internal/apperrors"
+	"github.com/boreymarf/task-fuss/server/internal/db"
+	"github.com/boreymarf/task-fuss/server/internal/dto"
+	"github.com/boreymarf/task-fuss/server/internal/logger"
+	"github.com/boreymarf/task-fuss/server/internal/models"
+)
+
+// UpdateSettings changes the settings of the user, omitted fields are left as they are.
+// A new time zone or day end hour moves the bounds of the days and a new week start moves
+// the bounds of weekly periods, so the stored outcomes are evaluated again.
+func (s *TaskService) UpdateSettings(userID int64, req *dto.UpdateSettingsRequest) (models.User, error) {
+
+	var user models.User
+	if err := s.userRepo.GetUserByID(userID, &user); err != nil {
+		return models.User{}, err
+	}
+
+	previous, err := userCalendar(&user)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	if req.WeekStart != nil {
+		user.WeekStart = int(parseWeekday(*req.WeekStart))
+	}
+
+	if req.Timezone != nil {
+		// An empty name and "Local" are accepted by time.LoadLocation but mean the zone of the server
+		if _, err := time.LoadLocation(*req.Timezone); err != nil || *req.Timezone == "" || *req.Timezone == "Local" {
+			return models.User{}, apperrors.NewValidationError("INVALID_TIMEZONE", "timezone", "Field 'timezone' must be an IANA time zone like 'Europe/Berlin'")
+		}
+		user.Timezone = *req.Timezone
+	}
+
+	if req.DayEndsAt != nil {
+		user.DayEndHour = *req.DayEndsAt
+	}
+
+	current, err := userCalendar(&user)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	err = s.withTx(func(tx *TaskService) error {
+		if err := tx.userRepo.UpdateSettings(&user); err != nil {
+			return err
+		}
+
+		if previous.Location.String() == current.Location.String() && previous.DayEndHour == current.DayEndHour &&
+			previous.WeekStart == current.WeekStart {
+			return nil
+		}
+
+		return tx.refreshMovedDays(userID, previous, current)
+	})
+	if err != nil {
+		logger.Log.Error().Err(err).Int64("userID", userID).Msg("Failed to update settings")
+		return models.User{}, err
+	}
+
+	return user, nil
+}
+
+// refreshMovedDays re-evaluates the outcomes of the days whose entries are put into another day
+// or another week by the current calendar. Both the day an entry belonged to and the day it belongs
+// to now are refreshed, with the following days its weekly, monthly and rolling periods give credit to.
+// Every task is loaded once, entries that stay where they were cause no work.
+func (s *TaskService) refreshMovedDays(userID int64, previous Calendar, current Calendar) error {
+
+	modelTasks, err := s.taskRepo.GetAllTasks(&db.GetAllTasksOptions{
+		DetailLevel:   "minimal",
+		ShowActive:    true,
+		ShowArchived:  true,
+		ShowCompleted: true,
+		UserID:        userID,
+	})
+	if err != nil {
+		return err
+	}
+
+	var taskIDs []int64
+	for _, modelTask := range modelTasks {
+		taskIDs = append(taskIDs, modelTask.ID)
+	}
+	if len(taskIDs) == 0 {
+		return nil
+	}
+
+	modelRequirements, err := s.requirementRepo.GetRequirementsByTaskIDs(taskIDs)
+	if err != nil {
+		return err
+	}
+
+	taskByRequirement := make(map[int64]int64)
+	var requirementIDs []int64
+	for _, requirement := range modelRequirements {
+		taskByRequirement[requirement.ID] = requirement.TaskID
+		requirementIDs = append(requirementIDs, requirement.ID)
+	}
+
+	entries, err := s.requirementEntryRepo.GetEntriesByRequirementIDs(requirementIDs, time.Time{}, time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		return err
+	}
+
+	entriesByTask := make(map[int64][]models.RequirementEntry)
+	for _, entry := range entries {
+		taskID := taskByRequirement[entry.RequirementID]
+		entriesByTask[taskID] = append(entriesByTask[taskID], entry)
+	}
+
+	today := current.today()
+
+	for _, taskID := range taskIDs {
+		if len(entriesByTask[taskID]) == 0 {
+			continue
+		}
+
+		tc, err := s.loadTaskContext(taskID)
+		if err != nil {
+			return err
+		}
+		// The same task seen with the bounds of days and weeks it was evaluated with
+		before := *tc
+		before.calendar = previous
+
+		days := make(map[time.Time]bool)
+		for _, entry := range entriesByTask[taskID] {
+			previousDay, currentDay := previous.dayOf(entry.EntryDate), current.dayOf(entry.EntryDate)
+			_, previousLast := before.periodSpan(previousDay)
+			_, currentLast := tc.periodSpan(currentDay)
+
+			if previousDay.Equal(currentDay) && previousLast.Equal(currentLast) {
+				continue
+			}
+
+			days[previousDay] = true
+			days[currentDay] = true
+			for day := previousDay; !day.After(previousLast) && !day.After(today); day = day.AddDate(0, 0, 1) {
+				days[day] = true
+			}
+			for day := currentDay; !day.After(currentLast) && !day.After(today); day = day.AddDate(0, 0, 1) {
+				days[day] = true
+			}
+		}
+
+		for _, day := range slices.SortedFunc(maps.Keys(days), time.Time.Compare) {
+			if _, err := s.storeTaskEntry(tc, day); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// parseWeekday accepts lowercase English weekday names, which are checked by the binding.
+func parseWeekday(name string) time.Weekday {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.ToLower(day.String()) == name {
+			return day
+		}
+	}
+	return time.Monday
+}
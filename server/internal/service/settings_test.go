@@ -0,0 +1,128 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/boreymarf/task-fuss/server/internal/dto"
+)
+
+// TestUpdateSettingsRefreshesMovedDays checks that after the change of settings the stored outcomes
+// are the ones a fresh evaluation with the new calendar gives, for every day around the entries.
+func TestUpdateSettingsRefreshesMovedDays(t *testing.T) {
+	today := day(time.Now().UTC().Format("2006-01-02"))
+	// A Sunday one to two weeks ago, so the week after it is over
+	sunday := today.AddDate(0, 0, -int(today.Weekday())-7)
+
+	tests := []struct {
+		name       string
+		expression string
+		settings   dto.UpdateSettingsRequest
+		value      string
+		// logged are the moments the value is logged at
+		logged []time.Time
+		// moved are days whose outcome changes, stored tells whether they have one afterwards
+		moved map[time.Time]bool
+	}{
+		{
+			name:       "day ends later",
+			expression: "meditated",
+			value:      "true",
+			settings:   dto.UpdateSettingsRequest{DayEndsAt: ptr(3)},
+			logged:     []time.Time{sunday.Add(90 * time.Minute), sunday.AddDate(0, 0, -3).Add(12 * time.Hour)},
+			moved:      map[time.Time]bool{sunday: false, sunday.AddDate(0, 0, -1): true},
+		},
+		{
+			name:       "time zone ahead of UTC",
+			expression: "meditated",
+			value:      "true",
+			settings:   dto.UpdateSettingsRequest{Timezone: ptr("Asia/Tokyo")},
+			logged:     []time.Time{sunday.Add(20 * time.Hour), sunday.AddDate(0, 0, 2).Add(12 * time.Hour)},
+			moved:      map[time.Time]bool{sunday: false, sunday.AddDate(0, 0, 1): true},
+		},
+		{
+			name:       "weekly count across the new week start",
+			expression: "count(gym) >= 2 per week",
+			value:      "true",
+			settings:   dto.UpdateSettingsRequest{WeekStart: ptr("sunday")},
+			logged:     []time.Time{sunday.AddDate(0, 0, -1).Add(12 * time.Hour), sunday.Add(12 * time.Hour)},
+			moved:      map[time.Time]bool{sunday.AddDate(0, 0, 1): true, sunday.AddDate(0, 0, 6): true},
+		},
+		{
+			name:       "rolling sum with a later day end",
+			expression: "sum(pages) >= 2 per 3 days",
+			value:      "1",
+			settings:   dto.UpdateSettingsRequest{DayEndsAt: ptr(4)},
+			logged:     []time.Time{sunday.Add(2 * time.Hour), sunday.AddDate(0, 0, 1).Add(12 * time.Hour)},
+			moved:      map[time.Time]bool{sunday.AddDate(0, 0, -1): true, sunday.AddDate(0, 0, 2): true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, userID := newTestService(t)
+			task := createTestTask(t, s, userID, "task", tt.expression)
+
+			var requirementID int64
+			for _, id := range atomIDs(t, s, task.ID) {
+				requirementID = id
+			}
+
+			for _, moment := range tt.logged {
+				if _, _, err := s.AddRequirementEntry(requirementID, userID, &dto.CreateEntryRequest{Value: tt.value, EntryDate: &moment}); err != nil {
+					t.Fatalf("failed to log at %s: %v", moment, err)
+				}
+			}
+
+			from, to := sunday.AddDate(0, 0, -7), today.AddDate(0, 0, 1)
+			stored := func() map[time.Time]bool {
+				t.Helper()
+				days, err := s.GetTaskDays(task.ID, userID, Range{From: from, To: to, FromDate: true, ToDate: true})
+				if err != nil {
+					t.Fatalf("GetTaskDays returned error: %v", err)
+				}
+				completed := make(map[time.Time]bool)
+				for _, taskDay := range days {
+					completed[taskDay.EntryDate.UTC()] = taskDay.Completed
+				}
+				return completed
+			}
+
+			before := stored()
+
+			if _, err := s.UpdateSettings(userID, &tt.settings); err != nil {
+				t.Fatalf("UpdateSettings returned error: %v", err)
+			}
+
+			after := stored()
+
+			tc, err := s.loadTaskContext(task.ID)
+			if err != nil {
+				t.Fatalf("loadTaskContext returned error: %v", err)
+			}
+			for date := from; date.Before(to); date = date.AddDate(0, 0, 1) {
+				evaluation, err := s.evaluateTaskDay(tc, date)
+				if err != nil {
+					t.Fatalf("evaluateTaskDay returned error: %v", err)
+				}
+
+				completed, ok := after[date]
+				if ok != hasValues(&evaluation.Requirement) || (ok && completed != evaluation.Passed) {
+					t.Errorf("%s: stored %v (completed %v), fresh evaluation has values %v (passed %v)",
+						date.Format("2006-01-02"), ok, completed, hasValues(&evaluation.Requirement), evaluation.Passed)
+				}
+			}
+
+			for date, want := range tt.moved {
+				_, hadOutcome := before[date]
+				_, hasOutcome := after[date]
+				if hasOutcome != want {
+					t.Errorf("%s: outcome stored %v, want %v", date.Format("2006-01-02"), hasOutcome, want)
+				}
+				if hadOutcome == hasOutcome && before[date] == after[date] {
+					t.Errorf("%s: outcome did not change", date.Format("2006-01-02"))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"time"
+
+	"github.com/boreymarf/task-fuss/server/internal/dto"
+	"github.com/boreymarf/task-fuss/server/internal/models"
+)
+
+// DefaultStatsWindow is the number of days the completion rate is calculated over
+const DefaultStatsWindow = 30
+
+// loadTaskStats calculates streaks and completion metrics for every task from task_entries.
+func (s *TaskService) loadTaskStats(userID int64, modelTasks []models.Task, window int) (map[int64]*dto.TaskStats, error) {
+
+	calendar, err := s.calendarFor(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var taskIDs []int64
+	for _, modelTask := range modelTasks {
+		taskIDs = append(taskIDs, modelTask.ID)
+	}
+
+	today := calendar.today()
+	tomorrow := today.AddDate(0, 0, 1)
+
+	entries, err := s.taskEntryRepo.GetTaskEntriesByTaskIDs(taskIDs, time.Time{}, tomorrow)
+	if err != nil {
+		return nil, err
+	}
+
+	completedByTask := make(map[int64]map[time.Time]bool)
+	for _, entry := range entries {
+		if !entry.Completed {
+			continue
+		}
+		if completedByTask[entry.TaskID] == nil {
+			completedByTask[entry.TaskID] = make(map[time.Time]bool)
+		}
+		day, _ := dayBounds(entry.EntryDate)
+		completedByTask[entry.TaskID][day] = true
+	}
+
+	stats := make(map[int64]*dto.TaskStats)
+	for i := range modelTasks {
+		ts, err := newTaskSchedule(&modelTasks[i], calendar)
+		if err != nil {
+			return nil, err
+		}
+
+		stats[modelTasks[i].ID] = computeTaskStats(completedByTask[modelTasks[i].ID], ts, today, window)
+	}
+
+	return stats, nil
+}
+
+// computeTaskStats calculates metrics from the set of completed days.
+// Only due days are counted, days the task is not scheduled for neither extend nor break a streak.
+// Today does not break the current streak until it is over.
+func computeTaskStats(completed map[time.Time]bool, ts *taskSchedule, today time.Time, window int) *dto.TaskStats {
+
+	stats := &dto.TaskStats{
+		WindowDays: window,
+	}
+
+	lastDay := ts.lastDay(today)
+	windowStart := today.AddDate(0, 0, -(window - 1))
+
+	// Outcomes are stored for days the task was not due too, they do not count
+	for day := range completed {
+		if !day.Before(ts.anchor) && !day.After(lastDay) && ts.isDue(day) {
+			stats.CompletedDays++
+		}
+	}
+
+	if ts.IsQuota() {
+		computePeriodStats(stats, completed, ts, today, lastDay, windowStart)
+		return stats
+	}
+
+	stats.StreakUnit = "day"
+
+	var dueDays []time.Time
+	for day := ts.anchor; !day.After(lastDay); day = day.AddDate(0, 0, 1) {
+		if ts.isDue(day) {
+			dueDays = append(dueDays, day)
+		}
+	}
+
+	// Current streak
+	for i := len(dueDays) - 1; i >= 0; i-- {
+		if completed[dueDays[i]] {
+			stats.CurrentStreak++
+		} else if !dueDays[i].Equal(today) {
+			break
+		}
+	}
+
+	// Longest streak
+	run := 0
+	for _, day := range dueDays {
+		if completed[day] {
+			run++
+			stats.LongestStreak = max(stats.LongestStreak, run)
+		} else {
+			run = 0
+		}
+	}
+
+	// Completion rate over the due days of the window
+	days := 0
+	completedInWindow := 0
+	for _, day := range dueDays {
+		if day.Before(windowStart) {
+			continue
+		}
+		days++
+		if completed[day] {
+			completedInWindow++
+		}
+	}
+
+	if days > 0 {
+		stats.CompletionRate = float64(completedInWindow) / float64(days)
+	}
+
+	return stats
+}
+
+// computePeriodStats calculates metrics of a quota schedule, where every period is met
+// when the task is completed on enough of its days. Streaks are counted in periods.
+// The current period does not break the streak until it is over.
+func computePeriodStats(stats *dto.TaskStats, completed map[time.Time]bool, ts *taskSchedule, today time.Time, lastDay time.Time, windowStart time.Time) {
+
+	stats.StreakUnit = "period"
+
+	type period struct {
+		start, end time.Time
+		met        bool
+	}
+
+	var periods []period
+	for start, end := ts.Period(ts.anchor, ts.anchor); !start.After(lastDay); start, end = ts.Period(ts.anchor, end) {
+		count := 0
+		for day := start; day.Before(end) && !day.After(lastDay); day = day.AddDate(0, 0, 1) {
+			if completed[day] && ts.isDue(day) {
+				count++
+			}
+		}
+		periods = append(periods, period{start: start, end: end, met: count >= ts.Times})
+	}
+
+	inProgress := func(p period) bool {
+		return today.Before(p.end)
+	}
+
+	// Current streak
+	for i := len(periods) - 1; i >= 0; i-- {
+		if periods[i].met {
+			stats.CurrentStreak++
+		} else if !inProgress(periods[i]) {
+			break
+		}
+	}
+
+	// Longest streak
+	run := 0
+	for _, p := range periods {
+		if p.met {
+			run++
+			stats.LongestStreak = max(stats.LongestStreak, run)
+		} else {
+			run = 0
+		}
+	}
+
+	// Completion rate over the periods that overlap the window, unfinished periods only count once met
+	total := 0
+	met := 0
+	for _, p := range periods {
+		if !p.end.After(windowStart) || (!p.met && inProgress(p)) {
+			continue
+		}
+		total++
+		if p.met {
+			met++
+		}
+	}
+
+	if total > 0 {
+		stats.CompletionRate = float64(met) / float64(total)
+	}
+}
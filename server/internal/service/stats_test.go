@@ -0,0 +1,122 @@
+package service
+
+import (
+	"database/sql"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/boreymarf/task-fuss/server/internal/models"
+)
+
+func TestComputeTaskStats(t *testing.T) {
+	tests := []struct {
+		name      string
+		rule      string
+		start     string
+		today     string
+		window    int
+		completed []string
+		unit      string
+		current   int
+		longest   int
+		days      int
+		rate      float64
+	}{
+		{
+			name:  "streak broken by a missed day",
+			start: "2025-01-01", today: "2025-01-10", window: 10,
+			completed: []string{"2025-01-01", "2025-01-02", "2025-01-03", "2025-01-04", "2025-01-06", "2025-01-07", "2025-01-08", "2025-01-09", "2025-01-10"},
+			unit:      "day", current: 5, longest: 5, days: 9, rate: 0.9,
+		},
+		{
+			name:  "today does not break the streak yet",
+			start: "2025-01-01", today: "2025-01-10", window: 10,
+			completed: []string{"2025-01-07", "2025-01-08", "2025-01-09"},
+			unit:      "day", current: 3, longest: 3, days: 3, rate: 0.3,
+		},
+		{
+			name:  "yesterday breaks the streak",
+			start: "2025-01-01", today: "2025-01-10", window: 10,
+			completed: []string{"2025-01-07", "2025-01-08"},
+			unit:      "day", current: 0, longest: 2, days: 2, rate: 0.2,
+		},
+		{
+			// The weekend in between is not due, the Saturday it was done anyway does not count
+			name: "streak kept across days that are not due",
+			rule: "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR", start: "2025-01-06", today: "2025-01-14", window: 30,
+			completed: []string{"2025-01-06", "2025-01-07", "2025-01-08", "2025-01-09", "2025-01-10", "2025-01-11", "2025-01-13", "2025-01-14"},
+			unit:      "day", current: 7, longest: 7, days: 7, rate: 1,
+		},
+		{
+			name: "missed due day after days that are not due",
+			rule: "FREQ=WEEKLY;BYDAY=MO,WE,FR", start: "2025-01-06", today: "2025-01-15", window: 30,
+			completed: []string{"2025-01-06", "2025-01-08", "2025-01-10", "2025-01-15"},
+			unit:      "day", current: 1, longest: 3, days: 4, rate: 0.8,
+		},
+		{
+			name:  "completion rate over the window only",
+			start: "2025-01-01", today: "2025-01-20", window: 10,
+			completed: []string{"2025-01-01", "2025-01-02", "2025-01-03", "2025-01-04", "2025-01-05", "2025-01-06", "2025-01-07", "2025-01-08", "2025-01-09", "2025-01-10", "2025-01-15", "2025-01-20"},
+			unit:      "day", current: 1, longest: 10, days: 12, rate: 0.2,
+		},
+		{
+			name:  "window longer than the task",
+			start: "2025-01-06", today: "2025-01-10", window: 30,
+			completed: []string{"2025-01-05", "2025-01-06", "2025-01-07", "2025-01-08"},
+			unit:      "day", current: 0, longest: 3, days: 3, rate: 0.6,
+		},
+		{
+			name: "quota streak kept by the week in progress",
+			rule: "FREQ=WEEKLY;X-TIMES=3", start: "2025-01-06", today: "2025-01-22", window: 30,
+			completed: []string{"2025-01-06", "2025-01-07", "2025-01-08", "2025-01-13", "2025-01-15", "2025-01-17", "2025-01-20"},
+			unit:      "period", current: 2, longest: 2, days: 7, rate: 1,
+		},
+		{
+			name: "quota streak broken by a week short of the quota",
+			rule: "FREQ=WEEKLY;X-TIMES=3", start: "2025-01-06", today: "2025-01-22", window: 30,
+			completed: []string{"2025-01-06", "2025-01-07", "2025-01-08", "2025-01-13", "2025-01-15", "2025-01-20", "2025-01-21", "2025-01-22"},
+			unit:      "period", current: 1, longest: 1, days: 8, rate: 2.0 / 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &models.Task{ID: 1, StartDate: sql.NullTime{Time: day(tt.start), Valid: true}}
+			if tt.rule != "" {
+				task.Recurrence = &tt.rule
+			}
+
+			ts, err := newTaskSchedule(task, Calendar{WeekStart: time.Monday})
+			if err != nil {
+				t.Fatalf("newTaskSchedule returned error: %v", err)
+			}
+
+			completed := make(map[time.Time]bool)
+			for _, date := range tt.completed {
+				completed[day(date)] = true
+			}
+
+			stats := computeTaskStats(completed, ts, day(tt.today), tt.window)
+
+			if stats.StreakUnit != tt.unit {
+				t.Errorf("StreakUnit = %q, want %q", stats.StreakUnit, tt.unit)
+			}
+			if stats.CurrentStreak != tt.current {
+				t.Errorf("CurrentStreak = %d, want %d", stats.CurrentStreak, tt.current)
+			}
+			if stats.LongestStreak != tt.longest {
+				t.Errorf("LongestStreak = %d, want %d", stats.LongestStreak, tt.longest)
+			}
+			if stats.CompletedDays != tt.days {
+				t.Errorf("CompletedDays = %d, want %d", stats.CompletedDays, tt.days)
+			}
+			if math.Abs(stats.CompletionRate-tt.rate) > 1e-9 {
+				t.Errorf("CompletionRate = %v, want %v", stats.CompletionRate, tt.rate)
+			}
+			if stats.WindowDays != tt.window {
+				t.Errorf("WindowDays = %d, want %d", stats.WindowDays, tt.window)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/boreymarf/task-fuss/server/internal/apperrors"
+	"github.com/boreymarf/task-fuss/server/internal/dto"
+	"github.com/boreymarf/task-fuss/server/internal/logger"
+	"github.com/boreymarf/task-fuss/server/internal/models"
+)
+
+var colorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// maxTagNameLength keeps tag names short enough to be shown as labels
+const maxTagNameLength = 50
+
+// FIXME: Service shouldn't return dto, I'll fix it later
+func (s *TaskService) CreateTag(userID int64, req *dto.CreateTagRequest) (dto.Tag, error) {
+
+	tag := models.Tag{OwnerID: userID}
+
+	errs := applyTagFields(&tag, &req.Name, req.Color)
+	if len(errs) > 0 {
+		return dto.Tag{}, errs
+	}
+
+	if err := s.tagRepo.CreateTag(&tag); err != nil {
+		return dto.Tag{}, duplicateTagError(err, tag.Name)
+	}
+
+	return toDtoTag(tag), nil
+}
+
+// GetTags returns every tag of the user ordered by name.
+func (s *TaskService) GetTags(userID int64) ([]dto.Tag, error) {
+
+	tags, err := s.tagRepo.GetTagsByOwnerID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDtoTags(tags), nil
+}
+
+// UpdateTag renames or recolors the tag, the tasks keep it.
+func (s *TaskService) UpdateTag(tagID int64, userID int64, req *dto.UpdateTagRequest) (dto.Tag, error) {
+
+	tag, err := s.getOwnedTag(tagID, userID)
+	if err != nil {
+		return dto.Tag{}, err
+	}
+
+	errs := applyTagFields(&tag, req.Name, req.Color)
+	if len(errs) > 0 {
+		return dto.Tag{}, errs
+	}
+
+	if err := s.tagRepo.UpdateTag(&tag); err != nil {
+		return dto.Tag{}, duplicateTagError(err, tag.Name)
+	}
+
+	return toDtoTag(tag), nil
+}
+
+// DeleteTag deletes the tag and removes it from every task, the tasks themselves are kept.
+func (s *TaskService) DeleteTag(tagID int64, userID int64) error {
+
+	if _, err := s.getOwnedTag(tagID, userID); err != nil {
+		return err
+	}
+
+	return s.withTx(func(tx *TaskService) error {
+		return tx.tagRepo.DeleteTag(tagID)
+	})
+}
+
+func (s *TaskService) getOwnedTag(tagID int64, userID int64) (models.Tag, error) {
+
+	tag, err := s.tagRepo.GetTagByID(tagID)
+	if err != nil {
+		return models.Tag{}, err
+	}
+
+	if tag.OwnerID != userID {
+		return models.Tag{}, apperrors.ErrForbidden
+	}
+
+	return tag, nil
+}
+
+// applyTagFields sets the fields that are not nil, an empty color clears it.
+func applyTagFields(tag *models.Tag, name *string, color *string) apperrors.ValidationErrors {
+	var errs apperrors.ValidationErrors
+
+	if name != nil {
+		tag.Name = strings.TrimSpace(*name)
+		if tag.Name == "" {
+			errs = append(errs, apperrors.NewValidationError("EMPTY_FIELD", "name", "Field 'name' cannot be empty"))
+		} else if len([]rune(tag.Name)) > maxTagNameLength {
+			errs = append(errs, apperrors.NewValidationError("INVALID_NAME", "name", fmt.Sprintf("Field 'name' cannot be longer than %d characters", maxTagNameLength)))
+		}
+	}
+
+	if color != nil {
+		switch {
+		case *color == "":
+			tag.Color = nil
+		case colorPattern.MatchString(*color):
+			normalized := strings.ToLower(*color)
+			tag.Color = &normalized
+		default:
+			errs = append(errs, apperrors.NewValidationError("INVALID_COLOR", "color", "Field 'color' must be a hex color like #4caf50"))
+		}
+	}
+
+	return errs
+}
+
+func duplicateTagError(err error, name string) error {
+	if errors.Is(err, apperrors.ErrDuplicate) {
+		return apperrors.NewValidationError("DUPLICATE_NAME", "name", fmt.Sprintf("Tag '%s' already exists", name))
+	}
+	logger.Log.Error().Err(err).Msg("Failed to store tag")
+	return err
+}
+
+// validateTagIDs checks that every tag belongs to the user.
+func (s *TaskService) validateTagIDs(tagIDs []int64, userID int64, field string) (apperrors.ValidationErrors, error) {
+
+	if len(tagIDs) == 0 {
+		return nil, nil
+	}
+
+	tags, err := s.tagRepo.GetTagsByOwnerID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	owned := make(map[int64]bool)
+	for _, tag := range tags {
+		owned[tag.ID] = true
+	}
+
+	var errs apperrors.ValidationErrors
+	for i, tagID := range tagIDs {
+		if !owned[tagID] {
+			errs = append(errs, apperrors.NewValidationError("INVALID_TAG", fmt.Sprintf("%s[%d]", field, i), fmt.Sprintf("Tag %d does not exist", tagID)))
+		}
+	}
+
+	return errs, nil
+}
+
+func toDtoTag(tag models.Tag) dto.Tag {
+	return dto.Tag{
+		ID:    tag.ID,
+		Name:  tag.Name,
+		Color: tag.Color,
+	}
+}
+
+func toDtoTags(tags []models.Tag) []dto.Tag {
+	result := make([]dto.Tag, 0, len(tags))
+	for _, tag := range tags {
+		result = append(result, toDtoTag(tag))
+	}
+	return result
+}
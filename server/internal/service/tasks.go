@@ -1,76 +1,203 @@
 package service
 
 import (
+	"database/sql"
 	"fmt"
+	"slices"
 	"sort"
+	"time"
 
 	"github.com/boreymarf/task-fuss/server/internal/apperrors"
 	"github.com/boreymarf/task-fuss/server/internal/db"
+	"github.com/boreymarf/task-fuss/server/internal/dsl"
 	"github.com/boreymarf/task-fuss/server/internal/dto"
+	"github.com/boreymarf/task-fuss/server/internal/duration"
 	"github.com/boreymarf/task-fuss/server/internal/logger"
 	"github.com/boreymarf/task-fuss/server/internal/models"
+	"github.com/boreymarf/task-fuss/server/internal/units"
 )
 
 type TaskService struct {
+	uow                  *db.UnitOfWork
+	userRepo             *db.UserRepository
 	taskRepo             *db.TaskRepository
 	taskEntryRepo        *db.TaskEntryRepository
 	requirementRepo      *db.RequirementRepository
 	requirementEntryRepo *db.RequirementEntryRepository
+	tagRepo              *db.TagRepository
+	searchRepo           *db.SearchRepository
 }
 
 func InitTaskService(
+	uow *db.UnitOfWork,
+	userRepo *db.UserRepository,
 	taskRepo *db.TaskRepository,
 	taskEntryRepo *db.TaskEntryRepository,
 	requirementRepo *db.RequirementRepository,
 	requirementEntryRepo *db.RequirementEntryRepository,
+	tagRepo *db.TagRepository,
+	searchRepo *db.SearchRepository,
 ) (*TaskService, error) {
 
 	repo := &TaskService{
+		uow:                  uow,
+		userRepo:             userRepo,
 		taskRepo:             taskRepo,
 		taskEntryRepo:        taskEntryRepo,
 		requirementRepo:      requirementRepo,
 		requirementEntryRepo: requirementEntryRepo,
+		tagRepo:              tagRepo,
+		searchRepo:           searchRepo,
 	}
 
 	return repo, nil
 }
 
+// withTx runs fn with a copy of the service whose repositories share one transaction.
+// Everything fn does is committed together or rolled back if it returns an error.
+// Calls from inside fn reuse the transaction that is already open.
+func (s *TaskService) withTx(fn func(tx *TaskService) error) error {
+
+	if s.uow == nil {
+		return fn(s)
+	}
+
+	return s.uow.Do(func(tx *sql.Tx) error {
+		return fn(&TaskService{
+			userRepo:             s.userRepo.WithTx(tx),
+			taskRepo:             s.taskRepo.WithTx(tx),
+			taskEntryRepo:        s.taskEntryRepo.WithTx(tx),
+			requirementRepo:      s.requirementRepo.WithTx(tx),
+			requirementEntryRepo: s.requirementEntryRepo.WithTx(tx),
+			tagRepo:              s.tagRepo.WithTx(tx),
+			searchRepo:           s.searchRepo,
+		})
+	})
+}
+
 func (s *TaskService) CreateTask(req *dto.CreateTaskRequest, user_id int64) (*models.Task, error) {
 
 	logger.Log.Debug().Msg("Trying to Create new task")
 
+	var errs apperrors.ValidationErrors
+
 	if req.Task.Title == "" {
-		return nil, apperrors.NewValidationError("EMPTY_FIELD", "title", "Field 'title' cannot be empty")
+		errs = append(errs, apperrors.NewValidationError("EMPTY_FIELD", "title", "Field 'title' cannot be empty"))
+	}
+
+	if req.Task.Requirement == nil && req.Task.Expression != nil {
+		requirement, err := dsl.Parse(*req.Task.Expression)
+		if err != nil {
+			return nil, err
+		}
+		req.Task.Requirement = requirement
 	}
-	// TODO: This can fail I think
+
 	if req.Task.Requirement == nil {
-		return nil, apperrors.NewValidationError("EMPTY_FIELD", "requirement", "Field 'requirement' cannot be empty")
+		errs = append(errs, apperrors.NewValidationError("EMPTY_FIELD", "requirement", "Field 'requirement' cannot be empty"))
+	} else {
+		errs = append(errs, validateRequirement(req.Task.Requirement, "requirement")...)
+	}
+
+	recurrence, recurrenceErr := normalizeRecurrence(req.Task.Recurrence)
+	if recurrenceErr != nil {
+		errs = append(errs, recurrenceErr)
+	}
+
+	tagErrs, err := s.validateTagIDs(req.Task.TagIDs, user_id, "tag_ids")
+	if err != nil {
+		return nil, err
 	}
+	errs = append(errs, tagErrs...)
 
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	calendar, err := s.calendarFor(user_id)
+	if err != nil {
+		return nil, err
+	}
+
+	// The task starts on the day of the user, which is not always the day in UTC
 	task := models.Task{
 		OwnerID:     user_id,
 		Title:       req.Task.Title,
 		Description: req.Task.Description,
+		Recurrence:  recurrence,
+		StartDate:   sql.NullTime{Time: calendar.today(), Valid: true},
 	}
 
-	createdTask, err := s.taskRepo.CreateTask(task)
-	if err != nil {
-		logger.Log.Error().Err(err).Msg("Failed to Create new task")
-		return nil, err
-	}
+	var createdTask *models.Task
 
-	logger.Log.Debug().Msg("Now trying to Create requirements of the task...")
+	// The task and its requirement tree are stored together or not at all
+	err = s.withTx(func(tx *TaskService) error {
+		var err error
 
-	if err := s.CreateRequirement(req.Task.Requirement, task.ID, nil); err != nil {
+		createdTask, err = tx.taskRepo.CreateTask(task)
+		if err != nil {
+			logger.Log.Error().Err(err).Msg("Failed to Create new task")
+			return err
+		}
+
+		logger.Log.Debug().Msg("Now trying to Create requirements of the task...")
+
+		if err := tx.createRequirement(req.Task.Requirement, createdTask.ID, nil); err != nil {
+			return err
+		}
+
+		if err := tx.tagRepo.SetTaskTags(createdTask.ID, req.Task.TagIDs); err != nil {
+			return err
+		}
+
+		return tx.storeRevision(createdTask, time.Time{})
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return createdTask, nil
 }
 
+// CreateRequirement validates the requirement tree and stores it as a child of parent_id,
+// or as the root of the task when parent_id is nil.
 func (s *TaskService) CreateRequirement(requirement *dto.Requirement, task_id int64, parent_id *int64) error {
 
+	if errs := validateRequirement(requirement, "requirement"); len(errs) > 0 {
+		return errs
+	}
+
+	return s.createRequirement(requirement, task_id, parent_id)
+}
+
+func (s *TaskService) createRequirement(requirement *dto.Requirement, task_id int64, parent_id *int64) error {
+
+	r := toModelRequirement(requirement, task_id, parent_id)
+
+	// Returns id after
+	if err := s.requirementRepo.CreateRequirement(&r); err != nil {
+		return err
+	}
+
+	logger.Log.Debug().Str("title", requirement.Title).Msg("Created requirement to the db!")
+
+	if requirement.Type == "condition" {
+		for i := range requirement.Operands {
+			if err := s.createRequirement(&requirement.Operands[i], task_id, &r.ID); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+
+}
+
+// toModelRequirement converts a single node of the tree, values are stored in their canonical form.
+func toModelRequirement(requirement *dto.Requirement, task_id int64, parent_id *int64) models.Requirement {
+
 	r := models.Requirement{
+		ID:          requirement.ID,
 		TaskID:      task_id,
 		ParentID:    parent_id,
 		Title:       requirement.Title,
@@ -78,33 +205,45 @@ func (s *TaskService) CreateRequirement(requirement *dto.Requirement, task_id in
 		DataType:    requirement.DataType,
 		Operator:    requirement.Operator,
 		TargetValue: requirement.TargetValue,
+		Options:     requirement.Options,
+		Unit:        requirement.Unit,
+		Aggregation: requirement.Aggregation,
+		Period:      requirement.Period,
+		PeriodDays:  requirement.PeriodDays,
+		ScoreMode:   requirement.ScoreMode,
 		Value:       requirement.Value,
 		SortOrder:   requirement.SortOrder,
 	}
 
-	// Returns id after
-	if err := s.requirementRepo.CreateRequirement(&r); err != nil {
-		return err
+	// Durations are stored as seconds, so "1h30m" and "PT1H30M" end up the same
+	if r.DataType != nil && *r.DataType == "duration" && r.TargetValue != nil {
+		if d, err := duration.Parse(*r.TargetValue); err == nil {
+			canonical := duration.Canonical(d)
+			r.TargetValue = &canonical
+		}
 	}
 
-	logger.Log.Debug().Str("title", requirement.Title).Msg("Created requirement to the db!")
-
-	if requirement.Type == "condition" {
-		for _, operand := range requirement.Operands {
-			s.CreateRequirement(&operand, task_id, &r.ID)
+	// Units are stored by their symbol, "miles" becomes "mi"
+	if r.Unit != nil {
+		if unit, ok := units.Lookup(*r.Unit); ok {
+			r.Unit = &unit.Symbol
 		}
 	}
 
-	return nil
+	return r
+}
 
+type GetTaskOptions struct {
+	WithStats   bool
+	StatsWindow int
 }
 
 // FIXME: Service should not return DTO, I'll fix it later
-func (s *TaskService) GetTaskByID(taskID int64, userID int64) (dto.Task, error) {
+func (s *TaskService) GetTaskByID(taskID int64, userID int64, opts *GetTaskOptions) (dto.Task, error) {
 
 	modelTask, err := s.taskRepo.GetTaskByID(taskID)
 	if err != nil {
-		return dto.Task{}, nil
+		return dto.Task{}, err
 	}
 
 	if modelTask.OwnerID != userID {
@@ -117,10 +256,18 @@ func (s *TaskService) GetTaskByID(taskID int64, userID int64) (dto.Task, error)
 		return dto.Task{}, err
 	}
 
+	tagsByTask, err := s.tagRepo.GetTagsByTaskIDs([]int64{taskID})
+	if err != nil {
+		return dto.Task{}, err
+	}
+
 	dtoTask := dto.Task{
 		ID:          modelTask.ID,
 		Title:       modelTask.Title,
 		Description: modelTask.Description,
+		Status:      modelTask.Status,
+		Recurrence:  modelTask.Recurrence,
+		Tags:        toDtoTags(tagsByTask[taskID]),
 	}
 
 	if modelTask.CreatedAt.Valid {
@@ -143,31 +290,469 @@ func (s *TaskService) GetTaskByID(taskID int64, userID int64) (dto.Task, error)
 
 	dtoTask.Requirement = dtoRequirement
 
+	expression := dsl.Format(dtoRequirement)
+	dtoTask.Expression = &expression
+
+	if opts.WithStats {
+		stats, err := s.loadTaskStats(userID, []models.Task{modelTask}, opts.StatsWindow)
+		if err != nil {
+			return dto.Task{}, err
+		}
+		dtoTask.Stats = stats[modelTask.ID]
+	}
+
 	return dtoTask, nil
 }
 
+// EvaluateTask evaluates the requirement tree of the task against the entries logged on the given date,
+// nil is today of the user.
+func (s *TaskService) EvaluateTask(taskID int64, userID int64, date *time.Time) (*TaskEvaluation, error) {
+
+	modelTask, err := s.taskRepo.GetTaskByID(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if modelTask.OwnerID != userID {
+		return nil, apperrors.ErrForbidden
+	}
+
+	tc, err := s.loadTaskContext(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	day := tc.calendar.today()
+	if date != nil {
+		day = *date
+	}
+
+	return s.evaluateTaskDay(tc, day)
+}
+
+// GetTaskDays returns the stored daily outcomes of the task for days in [from, to).
+// Days nothing was logged on have no outcome.
+func (s *TaskService) GetTaskDays(taskID int64, userID int64, r Range) ([]models.TaskEntry, error) {
+
+	modelTask, err := s.taskRepo.GetTaskByID(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if modelTask.OwnerID != userID {
+		return nil, apperrors.ErrForbidden
+	}
+
+	calendar, err := s.calendarFor(userID)
+	if err != nil {
+		return nil, err
+	}
+	from, to := calendar.days(r)
+
+	return s.taskEntryRepo.GetTaskEntriesByTaskIDs([]int64{taskID}, from, to)
+}
+
+// taskContext is everything needed to evaluate a task for any day.
+type taskContext struct {
+	task     models.Task
+	schedule *taskSchedule
+	calendar Calendar
+	// revisions are ordered by the day they take effect, there is at least one
+	revisions []taskRevision
+}
+
+func (s *TaskService) loadTaskContext(taskID int64) (*taskContext, error) {
+
+	modelTask, err := s.taskRepo.GetTaskByID(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar, err := s.calendarFor(modelTask.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	ts, err := newTaskSchedule(&modelTask, calendar)
+	if err != nil {
+		return nil, err
+	}
+
+	modelRevisions, err := s.loadRevisions(&modelTask)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := make([]taskRevision, 0, len(modelRevisions))
+	for _, modelRevision := range modelRevisions {
+		revision, err := decodeRevision(modelRevision)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+
+	return &taskContext{
+		task:      modelTask,
+		schedule:  ts,
+		calendar:  calendar,
+		revisions: revisions,
+	}, nil
+}
+
+// periodSpan returns the first day whose entries count for the day starting at day,
+// and the last day that entries logged on day count for. Periods of every revision are
+// taken into account, so the span covers the day whichever revision is in effect.
+func (tc *taskContext) periodSpan(day time.Time) (time.Time, time.Time) {
+
+	first, last := day, day
+
+	var walk func(r *dto.Requirement)
+	walk = func(r *dto.Requirement) {
+		for i := range r.Operands {
+			walk(&r.Operands[i])
+		}
+		if r.Type != "atom" {
+			return
+		}
+
+		period, periodDays := atomPeriod(r)
+		start, end := tc.calendar.periodBounds(period, periodDays, day)
+		if start.Before(first) {
+			first = start
+		}
+
+		affected := end.AddDate(0, 0, -1)
+		if period == "rolling" {
+			affected = day.AddDate(0, 0, periodDays-1)
+		}
+		if affected.After(last) {
+			last = affected
+		}
+	}
+	for _, revision := range tc.revisions {
+		walk(revision.root)
+	}
+
+	return first, last
+}
+
+func (s *TaskService) evaluateTaskDay(tc *taskContext, date time.Time) (*TaskEvaluation, error) {
+
+	start, next := dayBounds(date)
+	from, _ := tc.periodSpan(start)
+
+	root := tc.rootOn(start)
+
+	entries, err := s.requirementEntryRepo.GetEntriesByRequirementIDs(collectRequirementIDs(root), tc.calendar.dayStart(from), tc.calendar.dayStart(next))
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := EvaluateRequirement(root, entries, tc.calendar, start)
+	if err != nil {
+		logger.Log.Error().Err(err).Int64("taskID", tc.task.ID).Msg("Failed to evaluate requirement tree")
+		return nil, err
+	}
+
+	return &TaskEvaluation{
+		TaskID:      tc.task.ID,
+		Date:        start,
+		Due:         tc.schedule.isDue(start),
+		Passed:      result.Passed,
+		State:       result.State,
+		Score:       result.Score,
+		Requirement: result,
+	}, nil
+}
+
+// RefreshTaskEntry re-evaluates the task for the day of the user the moment belongs to and stores
+// the result in task_entries. It must be called whenever requirement entries of
+// the task are recorded, changed or removed. Entries of weekly, monthly and rolling
+// requirements count for the following days too, those days up to today are refreshed as well.
+func (s *TaskService) RefreshTaskEntry(taskID int64, moment time.Time) (*TaskEvaluation, error) {
+
+	tc, err := s.loadTaskContext(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	evaluation, err := s.storeTaskEntry(tc, tc.calendar.dayOf(moment))
+	if err != nil {
+		return nil, err
+	}
+
+	today := tc.calendar.today()
+	_, last := tc.periodSpan(evaluation.Date)
+	if last.After(today) {
+		last = today
+	}
+
+	for day := evaluation.Date.AddDate(0, 0, 1); !day.After(last); day = day.AddDate(0, 0, 1) {
+		if _, err := s.storeTaskEntry(tc, day); err != nil {
+			return nil, err
+		}
+	}
+
+	return evaluation, nil
+}
+
+func (s *TaskService) storeTaskEntry(tc *taskContext, date time.Time) (*TaskEvaluation, error) {
+
+	evaluation, err := s.evaluateTaskDay(tc, date)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only days something was logged for have an outcome, entries may have been deleted or moved to another day
+	if !hasValues(&evaluation.Requirement) {
+		if err := s.taskEntryRepo.DeleteTaskEntry(tc.task.ID, evaluation.Date); err != nil {
+			logger.Log.Error().Err(err).Int64("taskID", tc.task.ID).Msg("Failed to delete task entry")
+			return nil, err
+		}
+		return evaluation, nil
+	}
+
+	entry := models.TaskEntry{
+		TaskID:    tc.task.ID,
+		EntryDate: evaluation.Date,
+		Completed: evaluation.Passed,
+		Score:     evaluation.Score,
+	}
+
+	if err := s.taskEntryRepo.UpsertTaskEntry(&entry); err != nil {
+		logger.Log.Error().Err(err).Int64("taskID", tc.task.ID).Msg("Failed to store task entry")
+		return nil, err
+	}
+
+	return evaluation, nil
+}
+
+// hasValues reports whether a value was logged for any atom of the tree.
+func hasValues(r *RequirementResult) bool {
+	if r.Value != nil {
+		return true
+	}
+	for i := range r.Operands {
+		if hasValues(&r.Operands[i]) {
+			return true
+		}
+	}
+	return false
+}
+
 type GetAllTasksOptions struct {
 	DetailLevel   string
 	ShowActive    bool
 	ShowArchived  bool
 	ShowCompleted bool
+	WithStats     bool
+	StatsWindow   int
+	// DueOn keeps only the tasks scheduled for that day, nil keeps all of them
+	DueOn *time.Time
+	// IncludeTagIDs keeps tasks with any of the tags, or with all of them if MatchAllTags is set
+	IncludeTagIDs []int64
+	MatchAllTags  bool
+	// ExcludeTagIDs drops tasks with any of the tags
+	ExcludeTagIDs []int64
+	// Inclusive day ranges of the start and end dates, nil leaves the side open
+	StartFrom *time.Time
+	StartTo   *time.Time
+	EndFrom   *time.Time
+	EndTo     *time.Time
+	// SortBy is one of the SortBy* keys, ties are broken by ID
+	SortBy string
+	Desc   bool
+	Limit  int
+	// Cursor is NextCursor or PrevCursor of an earlier page with the same sort, empty for the first page
+	Cursor string
+}
+
+// pageTasks reads only the tasks of the page, the repository filters, sorts and counts them.
+// One task more than the page holds is read to know whether there is a page after it.
+// The count and the page are read in one transaction, so the total agrees with the page.
+func (s *TaskService) pageTasks(opts *GetAllTasksOptions, dbOpts *db.GetAllTasksOptions, cursor *taskCursor, cursorKey taskSortKey) (TaskPage, []models.Task, error) {
+
+	pageOpts := *dbOpts
+	pageOpts.Limit = opts.Limit + 1
+	if cursor != nil {
+		key := &db.TaskKey{Value: cursorKey.value, ID: cursorKey.id}
+		if cursor.Before {
+			pageOpts.Before = key
+		} else {
+			pageOpts.After = key
+		}
+	}
+
+	var total int
+	var modelTasks []models.Task
+	err := s.withTx(func(tx *TaskService) error {
+		var err error
+		if total, err = tx.taskRepo.CountTasks(dbOpts); err != nil {
+			return err
+		}
+		modelTasks, err = tx.taskRepo.GetAllTasks(&pageOpts)
+		return err
+	})
+	if err != nil {
+		return TaskPage{}, nil, err
+	}
+
+	// The extra task is the one furthest from the cursor, which is the first one when reading backwards
+	more := len(modelTasks) > opts.Limit
+	if more && cursor != nil && cursor.Before {
+		modelTasks = modelTasks[1:]
+	} else if more {
+		modelTasks = modelTasks[:opts.Limit]
+	}
+
+	// A page left empty by deleted tasks has no neighbours to point at, the list has to be started over
+	page := TaskPage{Total: total}
+	if len(modelTasks) == 0 {
+		return page, modelTasks, nil
+	}
+
+	hasPrev, hasNext := cursor != nil, more
+	if cursor != nil && cursor.Before {
+		hasPrev, hasNext = more, true
+	}
+
+	if hasPrev {
+		page.PrevCursor = encodeTaskCursor(opts.SortBy, opts.Desc, newTaskSortKey(opts.SortBy, &modelTasks[0], 0), true)
+	}
+	if hasNext {
+		page.NextCursor = encodeTaskCursor(opts.SortBy, opts.Desc, newTaskSortKey(opts.SortBy, &modelTasks[len(modelTasks)-1], 0), false)
+	}
+
+	return page, modelTasks, nil
+}
+
+// pageTasksInMemory reads every task that passes the filters of the repository, filters them by the
+// due day and sorts them by streak before cutting out the page. The stats of the tasks are returned
+// when sorting by streak needed them.
+func (s *TaskService) pageTasksInMemory(opts *GetAllTasksOptions, dbOpts *db.GetAllTasksOptions, userID int64, cursor *taskCursor, cursorKey taskSortKey) (TaskPage, []models.Task, map[int64]*dto.TaskStats, error) {
+
+	modelTasks, err := s.taskRepo.GetAllTasks(dbOpts)
+	if err != nil {
+		return TaskPage{}, nil, nil, err
+	}
+
+	if opts.DueOn != nil {
+		calendar, err := s.calendarFor(userID)
+		if err != nil {
+			return TaskPage{}, nil, nil, err
+		}
+		day, _ := dayBounds(*opts.DueOn)
+
+		var dueTasks []models.Task
+		for i := range modelTasks {
+			ts, err := newTaskSchedule(&modelTasks[i], calendar)
+			if err != nil {
+				return TaskPage{}, nil, nil, err
+			}
+			if ts.isDue(day) {
+				dueTasks = append(dueTasks, modelTasks[i])
+			}
+		}
+		modelTasks = dueTasks
+	}
+
+	var statsByTask map[int64]*dto.TaskStats
+	if opts.SortBy == SortByStreak {
+		statsByTask, err = s.loadTaskStats(userID, modelTasks, opts.StatsWindow)
+		if err != nil {
+			return TaskPage{}, nil, nil, err
+		}
+	}
+
+	keys := make([]taskSortKey, len(modelTasks))
+	order := make([]int, len(modelTasks))
+	for i := range modelTasks {
+		streak := 0
+		if stats := statsByTask[modelTasks[i].ID]; stats != nil {
+			streak = stats.CurrentStreak
+		}
+		keys[i] = newTaskSortKey(opts.SortBy, &modelTasks[i], streak)
+		order[i] = i
+	}
+	slices.SortFunc(order, func(a, b int) int {
+		return compareTaskKeys(opts.SortBy, opts.Desc, keys[a], keys[b])
+	})
+
+	sortedTasks := make([]models.Task, len(order))
+	sortedKeys := make([]taskSortKey, len(order))
+	for i, index := range order {
+		sortedTasks[i] = modelTasks[index]
+		sortedKeys[i] = keys[index]
+	}
+
+	first, last := pageBounds(sortedKeys, opts.SortBy, opts.Desc, cursor, cursorKey, opts.Limit)
+
+	// A page left empty by deleted tasks has no neighbours to point at, the list has to be started over
+	page := TaskPage{Total: len(sortedTasks)}
+	if first > 0 && first < last {
+		page.PrevCursor = encodeTaskCursor(opts.SortBy, opts.Desc, sortedKeys[first], true)
+	}
+	if last < len(sortedTasks) && first < last {
+		page.NextCursor = encodeTaskCursor(opts.SortBy, opts.Desc, sortedKeys[last-1], false)
+	}
+
+	return page, sortedTasks[first:last], statsByTask, nil
 }
 
 // FIXME: Service shouldn't return dto, I'll fix it later
-func (s *TaskService) GetAllTasks(opts *GetAllTasksOptions, userID int64) ([]dto.Task, error) {
+func (s *TaskService) GetAllTasks(opts *GetAllTasksOptions, userID int64) (TaskPage, error) {
+
+	var cursor *taskCursor
+	var cursorKey taskSortKey
+	if opts.Cursor != "" {
+		var err error
+		cursor, cursorKey, err = decodeTaskCursor(opts.Cursor, opts.SortBy, opts.Desc)
+		if err != nil {
+			return TaskPage{}, err
+		}
+	}
 
 	dbOpts := db.GetAllTasksOptions{
-		DetailLevel:  opts.DetailLevel,
-		ShowActive:   opts.ShowActive,
-		ShowArchived: opts.ShowArchived,
-		UserID:       userID,
+		DetailLevel:   opts.DetailLevel,
+		ShowActive:    opts.ShowActive,
+		ShowArchived:  opts.ShowArchived,
+		ShowCompleted: opts.ShowCompleted,
+		UserID:        userID,
+		IncludeTagIDs: opts.IncludeTagIDs,
+		MatchAllTags:  opts.MatchAllTags,
+		ExcludeTagIDs: opts.ExcludeTagIDs,
+		StartFrom:     opts.StartFrom,
+		StartTo:       opts.StartTo,
+		EndFrom:       opts.EndFrom,
+		EndTo:         opts.EndTo,
+	}
+
+	// Streaks are not stored, the list is ordered by ID and sorted once the streaks are known
+	if opts.SortBy != SortByStreak {
+		dbOpts.SortBy = opts.SortBy
+		dbOpts.Desc = opts.Desc
+	}
+
+	// Stats and schedules need fields the detail level may leave out, they are hidden again below
+	if (opts.WithStats || opts.DueOn != nil || opts.SortBy == SortByStreak) &&
+		dbOpts.DetailLevel != "basic" && dbOpts.DetailLevel != "full" {
+		dbOpts.DetailLevel = "basic"
 	}
 
-	// Get tasks
-	modelTasks, err := s.taskRepo.GetAllTasks(&dbOpts)
+	var page TaskPage
+	var modelTasks []models.Task
+	var statsByTask map[int64]*dto.TaskStats
+	var err error
+
+	if opts.SortBy == SortByStreak || opts.DueOn != nil {
+		page, modelTasks, statsByTask, err = s.pageTasksInMemory(opts, &dbOpts, userID, cursor, cursorKey)
+	} else {
+		page, modelTasks, err = s.pageTasks(opts, &dbOpts, cursor, cursorKey)
+	}
 	if err != nil {
 		logger.Log.Err(err).Msg("Failed to get all tasks!")
-		return nil, err
+		return TaskPage{}, err
 	}
 
 	// Get requirements
@@ -177,7 +762,19 @@ func (s *TaskService) GetAllTasks(opts *GetAllTasksOptions, userID int64) ([]dto
 	}
 	modelRequirements, err := s.requirementRepo.GetRequirementsByTaskIDs(tasksIDs)
 	if err != nil {
-		return nil, err
+		return TaskPage{}, err
+	}
+
+	tagsByTask, err := s.tagRepo.GetTagsByTaskIDs(tasksIDs)
+	if err != nil {
+		return TaskPage{}, err
+	}
+
+	if opts.WithStats && statsByTask == nil {
+		statsByTask, err = s.loadTaskStats(userID, modelTasks, opts.StatsWindow)
+		if err != nil {
+			return TaskPage{}, err
+		}
 	}
 
 	modelRequirementsByTask := make(map[int64][]models.Requirement)
@@ -186,39 +783,55 @@ func (s *TaskService) GetAllTasks(opts *GetAllTasksOptions, userID int64) ([]dto
 		modelRequirementsByTask[req.TaskID] = append(modelRequirementsByTask[req.TaskID], req)
 	}
 
-	var result []dto.Task
+	showBasic := opts.DetailLevel == "basic" || opts.DetailLevel == "full"
+	showFull := opts.DetailLevel == "full"
+
+	result := make([]dto.Task, 0, len(modelTasks))
 	for _, modelTask := range modelTasks {
 		dtoTask := dto.Task{
-			ID:          modelTask.ID,
-			Title:       modelTask.Title,
-			Description: modelTask.Description,
+			ID:     modelTask.ID,
+			Title:  modelTask.Title,
+			Status: modelTask.Status,
+			Tags:   toDtoTags(tagsByTask[modelTask.ID]),
 		}
 
 		// Create requirement if exists
 		if reqs, exists := modelRequirementsByTask[modelTask.ID]; exists {
 			dtoTask.Requirement, err = buildTree(reqs, modelTask.ID)
 			if err != nil {
-				return []dto.Task{}, err
+				return TaskPage{}, err
 			}
 		}
 
-		if modelTask.CreatedAt.Valid {
-			dtoTask.CreatedAt = &modelTask.CreatedAt.Time
-		}
-		if modelTask.UpdatedAt.Valid {
-			dtoTask.UpdatedAt = &modelTask.UpdatedAt.Time
+		if showFull {
+			dtoTask.Description = modelTask.Description
+			if modelTask.CreatedAt.Valid {
+				dtoTask.CreatedAt = &modelTask.CreatedAt.Time
+			}
+			if modelTask.UpdatedAt.Valid {
+				dtoTask.UpdatedAt = &modelTask.UpdatedAt.Time
+			}
 		}
-		if modelTask.StartDate.Valid {
-			dtoTask.StartDate = &modelTask.StartDate.Time
+		if showBasic {
+			if modelTask.StartDate.Valid {
+				dtoTask.StartDate = &modelTask.StartDate.Time
+			}
+			if modelTask.EndDate.Valid {
+				dtoTask.EndDate = &modelTask.EndDate.Time
+			}
+			dtoTask.Recurrence = modelTask.Recurrence
 		}
-		if modelTask.EndDate.Valid {
-			dtoTask.EndDate = &modelTask.EndDate.Time
+
+		if opts.WithStats {
+			dtoTask.Stats = statsByTask[modelTask.ID]
 		}
 
 		result = append(result, dtoTask)
 	}
 
-	return result, nil
+	page.Tasks = result
+
+	return page, nil
 
 }
 
@@ -262,6 +875,7 @@ func buildTree(modelRequirements []models.Requirement, taskID int64) (*dto.Requi
 
 		if r.Type == "condition" && r.Operator != nil {
 			dtoReq.Operator = r.Operator
+			dtoReq.ScoreMode = r.ScoreMode
 		} else if r.Type == "atom" {
 			if r.DataType != nil {
 				dtoReq.DataType = r.DataType
@@ -272,6 +886,13 @@ func buildTree(modelRequirements []models.Requirement, taskID int64) (*dto.Requi
 			if r.TargetValue != nil {
 				dtoReq.TargetValue = r.TargetValue
 			}
+			dtoReq.Options = r.Options
+			dtoReq.Unit = r.Unit
+			if r.Aggregation != nil {
+				dtoReq.Aggregation = r.Aggregation
+			}
+			dtoReq.Period = r.Period
+			dtoReq.PeriodDays = r.PeriodDays
 		}
 
 		if children, exists := childrenMap[r.ID]; exists {
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStoreTaskEntrySkipsEmptyDays(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		// logged are the values logged today by atom title
+		logged    map[string][]string
+		stored    bool
+		completed bool
+	}{
+		{name: "nothing counted", expression: "count(coffee) <= 2"},
+		{name: "nothing counted towards a minimum", expression: "count(gym) >= 1"},
+		{name: "nothing counted next to other atoms", expression: "count(coffee) <= 2 and meditated"},
+		{name: "counted within the limit", expression: "count(coffee) <= 2", logged: map[string][]string{"coffee": {"true"}}, stored: true, completed: true},
+		{name: "counted over the limit", expression: "count(coffee) <= 2", logged: map[string][]string{"coffee": {"true", "true", "true"}}, stored: true},
+		{name: "other atom logged", expression: "count(coffee) <= 2 and meditated", logged: map[string][]string{"meditated": {"true"}}, stored: true, completed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, userID := newTestService(t)
+			task := createTestTask(t, s, userID, "task", tt.expression)
+			today := day(time.Now().UTC().Format("2006-01-02"))
+
+			ids := atomIDs(t, s, task.ID)
+			for title, values := range tt.logged {
+				for _, value := range values {
+					logValue(t, s, userID, ids[title], today, value)
+				}
+			}
+
+			if _, err := s.RefreshTaskEntry(task.ID, today.Add(12*time.Hour)); err != nil {
+				t.Fatalf("RefreshTaskEntry returned error: %v", err)
+			}
+
+			days, err := s.GetTaskDays(task.ID, userID, Range{From: today, To: today.AddDate(0, 0, 1), FromDate: true, ToDate: true})
+			if err != nil {
+				t.Fatalf("GetTaskDays returned error: %v", err)
+			}
+
+			if stored := len(days) == 1; stored != tt.stored {
+				t.Fatalf("stored = %v, want %v (%d days)", stored, tt.stored, len(days))
+			}
+			if tt.stored && days[0].Completed != tt.completed {
+				t.Errorf("completed = %v, want %v", days[0].Completed, tt.completed)
+			}
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"github.com/boreymarf/task-fuss/server/internal/apperrors"
+	"github.com/boreymarf/task-fuss/server/internal/dto"
+	"github.com/boreymarf/task-fuss/server/internal/models"
+)
+
+// DefaultTrendWindow is the number of days the moving average spans when no window is given
+const DefaultTrendWindow = 7
+
+// DefaultTrendDays is the length of a trend without a start
+const DefaultTrendDays = 90
+
+// Which values of a requirement are better, taken from the comparison of its target
+const (
+	TrendHigherIsBetter = "higher"
+	TrendLowerIsBetter  = "lower"
+)
+
+// RequirementTrend is the series of the values logged for a numeric requirement.
+// Values are in the unit of the requirement, durations in seconds.
+type RequirementTrend struct {
+	RequirementID int64
+	DataType      string
+	Unit          *string
+	// Direction is empty when the target is not a lower or upper bound, there are no personal bests then
+	Direction string
+	Window    int
+	Days      []TrendDay
+	Weeks     []TrendWeek
+	Count     int
+	Average   *float64
+	Min       *TrendRecord
+	Max       *TrendRecord
+	// Best is the best value ever logged, including the days before the range
+	Best *TrendRecord
+}
+
+// TrendDay is the value of one day, entries of a day are combined by the aggregation of the requirement.
+type TrendDay struct {
+	Date time.Time
+	// Value is nil on days nothing was logged
+	Value *float64
+	// MovingAverage is the average of the values in the window that ends with the day
+	MovingAverage *float64
+	// PersonalBest is set when the value beats every value logged before it
+	PersonalBest bool
+}
+
+// TrendWeek sums up the values of a week of the user's calendar, the first and the last week may be partial.
+type TrendWeek struct {
+	Start   time.Time
+	Count   int
+	Average *float64
+	// Change is the relative change of the average to the previous week, nil when there is nothing to compare
+	Change *float64
+}
+
+type TrendRecord struct {
+	Date  time.Time
+	Value float64
+}
+
+// GetRequirementTrend returns the values of the requirement by day for the days of the range with their
+// moving average over window days, personal bests and week over week changes. A zero From means the
+// DefaultTrendDays days up to To.
+func (s *TaskService) GetRequirementTrend(requirementID int64, userID int64, r Range, window int) (RequirementTrend, error) {
+
+	requirement, err := s.getOwnedRequirement(requirementID, userID)
+	if err != nil {
+		return RequirementTrend{}, err
+	}
+
+	atom := &dto.Requirement{
+		ID:          requirement.ID,
+		Type:        requirement.Type,
+		DataType:    requirement.DataType,
+		Operator:    requirement.Operator,
+		Unit:        requirement.Unit,
+		Aggregation: requirement.Aggregation,
+	}
+
+	dataType := atomDataType(atom)
+	if atom.Type != "atom" || (dataType != "int" && dataType != "float" && dataType != "duration" && dataType != "rating") {
+		return RequirementTrend{}, apperrors.NewValidationError("NOT_NUMERIC", "requirement_id", "Trends are only available for int, float, duration and rating requirements")
+	}
+
+	if window <= 0 {
+		window = DefaultTrendWindow
+	}
+
+	calendar, err := s.calendarFor(userID)
+	if err != nil {
+		return RequirementTrend{}, err
+	}
+
+	from, to, err := pastDays(calendar, r, DefaultTrendDays)
+	if err != nil {
+		return RequirementTrend{}, err
+	}
+
+	// Earlier entries are needed for personal bests and the first moving averages
+	entries, err := s.requirementEntryRepo.GetEntriesByRequirementIDs([]int64{requirementID}, time.Time{}, calendar.dayStart(to))
+	if err != nil {
+		return RequirementTrend{}, err
+	}
+
+	entries, err = convertEntries(atom, entries)
+	if err != nil {
+		return RequirementTrend{}, err
+	}
+
+	values, err := dailyValues(calendar, atom, dataType, entries)
+	if err != nil {
+		return RequirementTrend{}, err
+	}
+
+	trend := RequirementTrend{
+		RequirementID: requirementID,
+		DataType:      dataType,
+		Unit:          requirement.Unit,
+		Direction:     trendDirection(requirement.Operator),
+		Window:        window,
+		Days:          []TrendDay{},
+		Weeks:         []TrendWeek{},
+	}
+
+	better := func(a, b float64) bool {
+		if trend.Direction == TrendLowerIsBetter {
+			return a < b
+		}
+		return a > b
+	}
+
+	// Best value logged before the range
+	var best *TrendRecord
+	if trend.Direction != "" {
+		for _, day := range sortedDays(values) {
+			if !day.Before(from) {
+				break
+			}
+			if best == nil || better(values[day], best.Value) {
+				best = &TrendRecord{Date: day, Value: values[day]}
+			}
+		}
+	}
+
+	sum := 0.0
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		trendDay := TrendDay{Date: day}
+
+		if value, logged := values[day]; logged {
+			trendDay.Value = &value
+
+			trend.Count++
+			sum += value
+			if trend.Min == nil || value < trend.Min.Value {
+				trend.Min = &TrendRecord{Date: day, Value: value}
+			}
+			if trend.Max == nil || value > trend.Max.Value {
+				trend.Max = &TrendRecord{Date: day, Value: value}
+			}
+
+			if trend.Direction != "" && (best == nil || better(value, best.Value)) {
+				// The very first value is not a record yet
+				trendDay.PersonalBest = best != nil
+				best = &TrendRecord{Date: day, Value: value}
+			}
+		}
+
+		windowSum, windowCount := 0.0, 0
+		for d := day.AddDate(0, 0, -(window - 1)); !d.After(day); d = d.AddDate(0, 0, 1) {
+			if value, logged := values[d]; logged {
+				windowSum += value
+				windowCount++
+			}
+		}
+		if windowCount > 0 {
+			average := windowSum / float64(windowCount)
+			trendDay.MovingAverage = &average
+		}
+
+		trend.Days = append(trend.Days, trendDay)
+
+		weekStart, _ := calendar.periodBounds("week", 0, day)
+		if len(trend.Weeks) == 0 || !trend.Weeks[len(trend.Weeks)-1].Start.Equal(weekStart) {
+			trend.Weeks = append(trend.Weeks, TrendWeek{Start: weekStart})
+		}
+		if trendDay.Value != nil {
+			week := &trend.Weeks[len(trend.Weeks)-1]
+			week.Count++
+			if week.Average == nil {
+				week.Average = new(float64)
+			}
+			*week.Average += *trendDay.Value
+		}
+	}
+
+	for i := range trend.Weeks {
+		week := &trend.Weeks[i]
+		if week.Average == nil {
+			continue
+		}
+		*week.Average /= float64(week.Count)
+
+		if i > 0 {
+			previous := trend.Weeks[i-1].Average
+			if previous != nil && *previous != 0 {
+				change := (*week.Average - *previous) / math.Abs(*previous)
+				week.Change = &change
+			}
+		}
+	}
+
+	if trend.Count > 0 {
+		average := sum / float64(trend.Count)
+		trend.Average = &average
+	}
+	trend.Best = best
+
+	return trend, nil
+}
+
+// dailyValues combines the entries, which are sorted by date, into one number per day of the calendar.
+func dailyValues(calendar Calendar, atom *dto.Requirement, dataType string, entries []models.RequirementEntry) (map[time.Time]float64, error) {
+
+	values := make(map[time.Time]float64)
+
+	for start := 0; start < len(entries); {
+		day := calendar.dayOf(entries[start].EntryDate)
+		_, next := calendar.dayMoments(day)
+
+		end := start
+		for end < len(entries) && entries[end].EntryDate.Before(next) {
+			end++
+		}
+
+		value, exists, err := aggregateEntries(atom, entries[start:end])
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			number, err := numericValue(dataType, value)
+			if err != nil {
+				return nil, err
+			}
+			values[day] = number
+		}
+
+		start = end
+	}
+
+	return values, nil
+}
+
+// trendDirection tells from the comparison of the target whether higher or lower values are better.
+func trendDirection(operator *string) string {
+	if operator == nil {
+		return ""
+	}
+	switch *operator {
+	case ">", ">=":
+		return TrendHigherIsBetter
+	case "<", "<=":
+		return TrendLowerIsBetter
+	default:
+		return ""
+	}
+}
+
+func sortedDays(values map[time.Time]float64) []time.Time {
+	days := make([]time.Time, 0, len(values))
+	for day := range values {
+		days = append(days, day)
+	}
+	slices.SortFunc(days, func(a, b time.Time) int { return a.Compare(b) })
+	return days
+}
@@ -0,0 +1,386 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/boreymarf/task-fuss/server/internal/apperrors"
+	"github.com/boreymarf/task-fuss/server/internal/config"
+	"github.com/boreymarf/task-fuss/server/internal/dsl"
+	"github.com/boreymarf/task-fuss/server/internal/dto"
+	"github.com/boreymarf/task-fuss/server/internal/logger"
+	"github.com/boreymarf/task-fuss/server/internal/models"
+	"github.com/boreymarf/task-fuss/server/internal/units"
+)
+
+// UpdateTask changes the fields set in the request and replaces the requirement tree if one is given.
+// The new tree is matched with the stored one by requirement ID: matching nodes are updated and keep
+// their logged values, new nodes are created and nodes missing from the new tree are deleted with
+// their values, unless an older revision still uses them. The new tree is stored as a revision effective
+// from the requested day and the outcomes of the days since then are re-evaluated.
+// Everything is stored in one transaction.
+func (s *TaskService) UpdateTask(taskID int64, userID int64, req *dto.UpdateTaskRequest) (dto.Task, error) {
+
+	task, err := s.taskRepo.GetTaskByID(taskID)
+	if err != nil {
+		return dto.Task{}, err
+	}
+
+	if task.OwnerID != userID {
+		return dto.Task{}, apperrors.ErrForbidden
+	}
+
+	treeChanged := req.Requirement != nil || req.Expression != nil
+
+	var revisions []models.RequirementRevision
+	if treeChanged {
+		revisions, err = s.loadRevisions(&task)
+		if err != nil {
+			return dto.Task{}, err
+		}
+	}
+
+	var errs apperrors.ValidationErrors
+
+	calendar, err := s.calendarFor(userID)
+	if err != nil {
+		return dto.Task{}, err
+	}
+
+	today := calendar.today()
+	effectiveFrom := today
+
+	if req.EffectiveFrom != nil {
+		if !treeChanged {
+			errs = append(errs, apperrors.NewValidationError("UNEXPECTED_FIELD", "effective_from", "Field 'effective_from' can only be used together with 'requirement' or 'expression'"))
+		} else if date, err := time.Parse(config.DateFormat, *req.EffectiveFrom); err != nil {
+			errs = append(errs, apperrors.NewValidationError("INVALID_DATE", "effective_from", "Field 'effective_from' must be a date in YYYY-MM-DD format"))
+		} else {
+			effectiveFrom = date
+		}
+	}
+
+	if treeChanged {
+		latest, _ := dayBounds(revisions[len(revisions)-1].EffectiveFrom)
+		if len(revisions) > 1 && effectiveFrom.Before(latest) {
+			errs = append(errs, apperrors.NewValidationError("INVALID_DATE", "effective_from",
+				fmt.Sprintf("Field 'effective_from' cannot be before %s, when the latest revision took effect", latest.Format(config.DateFormat))))
+		}
+	}
+
+	if req.Title != nil {
+		if strings.TrimSpace(*req.Title) == "" {
+			errs = append(errs, apperrors.NewValidationError("EMPTY_FIELD", "title", "Field 'title' cannot be empty"))
+		}
+		task.Title = *req.Title
+	}
+
+	if req.Description != nil {
+		task.Description = req.Description
+		if *req.Description == "" {
+			task.Description = nil
+		}
+	}
+
+	if req.Status != nil {
+		task.Status = *req.Status
+	}
+
+	if req.StartDate != nil {
+		date, err := time.Parse(config.DateFormat, *req.StartDate)
+		if err != nil {
+			errs = append(errs, apperrors.NewValidationError("INVALID_DATE", "start_date", "Field 'start_date' must be a date in YYYY-MM-DD format"))
+		} else {
+			task.StartDate = sql.NullTime{Time: date, Valid: true}
+		}
+	}
+
+	if req.EndDate != nil {
+		if *req.EndDate == "" {
+			task.EndDate = sql.NullTime{}
+		} else if date, err := time.Parse(config.DateFormat, *req.EndDate); err != nil {
+			errs = append(errs, apperrors.NewValidationError("INVALID_DATE", "end_date", "Field 'end_date' must be a date in YYYY-MM-DD format"))
+		} else {
+			task.EndDate = sql.NullTime{Time: date, Valid: true}
+		}
+	}
+
+	if task.StartDate.Valid && task.EndDate.Valid {
+		start, _ := dayBounds(task.StartDate.Time)
+		end, _ := dayBounds(task.EndDate.Time)
+		if end.Before(start) {
+			errs = append(errs, apperrors.NewValidationError("INVALID_DATE", "end_date", "Field 'end_date' cannot be before 'start_date'"))
+		}
+	}
+
+	if req.Recurrence != nil {
+		recurrence, recurrenceErr := normalizeRecurrence(req.Recurrence)
+		if recurrenceErr != nil {
+			errs = append(errs, recurrenceErr)
+		}
+		task.Recurrence = recurrence
+	}
+
+	if req.TagIDs != nil {
+		tagErrs, err := s.validateTagIDs(*req.TagIDs, userID, "tag_ids")
+		if err != nil {
+			return dto.Task{}, err
+		}
+		errs = append(errs, tagErrs...)
+	}
+
+	var existing []models.Requirement
+
+	root := req.Requirement
+	if treeChanged {
+		existing, err = s.requirementRepo.GetRequirementsByTaskIDs([]int64{taskID})
+		if err != nil {
+			return dto.Task{}, err
+		}
+	}
+
+	if root == nil && req.Expression != nil {
+		root, err = dsl.Parse(*req.Expression)
+		if err != nil {
+			return dto.Task{}, err
+		}
+		matchAtomsByTitle(root, existing)
+	}
+
+	if root != nil {
+		errs = append(errs, validateRequirement(root, "requirement")...)
+
+		entryCounts, err := s.requirementEntryRepo.CountEntriesByRequirementIDs(requirementIDs(existing))
+		if err != nil {
+			return dto.Task{}, err
+		}
+		errs = append(errs, validateTreeChange(root, "requirement", existing, entryCounts)...)
+	}
+
+	if len(errs) > 0 {
+		return dto.Task{}, errs
+	}
+
+	err = s.withTx(func(tx *TaskService) error {
+		if err := tx.taskRepo.UpdateTask(&task); err != nil {
+			return err
+		}
+
+		if req.TagIDs != nil {
+			if err := tx.tagRepo.SetTaskTags(taskID, *req.TagIDs); err != nil {
+				return err
+			}
+		}
+
+		if root == nil {
+			return tx.refreshTaskEntriesSince(taskID, today)
+		}
+
+		// The tree the task had so far becomes its first revision
+		if revisions[0].ID == 0 {
+			if err := tx.requirementRepo.UpsertRevision(&revisions[0]); err != nil {
+				return err
+			}
+		}
+
+		// Revisions effective from the same day are replaced, the older ones keep their requirements
+		used := make(map[int64]bool)
+		for _, modelRevision := range revisions {
+			if !modelRevision.EffectiveFrom.Before(effectiveFrom) {
+				continue
+			}
+			revision, err := decodeRevision(modelRevision)
+			if err != nil {
+				return err
+			}
+			for _, id := range collectRequirementIDs(revision.root) {
+				used[id] = true
+			}
+		}
+
+		if err := tx.replaceRequirementTree(root, taskID, existing, used); err != nil {
+			return err
+		}
+
+		if err := tx.storeRevision(&task, effectiveFrom); err != nil {
+			return err
+		}
+
+		// Days before the new revision keep their outcome
+		return tx.refreshTaskEntriesSince(taskID, effectiveFrom)
+	})
+	if err != nil {
+		logger.Log.Error().Err(err).Int64("taskID", taskID).Msg("Failed to update task")
+		return dto.Task{}, err
+	}
+
+	return s.GetTaskByID(taskID, userID, &GetTaskOptions{})
+}
+
+// replaceRequirementTree stores root as the requirement tree of the task, reusing the nodes of existing by ID.
+// Nodes left out of root are retired if they are in used and deleted otherwise.
+func (s *TaskService) replaceRequirementTree(root *dto.Requirement, taskID int64, existing []models.Requirement, used map[int64]bool) error {
+
+	kept := make(map[int64]bool)
+
+	var store func(r *dto.Requirement, parentID *int64) error
+	store = func(r *dto.Requirement, parentID *int64) error {
+		model := toModelRequirement(r, taskID, parentID)
+
+		if model.ID != 0 {
+			if err := s.requirementRepo.UpdateRequirement(&model); err != nil {
+				return err
+			}
+			kept[model.ID] = true
+		} else if err := s.requirementRepo.CreateRequirement(&model); err != nil {
+			return err
+		}
+
+		if r.Type == "condition" {
+			for i := range r.Operands {
+				if err := store(&r.Operands[i], &model.ID); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}
+
+	if err := store(root, nil); err != nil {
+		return err
+	}
+
+	var removed, retired []int64
+	for _, requirement := range existing {
+		switch {
+		case kept[requirement.ID]:
+		case used[requirement.ID]:
+			retired = append(retired, requirement.ID)
+		default:
+			removed = append(removed, requirement.ID)
+		}
+	}
+
+	if err := s.requirementRepo.RetireRequirements(retired); err != nil {
+		return err
+	}
+
+	// Foreign keys are not enforced, so entries are not removed by ON DELETE CASCADE
+	if err := s.requirementEntryRepo.DeleteEntriesByRequirementIDs(removed); err != nil {
+		return err
+	}
+
+	return s.requirementRepo.DeleteRequirements(removed)
+}
+
+// validateTreeChange checks that the IDs of the new tree belong to the task and that nodes
+// with logged values are not changed in a way that makes those values meaningless.
+func validateTreeChange(root *dto.Requirement, path string, existing []models.Requirement, entryCounts map[int64]int) apperrors.ValidationErrors {
+	var errs apperrors.ValidationErrors
+
+	byID := make(map[int64]*models.Requirement)
+	for i := range existing {
+		byID[existing[i].ID] = &existing[i]
+	}
+
+	seen := make(map[int64]bool)
+
+	var walk func(r *dto.Requirement, path string)
+	walk = func(r *dto.Requirement, path string) {
+		for i := range r.Operands {
+			walk(&r.Operands[i], fmt.Sprintf("%s.operands[%d]", path, i))
+		}
+
+		if r.ID == 0 {
+			return
+		}
+
+		old, ok := byID[r.ID]
+		if !ok {
+			errs = append(errs, apperrors.NewValidationError("INVALID_ID", path+".id", fmt.Sprintf("Requirement %d does not belong to the task", r.ID)))
+			return
+		}
+		if seen[r.ID] {
+			errs = append(errs, apperrors.NewValidationError("DUPLICATE_ID", path+".id", fmt.Sprintf("Requirement %d is used more than once", r.ID)))
+			return
+		}
+		seen[r.ID] = true
+
+		if entryCounts[r.ID] > 0 && !compatibleRequirement(old, r) {
+			errs = append(errs, apperrors.NewValidationError("INCOMPATIBLE_CHANGE", path+".id",
+				fmt.Sprintf("Requirement %d has logged values, so its type, data type and unit dimension cannot change, leave out the ID to replace it", r.ID)))
+		}
+	}
+	walk(root, path)
+
+	return errs
+}
+
+// compatibleRequirement reports whether values logged for old can still be read as values of r.
+func compatibleRequirement(old *models.Requirement, r *dto.Requirement) bool {
+
+	if old.Type != r.Type || stringValue(old.DataType) != stringValue(r.DataType) {
+		return false
+	}
+
+	if (old.Unit == nil) != (r.Unit == nil) {
+		return false
+	}
+	if old.Unit != nil {
+		oldUnit, _ := units.Lookup(*old.Unit)
+		newUnit, _ := units.Lookup(*r.Unit)
+		return oldUnit.Dimension == newUnit.Dimension
+	}
+
+	return true
+}
+
+// matchAtomsByTitle gives atoms parsed from an expression the IDs of compatible stored atoms with
+// the same name, so editing "pushups >= 50" to "pushups >= 60" keeps the logged pushups.
+func matchAtomsByTitle(root *dto.Requirement, existing []models.Requirement) {
+
+	byTitle := make(map[string][]*models.Requirement)
+	for i := range existing {
+		if existing[i].Type == "atom" {
+			byTitle[existing[i].Title] = append(byTitle[existing[i].Title], &existing[i])
+		}
+	}
+
+	var walk func(r *dto.Requirement)
+	walk = func(r *dto.Requirement) {
+		for i := range r.Operands {
+			walk(&r.Operands[i])
+		}
+		if r.Type != "atom" || r.ID != 0 {
+			return
+		}
+
+		candidates := byTitle[r.Title]
+		for i, candidate := range candidates {
+			if compatibleRequirement(candidate, r) {
+				r.ID = candidate.ID
+				byTitle[r.Title] = append(candidates[:i:i], candidates[i+1:]...)
+				return
+			}
+		}
+	}
+	walk(root)
+}
+
+func requirementIDs(requirements []models.Requirement) []int64 {
+	ids := make([]int64, 0, len(requirements))
+	for _, requirement := range requirements {
+		ids = append(ids, requirement.ID)
+	}
+	return ids
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
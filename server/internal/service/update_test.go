@@ -0,0 +1,208 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/boreymarf/task-fuss/server/internal/config"
+	"github.com/boreymarf/task-fuss/server/internal/dsl"
+	"github.com/boreymarf/task-fuss/server/internal/dto"
+	"github.com/boreymarf/task-fuss/server/internal/models"
+)
+
+// storedAtom returns a stored atom, an empty unit leaves it unset.
+func storedAtom(id int64, title string, dataType string, unit string) models.Requirement {
+	r := models.Requirement{ID: id, TaskID: 1, Title: title, Type: "atom", DataType: &dataType}
+	if unit != "" {
+		r.Unit = &unit
+	}
+	return r
+}
+
+func TestValidateTreeChange(t *testing.T) {
+	existing := []models.Requirement{
+		{ID: 1, TaskID: 1, Title: "and", Type: "condition", Operator: ptr("and")},
+		storedAtom(2, "pushups", "int", ""),
+		storedAtom(3, "run", "float", "km"),
+		storedAtom(4, "meditated", "bool", ""),
+	}
+	// Only pushups and run have logged values
+	entryCounts := map[int64]int{2: 5, 3: 1}
+
+	withID := func(r dto.Requirement, id int64) dto.Requirement {
+		r.ID = id
+		return r
+	}
+	withUnit := func(r dto.Requirement, unit string) dto.Requirement {
+		r.Unit = &unit
+		return r
+	}
+
+	tests := []struct {
+		name string
+		root dto.Requirement
+		want []string
+	}{
+		{name: "same tree", root: withID(condition(0, "and", withID(atom(0, "int", "", ">=", "50"), 2), withUnit(withID(atom(0, "float", "", ">=", "5"), 3), "km")), 1)},
+		{name: "new nodes", root: condition(0, "or", atom(0, "int", "", ">=", "50"), atom(0, "bool", "", "", ""))},
+		{name: "new target", root: withID(atom(0, "int", "", ">=", "60"), 2)},
+		{name: "unit of the same dimension", root: withUnit(withID(atom(0, "float", "", ">=", "3"), 3), "mi")},
+		{name: "data type without values", root: withID(atom(0, "int", "", ">=", "3"), 4)},
+		{name: "condition becomes an atom", root: withID(atom(0, "bool", "", "", ""), 1)},
+
+		{name: "data type with values", root: withID(atom(0, "float", "", ">=", "50"), 2), want: []string{"INCOMPATIBLE_CHANGE requirement.id"}},
+		{name: "atom becomes a condition", root: withID(condition(0, "and", atom(0, "bool", "", "", "")), 2), want: []string{"INCOMPATIBLE_CHANGE requirement.id"}},
+		{name: "unit of another dimension", root: withUnit(withID(atom(0, "float", "", ">=", "3"), 3), "kg"), want: []string{"INCOMPATIBLE_CHANGE requirement.id"}},
+		{name: "unit removed", root: withID(atom(0, "float", "", ">=", "3"), 3), want: []string{"INCOMPATIBLE_CHANGE requirement.id"}},
+		{name: "ID of another task", root: condition(0, "and", withID(atom(0, "int", "", ">=", "50"), 99)), want: []string{"INVALID_ID requirement.operands[0].id"}},
+		{name: "ID used twice", root: condition(0, "and", withID(atom(0, "int", "", ">=", "50"), 2), withID(atom(0, "int", "", ">=", "60"), 2)), want: []string{"DUPLICATE_ID requirement.operands[1].id"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, err := range validateTreeChange(&tt.root, "requirement", existing, entryCounts) {
+				got = append(got, fmt.Sprintf("%s %s", err.Code, err.Field))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("errors = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchAtomsByTitle(t *testing.T) {
+	existing := []models.Requirement{
+		{ID: 1, TaskID: 1, Title: "and", Type: "condition", Operator: ptr("and")},
+		storedAtom(2, "pushups", "int", ""),
+		storedAtom(3, "run", "int", "km"),
+		storedAtom(4, "meditated", "bool", ""),
+		storedAtom(5, "water", "int", ""),
+		storedAtom(6, "water", "int", ""),
+	}
+
+	tests := []struct {
+		expression string
+		// want are the IDs the atoms get in the order they are written
+		want []int64
+	}{
+		{"pushups >= 60", []int64{2}},
+		{"pushups >= 60 and meditated", []int64{2, 4}},
+		{"meditated or pushups >= 10", []int64{4, 2}},
+		{"run >= 10 mi", []int64{3}},
+		{"read and pushups >= 50", []int64{0, 2}},
+		// The data type changes, the logged values would not fit
+		{"pushups >= 30m", []int64{0}},
+		{"run >= 10 kg", []int64{0}},
+		{"run >= 10", []int64{0}},
+		// Every stored atom is given once
+		{"pushups >= 50 and pushups <= 100", []int64{2, 0}},
+		{"water >= 1 and water <= 3 and water != 2", []int64{5, 6, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			root, err := dsl.Parse(tt.expression)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %v", tt.expression, err)
+			}
+
+			matchAtomsByTitle(root, existing)
+
+			var got []int64
+			var walk func(r *dto.Requirement)
+			walk = func(r *dto.Requirement) {
+				if r.Type == "atom" {
+					got = append(got, r.ID)
+				}
+				for i := range r.Operands {
+					walk(&r.Operands[i])
+				}
+			}
+			walk(root)
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("IDs = %v, want %v", got, tt.want)
+			}
+			if root.Type == "condition" && root.ID != 0 {
+				t.Errorf("condition got ID %d, only atoms are matched", root.ID)
+			}
+		})
+	}
+}
+
+func TestUpdateTaskRequirementTree(t *testing.T) {
+	s, userID := newTestService(t)
+	task := createTestTask(t, s, userID, "task", "pushups >= 50 and meditated")
+	today := day(time.Now().UTC().Format("2006-01-02"))
+	tomorrow := today.AddDate(0, 0, 1).Format(config.DateFormat)
+
+	original := atomIDs(t, s, task.ID)
+	logValue(t, s, userID, original["pushups"], today, "55")
+	logValue(t, s, userID, original["meditated"], today, "true")
+
+	update := func(expression string) map[string]int64 {
+		t.Helper()
+		if _, err := s.UpdateTask(task.ID, userID, &dto.UpdateTaskRequest{Expression: &expression, EffectiveFrom: &tomorrow}); err != nil {
+			t.Fatalf("UpdateTask(%q) returned error: %v", expression, err)
+		}
+		return atomIDs(t, s, task.ID)
+	}
+
+	// state returns "current", "retired" or "deleted" and the number of entries of the requirement.
+	state := func(id int64) (string, int) {
+		t.Helper()
+		entries, err := s.requirementEntryRepo.GetEntriesByRequirementIDs([]int64{id}, time.Time{}, today.AddDate(0, 0, 2))
+		if err != nil {
+			t.Fatalf("failed to read entries: %v", err)
+		}
+		requirement, err := s.requirementRepo.GetRequirementByID(id)
+		switch {
+		case err != nil:
+			return "deleted", len(entries)
+		case requirement.Retired:
+			return "retired", len(entries)
+		default:
+			return "current", len(entries)
+		}
+	}
+
+	check := func(name string, id int64, wantState string, wantEntries int) {
+		t.Helper()
+		if got, entries := state(id); got != wantState || entries != wantEntries {
+			t.Errorf("%s is %s with %d entries, want %s with %d", name, got, entries, wantState, wantEntries)
+		}
+	}
+
+	// The first revision still uses meditated for the days before tomorrow
+	first := update("pushups >= 60 and read")
+	if first["pushups"] != original["pushups"] {
+		t.Errorf("pushups ID = %d, want %d", first["pushups"], original["pushups"])
+	}
+	if first["read"] == 0 {
+		t.Fatalf("read was not created")
+	}
+	check("pushups", original["pushups"], "current", 1)
+	check("meditated", original["meditated"], "retired", 1)
+
+	pushups, err := s.requirementRepo.GetRequirementByID(original["pushups"])
+	if err != nil {
+		t.Fatalf("failed to read pushups: %v", err)
+	}
+	if stringValue(pushups.TargetValue) != "60" {
+		t.Errorf("pushups target = %s, want 60", stringValue(pushups.TargetValue))
+	}
+
+	logValue(t, s, userID, first["read"], today, "true")
+
+	// A revision effective from the same day replaces the previous one, nothing else used read
+	second := update("pushups >= 60 and journal")
+	if second["pushups"] != original["pushups"] || second["journal"] == 0 {
+		t.Errorf("atoms = %v, want pushups %d and a new journal", second, original["pushups"])
+	}
+	check("pushups", original["pushups"], "current", 1)
+	check("meditated", original["meditated"], "retired", 1)
+	check("read", first["read"], "deleted", 0)
+}
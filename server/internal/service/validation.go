@@ -0,0 +1,348 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/boreymarf/task-fuss/server/internal/apperrors"
+	"github.com/boreymarf/task-fuss/server/internal/dto"
+	"github.com/boreymarf/task-fuss/server/internal/duration"
+	"github.com/boreymarf/task-fuss/server/internal/timeofday"
+	"github.com/boreymarf/task-fuss/server/internal/units"
+)
+
+var (
+	requirementTypes     = []string{"atom", "condition"}
+	conditionOperators   = []string{"and", "or", "not"}
+	comparisonOperators  = []string{"==", "!=", ">=", "<=", ">", "<"}
+	equalityOperators    = []string{"==", "!="}
+	requirementDataTypes = []string{"bool", "int", "float", "duration", "none", "choice", "text", "rating", "time"}
+	aggregations         = []string{"last", "sum", "count", "max", "min"}
+	numericDataTypes     = []string{"int", "float", "duration"}
+	orderedDataTypes     = []string{"int", "float", "duration", "rating", "time"}
+	periods              = []string{"day", "week", "month", "rolling"}
+	scoreModes           = []string{"average", "min"}
+)
+
+// maxPeriodDays limits rolling periods to a year
+const maxPeriodDays = 366
+
+// Bounds of the rating scale
+const (
+	minRating = 1
+	maxRating = 5
+)
+
+// validateRequirement walks the whole tree and returns every problem found in it.
+// path is the JSON path of r, it is used to point at the exact field in the errors.
+func validateRequirement(r *dto.Requirement, path string) apperrors.ValidationErrors {
+	var errs apperrors.ValidationErrors
+
+	add := func(code string, field string, format string, args ...any) {
+		errs = append(errs, apperrors.NewValidationError(code, path+"."+field, fmt.Sprintf(format, args...)))
+	}
+
+	if !slices.Contains(requirementTypes, r.Type) {
+		add("INVALID_TYPE", "type", "Field 'type' must be one of: %s", strings.Join(requirementTypes, ", "))
+		return errs
+	}
+
+	switch r.Type {
+	case "condition":
+		if r.DataType != nil {
+			add("UNEXPECTED_FIELD", "data_type", "Condition cannot have a data type")
+		}
+		if r.TargetValue != nil {
+			add("UNEXPECTED_FIELD", "target_value", "Condition cannot have a target value")
+		}
+		if r.Aggregation != nil {
+			add("UNEXPECTED_FIELD", "aggregation", "Condition cannot have an aggregation")
+		}
+		if r.Period != nil {
+			add("UNEXPECTED_FIELD", "period", "Condition cannot have a period")
+		}
+		if r.PeriodDays != nil {
+			add("UNEXPECTED_FIELD", "period_days", "Condition cannot have a period length")
+		}
+		if r.Options != nil {
+			add("UNEXPECTED_FIELD", "options", "Condition cannot have options")
+		}
+		if r.Unit != nil {
+			add("UNEXPECTED_FIELD", "unit", "Condition cannot have a unit")
+		}
+
+		if r.Operator == nil {
+			add("EMPTY_FIELD", "operator", "Condition must have an operator")
+		} else if !slices.Contains(conditionOperators, *r.Operator) {
+			add("INVALID_OPERATOR", "operator", "Condition operator must be one of: %s", strings.Join(conditionOperators, ", "))
+		} else if *r.Operator == "not" && len(r.Operands) > 1 {
+			add("INVALID_OPERANDS", "operands", "Operator 'not' takes exactly one operand, got %d", len(r.Operands))
+		}
+
+		if len(r.Operands) == 0 {
+			add("EMPTY_FIELD", "operands", "Condition must have at least one operand")
+		}
+
+		if r.ScoreMode != nil {
+			if !slices.Contains(scoreModes, *r.ScoreMode) {
+				add("INVALID_SCORE_MODE", "score_mode", "Field 'score_mode' must be one of: %s", strings.Join(scoreModes, ", "))
+			} else if r.Operator != nil && *r.Operator != "and" {
+				add("UNEXPECTED_FIELD", "score_mode", "Only 'and' conditions can have a score mode")
+			}
+		}
+
+		for i := range r.Operands {
+			errs = append(errs, validateRequirement(&r.Operands[i], fmt.Sprintf("%s.operands[%d]", path, i))...)
+		}
+
+	case "atom":
+		if strings.TrimSpace(r.Title) == "" {
+			add("EMPTY_FIELD", "title", "Field 'title' cannot be empty")
+		}
+		if len(r.Operands) > 0 {
+			add("UNEXPECTED_FIELD", "operands", "Atom cannot have operands")
+		}
+		if r.ScoreMode != nil {
+			add("UNEXPECTED_FIELD", "score_mode", "Atom cannot have a score mode")
+		}
+
+		if r.DataType == nil {
+			add("EMPTY_FIELD", "data_type", "Atom must have a data type")
+			return errs
+		}
+		if !slices.Contains(requirementDataTypes, *r.DataType) {
+			add("INVALID_DATA_TYPE", "data_type", "Field 'data_type' must be one of: %s", strings.Join(requirementDataTypes, ", "))
+			return errs
+		}
+
+		errs = append(errs, validateAtomOptions(r, path)...)
+		errs = append(errs, validateAtomUnit(r, path)...)
+		errs = append(errs, validateAtomPeriod(r, path)...)
+
+		if r.Aggregation != nil && !slices.Contains(aggregations, *r.Aggregation) {
+			add("INVALID_AGGREGATION", "aggregation", "Field 'aggregation' must be one of: %s", strings.Join(aggregations, ", "))
+			return errs
+		}
+
+		errs = append(errs, validateAtomComparison(r, path)...)
+	}
+
+	return errs
+}
+
+// validateAtomOptions checks the options of a choice, other data types cannot have them.
+func validateAtomOptions(r *dto.Requirement, path string) apperrors.ValidationErrors {
+	var errs apperrors.ValidationErrors
+
+	add := func(code string, field string, format string, args ...any) {
+		errs = append(errs, apperrors.NewValidationError(code, path+"."+field, fmt.Sprintf(format, args...)))
+	}
+
+	if *r.DataType != "choice" {
+		if r.Options != nil {
+			add("UNEXPECTED_FIELD", "options", "Only atoms with data type 'choice' can have options")
+		}
+		return errs
+	}
+
+	if len(r.Options) == 0 {
+		add("EMPTY_FIELD", "options", "Atom with data type 'choice' must have options")
+		return errs
+	}
+
+	seen := make(map[string]bool)
+	for i, option := range r.Options {
+		option = strings.TrimSpace(option)
+		if option == "" {
+			add("INVALID_VALUE", fmt.Sprintf("options[%d]", i), "Option cannot be empty")
+			continue
+		}
+		if seen[option] {
+			add("INVALID_VALUE", fmt.Sprintf("options[%d]", i), "Option '%s' is listed more than once", option)
+		}
+		seen[option] = true
+	}
+
+	return errs
+}
+
+// validateAtomUnit checks that the unit is known and that values of the atom are plain numbers.
+func validateAtomUnit(r *dto.Requirement, path string) apperrors.ValidationErrors {
+	var errs apperrors.ValidationErrors
+
+	if r.Unit == nil {
+		return errs
+	}
+
+	if *r.DataType != "int" && *r.DataType != "float" {
+		errs = append(errs, apperrors.NewValidationError("UNEXPECTED_FIELD", path+".unit", "Only atoms with data type 'int' or 'float' can have a unit"))
+		return errs
+	}
+
+	if _, ok := units.Lookup(*r.Unit); !ok {
+		errs = append(errs, apperrors.NewValidationError("INVALID_UNIT", path+".unit", fmt.Sprintf("Unit '%s' is not known", *r.Unit)))
+	}
+
+	return errs
+}
+
+func validateAtomPeriod(r *dto.Requirement, path string) apperrors.ValidationErrors {
+	var errs apperrors.ValidationErrors
+
+	add := func(code string, field string, format string, args ...any) {
+		errs = append(errs, apperrors.NewValidationError(code, path+"."+field, fmt.Sprintf(format, args...)))
+	}
+
+	if r.Period == nil {
+		if r.PeriodDays != nil {
+			add("UNEXPECTED_FIELD", "period_days", "Field 'period_days' can only be used with period 'rolling'")
+		}
+		return errs
+	}
+
+	if !slices.Contains(periods, *r.Period) {
+		add("INVALID_PERIOD", "period", "Field 'period' must be one of: %s", strings.Join(periods, ", "))
+		return errs
+	}
+
+	if *r.Period != "rolling" {
+		if r.PeriodDays != nil {
+			add("UNEXPECTED_FIELD", "period_days", "Field 'period_days' can only be used with period 'rolling'")
+		}
+		return errs
+	}
+
+	if r.PeriodDays == nil {
+		add("EMPTY_FIELD", "period_days", "Rolling period must have a length in days")
+	} else if *r.PeriodDays < 1 || *r.PeriodDays > maxPeriodDays {
+		add("INVALID_VALUE", "period_days", "Field 'period_days' must be between 1 and %d", maxPeriodDays)
+	}
+
+	return errs
+}
+
+func validateAtomComparison(r *dto.Requirement, path string) apperrors.ValidationErrors {
+	var errs apperrors.ValidationErrors
+
+	add := func(code string, field string, format string, args ...any) {
+		errs = append(errs, apperrors.NewValidationError(code, path+"."+field, fmt.Sprintf(format, args...)))
+	}
+
+	dataType := *r.DataType
+
+	if r.Aggregation != nil {
+		switch *r.Aggregation {
+		case "count":
+			// Count compares the number of entries, so the target is an integer whatever the data type is
+			dataType = "int"
+		case "sum":
+			if !slices.Contains(numericDataTypes, dataType) {
+				add("INVALID_AGGREGATION", "aggregation", "Aggregation '%s' requires data type to be one of: %s", *r.Aggregation, strings.Join(numericDataTypes, ", "))
+				return errs
+			}
+		case "max", "min":
+			if !slices.Contains(orderedDataTypes, dataType) {
+				add("INVALID_AGGREGATION", "aggregation", "Aggregation '%s' requires data type to be one of: %s", *r.Aggregation, strings.Join(orderedDataTypes, ", "))
+				return errs
+			}
+		}
+	}
+
+	// Text is satisfied by anything that was written, just like none is by anything that was logged
+	if dataType == "none" || dataType == "text" {
+		if r.Operator != nil {
+			add("UNEXPECTED_FIELD", "operator", "Atom with data type '%s' cannot have an operator", dataType)
+		}
+		if r.TargetValue != nil {
+			add("UNEXPECTED_FIELD", "target_value", "Atom with data type '%s' cannot have a target value", dataType)
+		}
+		return errs
+	}
+
+	allowed := comparisonOperators
+	if dataType == "bool" || dataType == "choice" {
+		allowed = equalityOperators
+	}
+
+	if r.Operator != nil && !slices.Contains(allowed, *r.Operator) {
+		add("INVALID_OPERATOR", "operator", "Operator for data type '%s' must be one of: %s", dataType, strings.Join(allowed, ", "))
+	}
+
+	if r.TargetValue == nil {
+		// A bool atom without a target has to be true
+		if dataType != "bool" {
+			add("EMPTY_FIELD", "target_value", "Atom with data type '%s' must have a target value", dataType)
+		}
+		return errs
+	}
+
+	// Missing options are already reported, every target would be rejected without them
+	if dataType == "choice" && len(r.Options) == 0 {
+		return errs
+	}
+
+	if err := checkValue(dataType, r.Options, *r.TargetValue); err != nil {
+		add("INVALID_VALUE", "target_value", "Target value %s", err)
+	}
+
+	return errs
+}
+
+// checkValue reports whether value can be used as a value of dataType.
+// options are the allowed values of a choice and are ignored for other data types.
+func checkValue(dataType string, options []string, value string) error {
+	value = strings.TrimSpace(value)
+
+	switch dataType {
+	case "bool":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("'%s' is not a bool, use true or false", value)
+		}
+	case "int":
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return fmt.Errorf("'%s' is not an integer", value)
+		}
+	case "float":
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("'%s' is not a number", value)
+		}
+		// ParseFloat accepts NaN and Inf, which compare as neither above nor below any target
+		if math.IsNaN(n) || math.IsInf(n, 0) {
+			return fmt.Errorf("'%s' is not a finite number", value)
+		}
+	case "duration":
+		if _, err := duration.Parse(value); err != nil {
+			return fmt.Errorf("%w, write durations like 1h30m, PT1H30M, 01:30:00, 25:30 (minutes and seconds) or 90 (minutes)", err)
+		}
+	case "choice":
+		if !slices.Contains(trimOptions(options), value) {
+			return fmt.Errorf("'%s' is not one of the options: %s", value, strings.Join(options, ", "))
+		}
+	case "rating":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < minRating || n > maxRating {
+			return fmt.Errorf("'%s' is not a rating, use a whole number from %d to %d", value, minRating, maxRating)
+		}
+	case "time":
+		if _, err := timeofday.Parse(value); err != nil {
+			return fmt.Errorf("'%s' is not a time of day, use HH:MM or HH:MM:SS", value)
+		}
+	case "none", "text":
+	default:
+		return fmt.Errorf("unknown data type '%s'", dataType)
+	}
+
+	return nil
+}
+
+func trimOptions(options []string) []string {
+	trimmed := make([]string, len(options))
+	for i, option := range options {
+		trimmed[i] = strings.TrimSpace(option)
+	}
+	return trimmed
+}
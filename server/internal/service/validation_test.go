@@ -0,0 +1,91 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/boreymarf/task-fuss/server/internal/dto"
+)
+
+func TestValidateRequirement(t *testing.T) {
+	valid := atom(0, "int", "", ">=", "50")
+
+	withPeriod := func(r dto.Requirement, period string, days *int) dto.Requirement {
+		r.Period = &period
+		r.PeriodDays = days
+		return r
+	}
+	withUnit := func(r dto.Requirement, unit string) dto.Requirement {
+		r.Unit = &unit
+		return r
+	}
+	withOptions := func(r dto.Requirement, options ...string) dto.Requirement {
+		r.Options = options
+		return r
+	}
+
+	untitled := valid
+	untitled.Title = " "
+	orWithScoreMode := condition(0, "or", valid)
+	orWithScoreMode.ScoreMode = ptr("min")
+
+	tests := []struct {
+		name        string
+		requirement dto.Requirement
+		// want are the codes and fields of the errors in the order they are reported
+		want []string
+	}{
+		{name: "valid atom", requirement: valid},
+		{name: "valid tree", requirement: condition(0, "and", valid, condition(0, "not", atom(0, "bool", "", "", "")), atom(0, "none", "count", "<=", "2"))},
+		{name: "valid rolling sum", requirement: withPeriod(atom(0, "float", "sum", ">=", "20"), "rolling", ptr(7))},
+		{name: "valid choice", requirement: withOptions(atom(0, "choice", "", "==", "good"), "bad", "good")},
+
+		{name: "empty condition", requirement: condition(0, "and"), want: []string{"EMPTY_FIELD requirement.operands"}},
+		{name: "condition without operator", requirement: dto.Requirement{Type: "condition", Operands: []dto.Requirement{valid}}, want: []string{"EMPTY_FIELD requirement.operator"}},
+		{name: "unknown condition", requirement: condition(0, "xor", valid), want: []string{"INVALID_OPERATOR requirement.operator"}},
+		{name: "not with two operands", requirement: condition(0, "not", valid, valid), want: []string{"INVALID_OPERANDS requirement.operands"}},
+		{name: "score mode of or", requirement: orWithScoreMode, want: []string{"UNEXPECTED_FIELD requirement.score_mode"}},
+		{name: "unknown type", requirement: dto.Requirement{Type: "group"}, want: []string{"INVALID_TYPE requirement.type"}},
+
+		{name: "bool with >=", requirement: atom(0, "bool", "", ">=", "true"), want: []string{"INVALID_OPERATOR requirement.operator"}},
+		{name: "abc for an int", requirement: atom(0, "int", "", ">=", "abc"), want: []string{"INVALID_VALUE requirement.target_value"}},
+		{name: "fraction for an int", requirement: atom(0, "int", "", ">=", "2.5"), want: []string{"INVALID_VALUE requirement.target_value"}},
+		{name: "NaN", requirement: atom(0, "float", "", "<=", "NaN"), want: []string{"INVALID_VALUE requirement.target_value"}},
+		{name: "Inf", requirement: atom(0, "float", "", "<=", "Inf"), want: []string{"INVALID_VALUE requirement.target_value"}},
+		{name: "negative Inf", requirement: atom(0, "float", "", ">=", "-inf"), want: []string{"INVALID_VALUE requirement.target_value"}},
+		{name: "duration", requirement: atom(0, "duration", "", ">=", "5 parsecs"), want: []string{"INVALID_VALUE requirement.target_value"}},
+		{name: "time of day", requirement: atom(0, "time", "", "<=", "25:00"), want: []string{"INVALID_VALUE requirement.target_value"}},
+		{name: "rating", requirement: atom(0, "rating", "", ">=", "6"), want: []string{"INVALID_VALUE requirement.target_value"}},
+		{name: "choice not among the options", requirement: withOptions(atom(0, "choice", "", "==", "great"), "bad", "good"), want: []string{"INVALID_VALUE requirement.target_value"}},
+		{name: "option listed twice", requirement: withOptions(atom(0, "choice", "", "==", "good"), "good", " good"), want: []string{"INVALID_VALUE requirement.options[1]"}},
+		{name: "no target", requirement: atom(0, "int", "", ">=", ""), want: []string{"EMPTY_FIELD requirement.target_value"}},
+		{name: "no data type", requirement: atom(0, "", "", "", ""), want: []string{"EMPTY_FIELD requirement.data_type"}},
+		{name: "no title", requirement: untitled, want: []string{"EMPTY_FIELD requirement.title"}},
+		{name: "operator of none", requirement: atom(0, "none", "", "==", ""), want: []string{"UNEXPECTED_FIELD requirement.operator"}},
+		{name: "sum of bools", requirement: atom(0, "bool", "sum", ">=", "3"), want: []string{"INVALID_AGGREGATION requirement.aggregation"}},
+		{name: "fractional count", requirement: atom(0, "none", "count", "<=", "2.5"), want: []string{"INVALID_VALUE requirement.target_value"}},
+		{name: "rolling period without length", requirement: withPeriod(valid, "rolling", nil), want: []string{"EMPTY_FIELD requirement.period_days"}},
+		{name: "length of a weekly period", requirement: withPeriod(valid, "week", ptr(7)), want: []string{"UNEXPECTED_FIELD requirement.period_days"}},
+		{name: "unit of a bool", requirement: withUnit(atom(0, "bool", "", "", ""), "km"), want: []string{"UNEXPECTED_FIELD requirement.unit"}},
+		{name: "unknown unit", requirement: withUnit(valid, "parsecs"), want: []string{"INVALID_UNIT requirement.unit"}},
+
+		{name: "nested", requirement: condition(0, "and", valid, condition(0, "or", atom(0, "int", "", ">=", "abc"))), want: []string{"INVALID_VALUE requirement.operands[1].operands[0].target_value"}},
+		{name: "every problem at once", requirement: condition(0, "and", atom(0, "bool", "", ">=", "true"), valid, atom(0, "float", "", "<=", "NaN")), want: []string{
+			"INVALID_OPERATOR requirement.operands[0].operator",
+			"INVALID_VALUE requirement.operands[2].target_value",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, err := range validateRequirement(&tt.requirement, "requirement") {
+				got = append(got, fmt.Sprintf("%s %s", err.Code, err.Field))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("errors = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+// Package timeofday reads and writes times of day, "07:30" or "07:30:15", as the time passed since midnight.
+package timeofday
+
+import (
+	"strings"
+	"time"
+)
+
+// Parse reads "HH:MM" or "HH:MM:SS", hours may have a single digit.
+func Parse(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+
+	layout := "15:04"
+	if strings.Count(s, ":") == 2 {
+		layout = "15:04:05"
+	}
+
+	t, err := time.Parse(layout, s)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second, nil
+}
+
+// Format is the inverse of Parse, it writes "HH:MM" and adds seconds only when there are any.
+func Format(d time.Duration) string {
+	t := time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC).Add(d)
+	if t.Second() != 0 {
+		return t.Format("15:04:05")
+	}
+	return t.Format("15:04")
+}
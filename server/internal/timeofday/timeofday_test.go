@@ -0,0 +1,76 @@
+package timeofday
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"07:00", 7 * time.Hour},
+		{"7:00", 7 * time.Hour},
+		{"00:00", 0},
+		{"23:59", 23*time.Hour + 59*time.Minute},
+		{"06:30:15", 6*time.Hour + 30*time.Minute + 15*time.Second},
+		{" 22:15 ", 22*time.Hour + 15*time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Parse(tt.in)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"7",
+		"24:00",
+		"25:00",
+		"07:60",
+		"07:00:60",
+		"07:00:00:00",
+		"7am",
+	}
+
+	for _, in := range tests {
+		t.Run(in, func(t *testing.T) {
+			if got, err := Parse(in); err == nil {
+				t.Errorf("Parse(%q) = %v, want error", in, got)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want string
+	}{
+		{0, "00:00"},
+		{7 * time.Hour, "07:00"},
+		{6*time.Hour + 30*time.Minute + 15*time.Second, "06:30:15"},
+		{23*time.Hour + 59*time.Minute, "23:59"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := Format(tt.in); got != tt.want {
+				t.Errorf("Format(%v) = %q, want %q", tt.in, got, tt.want)
+			}
+			if back, err := Parse(tt.want); err != nil || back != tt.in {
+				t.Errorf("Parse(%q) = %v, %v, want %v", tt.want, back, err, tt.in)
+			}
+		})
+	}
+}
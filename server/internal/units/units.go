@@ -0,0 +1,151 @@
+// Package units converts numeric values between units of measure.
+//
+// Every unit belongs to a dimension and is defined by its size in the base unit
+// of that dimension, so any two units of the same dimension can be converted:
+//
+//	distance  m, km, cm, mm, mi, yd, ft, in
+//	mass      g, kg, mg, lb, oz, st
+//	volume    ml, l, cl, dl, gal, qt, pt, cup, fl_oz (US customary)
+//	energy    J, kJ, cal, kcal
+//	duration  s, min, h, d
+//
+// Lookups are case-insensitive and accept a few common spellings like "lbs" or "miles".
+package units
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+)
+
+type Dimension string
+
+const (
+	Distance Dimension = "distance"
+	Mass     Dimension = "mass"
+	Volume   Dimension = "volume"
+	Energy   Dimension = "energy"
+	Duration Dimension = "duration"
+)
+
+type Unit struct {
+	// Symbol is the canonical name of the unit, it is what gets stored
+	Symbol    string
+	Dimension Dimension
+	// size of the unit in the base unit of the dimension
+	size float64
+}
+
+var all = []Unit{
+	{"m", Distance, 1},
+	{"km", Distance, 1000},
+	{"cm", Distance, 0.01},
+	{"mm", Distance, 0.001},
+	{"mi", Distance, 1609.344},
+	{"yd", Distance, 0.9144},
+	{"ft", Distance, 0.3048},
+	{"in", Distance, 0.0254},
+
+	{"g", Mass, 1},
+	{"kg", Mass, 1000},
+	{"mg", Mass, 0.001},
+	{"lb", Mass, 453.59237},
+	{"oz", Mass, 28.349523125},
+	{"st", Mass, 6350.29318},
+
+	{"ml", Volume, 1},
+	{"l", Volume, 1000},
+	{"cl", Volume, 10},
+	{"dl", Volume, 100},
+	{"gal", Volume, 3785.411784},
+	{"qt", Volume, 946.352946},
+	{"pt", Volume, 473.176473},
+	{"cup", Volume, 236.5882365},
+	{"fl_oz", Volume, 29.5735295625},
+
+	{"J", Energy, 1},
+	{"kJ", Energy, 1000},
+	{"cal", Energy, 4.184},
+	{"kcal", Energy, 4184},
+
+	{"s", Duration, 1},
+	{"min", Duration, 60},
+	{"h", Duration, 3600},
+	{"d", Duration, 86400},
+}
+
+var aliases = map[string]string{
+	"meter": "m", "meters": "m", "metre": "m", "metres": "m",
+	"kilometer": "km", "kilometers": "km", "kilometre": "km", "kilometres": "km",
+	"mile": "mi", "miles": "mi",
+	"yard": "yd", "yards": "yd",
+	"foot": "ft", "feet": "ft",
+	"inch": "in", "inches": "in",
+	"gram": "g", "grams": "g",
+	"kilogram": "kg", "kilograms": "kg", "kgs": "kg",
+	"lbs": "lb", "pound": "lb", "pounds": "lb",
+	"ounce": "oz", "ounces": "oz",
+	"stone": "st",
+	"liter": "l", "liters": "l", "litre": "l", "litres": "l",
+	"gallon": "gal", "gallons": "gal",
+	"cups": "cup",
+	"floz": "fl_oz",
+	"sec":  "s", "secs": "s", "second": "s", "seconds": "s",
+	"mins": "min", "minute": "min", "minutes": "min",
+	"hr": "h", "hrs": "h", "hour": "h", "hours": "h",
+	"day": "d", "days": "d",
+}
+
+var bySymbol = make(map[string]Unit)
+
+func init() {
+	for _, u := range all {
+		bySymbol[strings.ToLower(u.Symbol)] = u
+	}
+}
+
+// Lookup finds a unit by its symbol or one of its spellings.
+func Lookup(name string) (Unit, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if symbol, ok := aliases[name]; ok {
+		name = strings.ToLower(symbol)
+	}
+
+	u, ok := bySymbol[name]
+	return u, ok
+}
+
+// Symbols returns the canonical symbols of every unit of the dimension.
+func Symbols(dimension Dimension) []string {
+	var symbols []string
+	for _, u := range all {
+		if u.Dimension == dimension {
+			symbols = append(symbols, u.Symbol)
+		}
+	}
+	return symbols
+}
+
+// Convert returns value measured in from as measured in to.
+// The result is rounded to nine decimal places so that 1 km is exactly 1000 m again.
+func Convert(value float64, from Unit, to Unit) (float64, error) {
+	if from.Dimension != to.Dimension {
+		return 0, fmt.Errorf("%s is a unit of %s and %s is a unit of %s", from.Symbol, from.Dimension, to.Symbol, to.Dimension)
+	}
+
+	converted := value * from.size / to.size
+	return math.Round(converted*1e9) / 1e9, nil
+}
+
+// Split separates a trailing unit from a number, "3.1 mi" and "3.1mi" both give "3.1" and "mi".
+// The unit is empty when there is none.
+func Split(s string) (string, string) {
+	s = strings.TrimSpace(s)
+
+	i := strings.LastIndexFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '_'
+	})
+
+	return strings.TrimSpace(s[:i+1]), s[i+1:]
+}
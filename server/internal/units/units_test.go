@@ -0,0 +1,154 @@
+package units
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name      string
+		symbol    string
+		dimension Dimension
+	}{
+		{"km", "km", Distance},
+		{"KM", "km", Distance},
+		{" mi ", "mi", Distance},
+		{"miles", "mi", Distance},
+		{"feet", "ft", Distance},
+		{"lbs", "lb", Mass},
+		{"Pounds", "lb", Mass},
+		{"kgs", "kg", Mass},
+		{"floz", "fl_oz", Volume},
+		{"litres", "l", Volume},
+		{"kJ", "kJ", Energy},
+		{"kj", "kJ", Energy},
+		{"KCAL", "kcal", Energy},
+		{"min", "min", Duration},
+		{"minutes", "min", Duration},
+		{"hrs", "h", Duration},
+		{"days", "d", Duration},
+		// "m" is meters, minutes are "min"
+		{"m", "m", Distance},
+		// Lookups ignore case, so "MM" is millimeters as well
+		{"MM", "mm", Distance},
+		{"oz", "oz", Mass},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, ok := Lookup(tt.name)
+			if !ok {
+				t.Fatalf("Lookup(%q) found nothing", tt.name)
+			}
+			if u.Symbol != tt.symbol || u.Dimension != tt.dimension {
+				t.Errorf("Lookup(%q) = %s (%s), want %s (%s)", tt.name, u.Symbol, u.Dimension, tt.symbol, tt.dimension)
+			}
+		})
+	}
+}
+
+func TestLookupUnknown(t *testing.T) {
+	for _, name := range []string{"", "parsecs", "fl oz", "minute s", "ms", "kilo"} {
+		if u, ok := Lookup(name); ok {
+			t.Errorf("Lookup(%q) = %s, want nothing", name, u.Symbol)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		value float64
+		from  string
+		to    string
+		want  float64
+	}{
+		{1, "km", "m", 1000},
+		{1000, "m", "km", 1},
+		{1, "mi", "km", 1.609344},
+		{3.1, "mi", "m", 4988.9664},
+		{1, "lb", "g", 453.59237},
+		{1, "st", "lb", 14},
+		{1, "gal", "qt", 4},
+		{2, "cup", "pt", 1},
+		{1, "kcal", "kJ", 4.184},
+		{90, "min", "h", 1.5},
+		{1, "d", "s", 86400},
+		{0.1, "km", "m", 100},
+		{5, "km", "km", 5},
+	}
+
+	for _, tt := range tests {
+		from, _ := Lookup(tt.from)
+		to, _ := Lookup(tt.to)
+
+		got, err := Convert(tt.value, from, to)
+		if err != nil {
+			t.Errorf("Convert(%v, %s, %s) returned error: %v", tt.value, tt.from, tt.to, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Convert(%v, %s, %s) = %v, want %v", tt.value, tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestConvertAcrossDimensions(t *testing.T) {
+	tests := [][2]string{
+		{"km", "kg"},
+		{"min", "m"},
+		{"l", "lb"},
+	}
+
+	for _, tt := range tests {
+		from, _ := Lookup(tt[0])
+		to, _ := Lookup(tt[1])
+		if _, err := Convert(1, from, to); err == nil {
+			t.Errorf("Convert(1, %s, %s) returned no error", tt[0], tt[1])
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		in     string
+		number string
+		unit   string
+	}{
+		{"3.1 mi", "3.1", "mi"},
+		{"3.1mi", "3.1", "mi"},
+		{"  5 km  ", "5", "km"},
+		{"12 fl_oz", "12", "fl_oz"},
+		{"42", "42", ""},
+		{"-2.5 kg", "-2.5", "kg"},
+		{"1e3 m", "1e3", "m"},
+		// Only the trailing letters are the unit, "1e3" alone ends in a digit
+		{"1e3", "1e3", ""},
+		{"km", "", "km"},
+		{"", "", ""},
+	}
+
+	for _, tt := range tests {
+		number, unit := Split(tt.in)
+		if number != tt.number || unit != tt.unit {
+			t.Errorf("Split(%q) = %q, %q, want %q, %q", tt.in, number, unit, tt.number, tt.unit)
+		}
+	}
+}
+
+func TestSymbols(t *testing.T) {
+	tests := []struct {
+		dimension Dimension
+		want      []string
+	}{
+		{Distance, []string{"m", "km", "cm", "mm", "mi", "yd", "ft", "in"}},
+		{Duration, []string{"s", "min", "h", "d"}},
+		{Dimension("speed"), nil},
+	}
+
+	for _, tt := range tests {
+		if got := Symbols(tt.dimension); !slices.Equal(got, tt.want) {
+			t.Errorf("Symbols(%s) = %v, want %v", tt.dimension, got, tt.want)
+		}
+	}
+}
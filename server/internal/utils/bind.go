@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 
 	"github.com/boreymarf/task-fuss/server/internal/api"
 	"github.com/boreymarf/task-fuss/server/internal/dto"
@@ -125,6 +126,12 @@ func HandleBindingError(c *gin.Context, err error) {
 					Code:    "MAX",
 					Message: fmt.Sprintf("Field %s should be shorter than %s characters", fieldError.Field(), fieldError.Param()),
 				})
+			case "oneof":
+				response.Details = append(response.Details, dto.FieldError{
+					Field:   fieldError.Field(),
+					Code:    "INVALID_VALUE",
+					Message: fmt.Sprintf("Field %s must be one of: %s", fieldError.Field(), strings.ReplaceAll(fieldError.Param(), " ", ", ")),
+				})
 			case "type":
 				logger.Log.Info().
 					Str("ip", c.ClientIP()).